@@ -26,14 +26,27 @@ O OI lê o arquivo oi.json e garante que a realidade do servidor
 		Version: version,
 	}
 
+	// Flags globais (runtime e proxy)
+	cli.AddGlobalFlags(rootCmd)
+
 	// Adiciona comandos
 	rootCmd.AddCommand(cli.NewUpCommand())
+	rootCmd.AddCommand(cli.NewPlanCommand())
 	rootCmd.AddCommand(cli.NewDownCommand())
 	rootCmd.AddCommand(cli.NewStatusCommand())
+	rootCmd.AddCommand(cli.NewOutdatedCommand())
+	rootCmd.AddCommand(cli.NewWatchImagesCommand())
 	rootCmd.AddCommand(cli.NewStopCommand())
 	rootCmd.AddCommand(cli.NewStartCommand())
+	rootCmd.AddCommand(cli.NewRollbackCommand())
+	rootCmd.AddCommand(cli.NewPromoteCommand())
+	rootCmd.AddCommand(cli.NewAbortCommand())
+	rootCmd.AddCommand(cli.NewHistoryCommand())
+	rootCmd.AddCommand(cli.NewVolumesCommand())
+	rootCmd.AddCommand(cli.NewLoginCommand())
 	rootCmd.AddCommand(cli.NewLogsCommand())
 	rootCmd.AddCommand(cli.NewLogCommand())
+	rootCmd.AddCommand(cli.NewProxyCommand())
 	rootCmd.AddCommand(cli.NewInfoCommand(version))
 	rootCmd.AddCommand(cli.NewUpdateCommand(version))
 	rootCmd.AddCommand(newInitCommand())
@@ -99,9 +112,25 @@ func newInitCommand() *cobra.Command {
 				nome = args[0]
 			}
 
+			// Com Dockerfile, 'oi up' constrói a imagem localmente (origem vira a tag)
+			build := ""
+			if dockerfile != "" {
+				// O Dockerfile é relativo ao contexto (diretório do oi.json)
+				if cwd, err := os.Getwd(); err == nil && filepath.IsAbs(dockerfile) {
+					if rel, err := filepath.Rel(cwd, dockerfile); err == nil {
+						dockerfile = rel
+					}
+				}
+				build = fmt.Sprintf(`
+  "build": {
+    "context": ".",
+    "dockerfile": "%s"
+  },`, filepath.ToSlash(dockerfile))
+			}
+
 			template := fmt.Sprintf(`{
   "nome": "%s",
-  "origem": "%s",
+  "origem": "%s",%s
   "dominio": "%s.localhost",
   "porta": %d,
   "recursos": {
@@ -109,7 +138,7 @@ func newInitCommand() *cobra.Command {
     "memoria": "256mb"
   }
 }
-`, nome, origem, nome, porta)
+`, nome, origem, build, nome, porta)
 
 			if err := os.WriteFile("oi.json", []byte(template), 0644); err != nil {
 				return fmt.Errorf("❌ Erro ao criar oi.json: %w", err)
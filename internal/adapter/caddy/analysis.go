@@ -0,0 +1,242 @@
+package caddy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// Nomes usados na configuração de logging do Caddy
+const (
+	accessLoggerName = "oi"
+	accessLogName    = "oi-access"
+	upstreamLogKey   = "upstream"
+)
+
+// upstreamLogHandler acrescenta ao access log o upstream que atendeu a requisição
+// O placeholder é resolvido depois do reverse_proxy (requer Caddy 2.8+)
+func upstreamLogHandler() handleConfig {
+	return handleConfig{
+		Handler: "log_append",
+		Key:     upstreamLogKey,
+		Value:   "{http.reverse_proxy.upstream.hostport}",
+	}
+}
+
+// serverLogs é a configuração de access log de um servidor HTTP do Caddy
+type serverLogs struct {
+	DefaultLoggerName string `json:"default_logger_name,omitempty"`
+}
+
+// EnableTrafficLog liga o access log do servidor gerenciado e o grava em m.accessLog
+// Se o servidor já tiver um logger de acesso, ele é reaproveitado; o arquivo do OI
+// recebe uma cópia das entradas sem alterar os destinos já configurados
+func (m *Manager) EnableTrafficLog(ctx context.Context) error {
+	if err := m.ensureServer(ctx); err != nil {
+		return err
+	}
+
+	// 1. Access log do servidor (logs.default_logger_name)
+	loggerName := accessLoggerName
+	status, body, err := m.do(ctx, http.MethodGet, m.serverPath()+"/logs", nil)
+	if err != nil {
+		return err
+	}
+	var logs serverLogs
+	if status < 400 {
+		if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && string(trimmed) != "null" {
+			if err := json.Unmarshal(trimmed, &logs); err != nil {
+				return fmt.Errorf("falha ao parsear logs do servidor: %w", err)
+			}
+		}
+	}
+	if logs.DefaultLoggerName != "" {
+		loggerName = logs.DefaultLoggerName
+	} else if err := m.set(ctx, m.serverPath()+"/logs", []string{"default_logger_name"}, loggerName); err != nil {
+		return fmt.Errorf("falha ao habilitar access log: %w", err)
+	}
+
+	// 2. Logger que grava as entradas de acesso em JSON no arquivo lido pelo OI
+	logger := map[string]interface{}{
+		"writer":  map[string]string{"output": "file", "filename": m.accessLog},
+		"encoder": map[string]string{"format": "json"},
+		"include": []string{"http.log.access." + loggerName},
+	}
+	if err := m.set(ctx, "/config/logging", []string{"logs", accessLogName}, logger); err != nil {
+		return fmt.Errorf("falha ao configurar access log em %s: %w", m.accessLog, err)
+	}
+	return nil
+}
+
+// set grava value em base/keys..., criando os níveis intermediários ausentes
+// POST na Admin API cria ou substitui chaves de objetos
+func (m *Manager) set(ctx context.Context, base string, keys []string, value interface{}) error {
+	path, value, err := m.wrapMissing(ctx, base, keys, value)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("falha ao serializar configuração: %w", err)
+	}
+	status, respBody, err := m.do(ctx, http.MethodPost, path, body)
+	if err != nil {
+		return err
+	}
+	if status >= 400 {
+		return fmt.Errorf("Caddy retornou erro %d: %s", status, respBody)
+	}
+	return nil
+}
+
+// accessEntry são os campos usados de uma entrada do access log do Caddy
+type accessEntry struct {
+	TS      float64 `json:"ts"`
+	Request struct {
+		Host string `json:"host"`
+	} `json:"request"`
+	// Duration é a latência em segundos
+	Duration float64 `json:"duration"`
+	Status   int     `json:"status"`
+	Upstream string  `json:"upstream"`
+}
+
+// TrafficStats lê o access log e resume as requisições aos hosts atendidas pelos upstreams
+// Entradas anteriores a since, de outros hosts ou sem upstream (ex: rotas sem
+// canário) são ignoradas. A leitura é incremental: cada chamada só lê as linhas gravadas
+// desde a anterior (ver trafficLog)
+func (m *Manager) TrafficStats(ctx context.Context, hosts []string, upstreams []string, since time.Time) (domain.TrafficStats, error) {
+	wanted := make(map[string]bool, len(upstreams))
+	for _, u := range upstreams {
+		wanted[u] = true
+	}
+
+	var statuses []int
+	var latencies []time.Duration
+	err := m.traffic.read(ctx, m.accessLog, float64(since.UnixNano())/1e9, func(entry accessEntry) {
+		if !wanted[entry.Upstream] || !matchAnyHost(hosts, hostOnly(entry.Request.Host)) {
+			return
+		}
+		statuses = append(statuses, entry.Status)
+		latencies = append(latencies, time.Duration(math.Round(entry.Duration*float64(time.Second))))
+	})
+	if err != nil {
+		return domain.TrafficStats{}, err
+	}
+	return domain.NewTrafficStats(statuses, latencies), nil
+}
+
+// trafficLog guarda a leitura do access log entre chamadas de TrafficStats, para a
+// análise do canário não reler o arquivo inteiro a cada verificação
+type trafficLog struct {
+	mu sync.Mutex
+	// file identifica o arquivo lido: outro arquivo no caminho (rotação) ou um arquivo
+	// menor que offset (truncado) fazem a leitura recomeçar do início
+	file   os.FileInfo
+	offset int64
+	// entries são as entradas com upstream a partir de since (em segundos)
+	since   float64
+	entries []accessEntry
+}
+
+// read acrescenta as linhas completas gravadas desde a última leitura e chama visit
+// para cada entrada a partir de since
+// Um since anterior ao da leitura guardada recomeça do início; um posterior descarta
+// as entradas que ficaram de fora
+func (t *trafficLog) read(ctx context.Context, path string, since float64, visit func(accessEntry)) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		// Nenhuma requisição registrada ainda
+		t.file, t.offset, t.entries = nil, 0, nil
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("falha ao abrir access log (verifique permissões ou 'caddy.access_log' em ~/.oi/config.json): %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("falha ao ler access log: %w", err)
+	}
+	if t.file == nil || !os.SameFile(t.file, info) || info.Size() < t.offset || since < t.since {
+		t.offset, t.entries = 0, nil
+	}
+	t.file, t.since = info, since
+
+	kept := t.entries[:0]
+	for _, entry := range t.entries {
+		if entry.TS >= since {
+			kept = append(kept, entry)
+		}
+	}
+	t.entries = kept
+
+	if _, err := file.Seek(t.offset, io.SeekStart); err != nil {
+		return fmt.Errorf("falha ao ler access log: %w", err)
+	}
+	reader := bufio.NewReaderSize(file, 64*1024)
+	marker := []byte(`"` + upstreamLogKey + `":`)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// Uma linha sem "\n" ainda está sendo gravada: fica para a próxima leitura
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("falha ao ler access log: %w", err)
+		}
+		t.offset += int64(len(line))
+
+		if !bytes.Contains(line, marker) {
+			continue
+		}
+		var entry accessEntry
+		if err := json.Unmarshal(line, &entry); err != nil || entry.TS < since {
+			continue
+		}
+		t.entries = append(t.entries, entry)
+	}
+
+	for _, entry := range t.entries {
+		visit(entry)
+	}
+	return nil
+}
+
+// matchAnyHost verifica se o host da requisição casa com algum dos hosts (exatos ou curingas)
+func matchAnyHost(hosts []string, host string) bool {
+	for _, h := range hosts {
+		if domain.MatchHost(h, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// hostOnly remove a porta do Host da requisição, se houver
+func hostOnly(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return host
+}
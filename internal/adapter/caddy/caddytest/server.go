@@ -0,0 +1,356 @@
+// Package caddytest emula a Admin API do Caddy em memória, para testar o adapter sem um Caddy real
+package caddytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Server é uma Admin API do Caddy (httptest) que guarda a configuração em memória
+// Segue a semântica do Caddy para /config/<caminho> e /id/<@id>/<caminho>:
+//   - GET retorna o valor (null se a chave não existir no objeto)
+//   - POST cria ou substitui a chave; em um array, acrescenta no fim
+//   - PUT cria a chave (erro se existir); em um índice de array, insere na posição
+//   - PATCH substitui um valor existente (erro se não existir)
+//   - DELETE remove um valor existente
+//
+// Caminhos que atravessam chaves inexistentes, índices inválidos e @id duplicados
+// respondem 400; @id desconhecido responde 404, como no Caddy
+type Server struct {
+	// URL é o endereço da Admin API (ex: "http://127.0.0.1:41234")
+	URL string
+
+	srv       *httptest.Server
+	mu        sync.Mutex
+	config    interface{}
+	overrides map[string]response
+	requests  []string
+}
+
+type response struct {
+	status int
+	body   string
+}
+
+// NewServer inicia a Admin API emulada com a configuração vazia
+// O servidor é encerrado ao fim do teste
+func NewServer(t testing.TB) *Server {
+	s := &Server{overrides: make(map[string]response)}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.handle))
+	s.URL = s.srv.URL
+	t.Cleanup(s.srv.Close)
+	return s
+}
+
+// Close encerra o servidor (requisições seguintes falham na conexão)
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Load substitui a configuração inteira pelo JSON informado
+func (s *Server) Load(t testing.TB, config string) {
+	t.Helper()
+	var value interface{}
+	if err := json.Unmarshal([]byte(config), &value); err != nil {
+		t.Fatalf("configuração inválida: %v", err)
+	}
+	if _, err := index(value); err != nil {
+		t.Fatalf("configuração inválida: %v", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config = value
+}
+
+// Get retorna o JSON do valor em path (relativo a /config, ex: "apps/http/servers/srv0/routes")
+// Retorna "null" se o caminho não existir
+func (s *Server) Get(path string) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, err := apply(&s.config, splitPath(path), http.MethodGet, nil)
+	if err != nil {
+		return []byte("null")
+	}
+	data, _ := json.Marshal(value)
+	return data
+}
+
+// Override faz as requisições method+path (ex: "GET", "/config/apps/http/servers/srv0/routes")
+// responderem status e body sem tocar na configuração (erros e respostas malformadas)
+func (s *Server) Override(method, path string, status int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.overrides[method+" "+path] = response{status: status, body: body}
+}
+
+// ClearOverrides remove as respostas definidas com Override
+func (s *Server) ClearOverrides() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.overrides = make(map[string]response)
+}
+
+// Requests retorna as requisições recebidas ("MÉTODO caminho"), em ordem
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// apiError é um erro da Admin API com o status HTTP correspondente
+type apiError struct {
+	status int
+	msg    string
+}
+
+func (e *apiError) Error() string {
+	return e.msg
+}
+
+func badRequest(format string, args ...interface{}) *apiError {
+	return &apiError{status: http.StatusBadRequest, msg: fmt.Sprintf(format, args...)}
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+	if resp, ok := s.overrides[r.Method+" "+r.URL.Path]; ok {
+		w.WriteHeader(resp.status)
+		io.WriteString(w, resp.body)
+		return
+	}
+
+	value, err := s.serve(r)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(err.status)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.msg})
+		return
+	}
+	if r.Method == http.MethodGet {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(value)
+	}
+}
+
+// serve resolve o caminho e aplica o método na configuração; s.mu deve estar travado
+func (s *Server) serve(r *http.Request) (interface{}, *apiError) {
+	var parts []string
+	switch {
+	case r.URL.Path == "/config" || strings.HasPrefix(r.URL.Path, "/config/"):
+		parts = splitPath(strings.TrimPrefix(r.URL.Path, "/config"))
+	case strings.HasPrefix(r.URL.Path, "/id/"):
+		rest := splitPath(strings.TrimPrefix(r.URL.Path, "/id/"))
+		if len(rest) == 0 {
+			return nil, badRequest("request path is missing object ID")
+		}
+		ids, err := index(s.config)
+		if err != nil {
+			return nil, err
+		}
+		base, ok := ids[rest[0]]
+		if !ok {
+			return nil, &apiError{status: http.StatusNotFound, msg: fmt.Sprintf("unknown object ID '%s'", rest[0])}
+		}
+		parts = append(append([]string(nil), base...), rest[1:]...)
+	default:
+		return nil, &apiError{status: http.StatusNotFound, msg: "not found"}
+	}
+
+	var body interface{}
+	switch r.Method {
+	case http.MethodGet, http.MethodDelete:
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		if ct := r.Header.Get("Content-Type"); !strings.Contains(ct, "/json") {
+			return nil, badRequest("unacceptable content-type: %v; 'application/json' required", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			return nil, badRequest("decoding request body: %v", err)
+		}
+	default:
+		return nil, &apiError{status: http.StatusMethodNotAllowed, msg: "method not allowed"}
+	}
+
+	if r.Method == http.MethodGet {
+		return apply(&s.config, parts, r.Method, nil)
+	}
+
+	// Alterações valem só se a configuração resultante for válida (sem @id duplicado)
+	previous := deepCopy(s.config)
+	if _, err := apply(&s.config, parts, r.Method, body); err != nil {
+		s.config = previous
+		return nil, err
+	}
+	if _, err := index(s.config); err != nil {
+		s.config = previous
+		return nil, err
+	}
+	return nil, nil
+}
+
+// apply executa o método no caminho parts a partir de *ptr
+func apply(ptr *interface{}, parts []string, method string, value interface{}) (interface{}, *apiError) {
+	if len(parts) == 0 {
+		switch method {
+		case http.MethodGet:
+			return *ptr, nil
+		case http.MethodPost:
+			*ptr = value
+		case http.MethodPut:
+			if *ptr != nil {
+				return nil, badRequest("[/config/] key already exists")
+			}
+			*ptr = value
+		case http.MethodPatch:
+			if *ptr == nil {
+				return nil, badRequest("[/config/] key does not exist")
+			}
+			*ptr = value
+		case http.MethodDelete:
+			*ptr = nil
+		}
+		return nil, nil
+	}
+
+	part, last := parts[0], len(parts) == 1
+	switch v := (*ptr).(type) {
+	case map[string]interface{}:
+		child, exists := v[part]
+		if !last {
+			if !exists || child == nil {
+				return nil, badRequest("invalid traversal path at: %s", part)
+			}
+			result, err := apply(&child, parts[1:], method, value)
+			v[part] = child
+			return result, err
+		}
+		switch method {
+		case http.MethodGet:
+			return child, nil
+		case http.MethodPost:
+			if arr, ok := child.([]interface{}); ok {
+				v[part] = append(arr, value)
+			} else {
+				v[part] = value
+			}
+		case http.MethodPut:
+			if exists {
+				return nil, badRequest("[%s] key already exists: %s", part, part)
+			}
+			v[part] = value
+		case http.MethodPatch:
+			if !exists {
+				return nil, badRequest("[%s] key does not exist: %s", part, part)
+			}
+			v[part] = value
+		case http.MethodDelete:
+			if !exists {
+				return nil, badRequest("[%s] key does not exist: %s", part, part)
+			}
+			delete(v, part)
+		}
+		return nil, nil
+
+	case []interface{}:
+		i, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, badRequest("invalid array index '%s': %v", part, err)
+		}
+		// PUT pode inserir logo após o último elemento
+		if i < 0 || i > len(v) || (i == len(v) && !(last && method == http.MethodPut)) {
+			return nil, badRequest("array index out of bounds: %s", part)
+		}
+		if !last {
+			child := v[i]
+			result, err := apply(&child, parts[1:], method, value)
+			v[i] = child
+			return result, err
+		}
+		switch method {
+		case http.MethodGet:
+			return v[i], nil
+		case http.MethodPost:
+			if arr, ok := v[i].([]interface{}); ok {
+				v[i] = append(arr, value)
+			} else {
+				v[i] = value
+			}
+		case http.MethodPut:
+			inserted := append(append(append([]interface{}(nil), v[:i]...), value), v[i:]...)
+			*ptr = inserted
+		case http.MethodPatch:
+			v[i] = value
+		case http.MethodDelete:
+			*ptr = append(append([]interface{}(nil), v[:i]...), v[i+1:]...)
+		}
+		return nil, nil
+
+	default:
+		return nil, badRequest("invalid traversal path at: %s", part)
+	}
+}
+
+// index mapeia cada @id para o caminho do objeto que o declara
+func index(config interface{}) (map[string][]string, *apiError) {
+	ids := make(map[string][]string)
+	var walk func(value interface{}, path []string) *apiError
+	walk = func(value interface{}, path []string) *apiError {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if id, ok := v["@id"].(string); ok && id != "" {
+				if _, dup := ids[id]; dup {
+					return badRequest("indexing config: duplicate ID '%s' found at /config/%s", id, strings.Join(path, "/"))
+				}
+				ids[id] = append([]string(nil), path...)
+			}
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				if err := walk(v[k], append(path, k)); err != nil {
+					return err
+				}
+			}
+		case []interface{}:
+			for i, item := range v {
+				if err := walk(item, append(path, strconv.Itoa(i))); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := walk(config, nil); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func splitPath(path string) []string {
+	var parts []string
+	for _, p := range strings.Split(path, "/") {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
+func deepCopy(value interface{}) interface{} {
+	data, _ := json.Marshal(value)
+	var cp interface{}
+	json.Unmarshal(data, &cp)
+	return cp
+}
@@ -7,30 +7,73 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// Valores padrão da conexão com o Caddy
+const (
+	DefaultAdminURL  = "http://localhost:2019"
+	DefaultServer    = "srv0"
+	DefaultAccessLog = "/var/log/caddy/oi-access.log"
 )
 
 // Manager implementa port.ProxyManager usando Caddy Admin API
 type Manager struct {
 	adminURL   string
+	server     string
+	accessLog  string
 	httpClient *http.Client
+	// bootstrapped indica que o app HTTP e o servidor já foram verificados
+	bootstrapped bool
+	// traffic é a leitura incremental do access log (ver TrafficStats)
+	traffic trafficLog
 }
 
 // NewManager cria uma nova instância do Caddy Manager
-func NewManager(adminURL string) *Manager {
+// adminURL, server e accessLog vazios usam DefaultAdminURL, DefaultServer e DefaultAccessLog
+// accessLog é o arquivo em que o Caddy grava o tráfego analisado nos canários
+func NewManager(adminURL string, server string, accessLog string) *Manager {
 	if adminURL == "" {
-		adminURL = "http://localhost:2019"
+		adminURL = DefaultAdminURL
+	}
+	if server == "" {
+		server = DefaultServer
+	}
+	if accessLog == "" {
+		accessLog = DefaultAccessLog
 	}
 	return &Manager{
-		adminURL: adminURL,
+		adminURL:  strings.TrimSuffix(adminURL, "/"),
+		server:    server,
+		accessLog: accessLog,
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
 	}
 }
 
+// AdminURL retorna o endereço da Admin API usada
+func (m *Manager) AdminURL() string {
+	return m.adminURL
+}
+
+// Server retorna o nome do servidor HTTP gerenciado
+func (m *Manager) Server() string {
+	return m.server
+}
+
+// AccessLog retorna o arquivo de access log usado na análise de canários
+func (m *Manager) AccessLog() string {
+	return m.accessLog
+}
+
 // routeConfig representa a configuração de rota do Caddy
+// ID ("@id") permite endereçar a rota diretamente via /id/<tag>, sem depender do índice
 type routeConfig struct {
+	ID       string         `json:"@id,omitempty"`
 	Match    []matchConfig  `json:"match"`
 	Handle   []handleConfig `json:"handle"`
 	Terminal bool           `json:"terminal"`
@@ -38,32 +81,181 @@ type routeConfig struct {
 
 type matchConfig struct {
 	Host []string `json:"host"`
+	// Path casa o prefixo exato e os subcaminhos (ex: ["/v2", "/v2/*"])
+	Path []string `json:"path,omitempty"`
 }
 
 type handleConfig struct {
-	Handler   string        `json:"handler"`
-	Upstreams []upstream    `json:"upstreams,omitempty"`
-	Routes    []interface{} `json:"routes,omitempty"`
+	Handler       string         `json:"handler"`
+	Upstreams     []upstream     `json:"upstreams,omitempty"`
+	LoadBalancing *loadBalancing `json:"load_balancing,omitempty"`
+	Routes        []interface{}  `json:"routes,omitempty"`
+	// Key e Value são usados pelo handler log_append
+	Key   string `json:"key,omitempty"`
+	Value string `json:"value,omitempty"`
+	// StripPathPrefix é usado pelo handler rewrite (remover_prefixo)
+	StripPathPrefix string `json:"strip_path_prefix,omitempty"`
 }
 
 type upstream struct {
 	Dial string `json:"dial"`
 }
 
-// AddRoute adiciona ou atualiza uma rota de domínio para upstream
-func (m *Manager) AddRoute(ctx context.Context, domain string, upstreamHost string, port int) error {
+type loadBalancing struct {
+	SelectionPolicy selectionPolicy `json:"selection_policy"`
+}
+
+type selectionPolicy struct {
+	Policy string `json:"policy"`
+	// Weights acompanha a ordem de Upstreams (apenas em weighted_round_robin)
+	Weights []int `json:"weights,omitempty"`
+}
+
+// hasHost verifica se a rota casa com o domínio
+func (r routeConfig) hasHost(domain string) bool {
+	for _, host := range r.hosts() {
+		if host == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// hosts retorna os hosts dos matchers da rota
+func (r routeConfig) hosts() []string {
+	var hosts []string
+	for _, match := range r.Match {
+		hosts = append(hosts, match.Host...)
+	}
+	return hosts
+}
+
+// pathPrefix retorna o prefixo de caminho da rota (vazio se casar qualquer caminho)
+func (r routeConfig) pathPrefix() string {
+	for _, match := range r.Match {
+		for _, path := range match.Path {
+			if !strings.HasSuffix(path, "*") {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+// serves verifica se a rota atende o endereço (host e prefixo exatos)
+func (r routeConfig) serves(site domain.Site) bool {
+	return r.hasHost(site.Host) && r.pathPrefix() == site.Path
+}
+
+// servesAny verifica se a rota atende algum dos endereços
+func (r routeConfig) servesAny(sites []domain.Site) bool {
+	for _, site := range sites {
+		if r.serves(site) {
+			return true
+		}
+	}
+	return false
+}
+
+// precedence segue domain.Route.Precedence; rotas sem host (ex: catch-all de outros
+// sites) ficam depois de todas
+func (r routeConfig) precedence() int {
+	hosts := r.hosts()
+	if len(hosts) == 0 {
+		return -1
+	}
+	return domain.Route{Domain: hosts[0], PathPrefix: r.pathPrefix()}.Precedence()
+}
+
+// competes verifica se as duas rotas podem atender a mesma requisição
+func (r routeConfig) competes(other routeConfig) bool {
+	hosts, otherHosts := r.hosts(), other.hosts()
+	if len(hosts) == 0 || len(otherHosts) == 0 {
+		return true
+	}
+	for _, a := range hosts {
+		for _, b := range otherHosts {
+			if a == b || domain.MatchHost(a, b) || domain.MatchHost(b, a) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// insertIndex retorna a posição da rota na lista: antes da primeira rota concorrente de
+// menor precedência, já que o Caddy usa a primeira rota que casa
+func insertIndex(routes []routeConfig, route routeConfig) int {
+	for i, other := range routes {
+		if route.competes(other) && other.precedence() < route.precedence() {
+			return i
+		}
+	}
+	return len(routes)
+}
+
+// misplaced verifica se a rota na posição idx ficou fora de ordem (ex: prefixo alterado)
+func misplaced(routes []routeConfig, idx int, route routeConfig) bool {
+	for i, other := range routes {
+		if i == idx || !route.competes(other) {
+			continue
+		}
+		if (i < idx && other.precedence() < route.precedence()) || (i > idx && other.precedence() > route.precedence()) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddRoute adiciona ou substitui a rota de um serviço com todos os upstreams
+// A rota é identificada pelo @id (route.ID): se já existir, é trocada em uma única
+// requisição PATCH (atômica para o tráfego); senão, é criada antes das rotas de menor
+// precedência para os mesmos hosts (ou no fim da lista)
+// Com mais de um upstream, o Caddy distribui as requisições segundo a política da rota
+func (m *Manager) AddRoute(ctx context.Context, r domain.Route) error {
+	if r.ID == "" {
+		return fmt.Errorf("rota para %s sem ID", r.Domain)
+	}
+
+	handle := handleConfig{Handler: "reverse_proxy"}
+	weighted := false
+	for _, u := range r.Upstreams {
+		handle.Upstreams = append(handle.Upstreams, upstream{Dial: u.Address()})
+		weighted = weighted || u.Weight > 0
+	}
+
+	switch {
+	case weighted:
+		// Canário: divisão de tráfego por peso (ex: 90/10)
+		policy := selectionPolicy{Policy: domain.PolicyWeightedRoundRobin}
+		for _, u := range r.Upstreams {
+			policy.Weights = append(policy.Weights, u.Weight)
+		}
+		handle.LoadBalancing = &loadBalancing{SelectionPolicy: policy}
+	case len(handle.Upstreams) > 1:
+		handle.LoadBalancing = &loadBalancing{
+			SelectionPolicy: selectionPolicy{Policy: r.PolicyOrDefault()},
+		}
+	}
+
+	handlers := []handleConfig{handle}
+	if r.StripPrefix && r.PathPrefix != "" {
+		// remover_prefixo: "/v2/pedidos" chega ao upstream como "/pedidos"
+		handlers = append([]handleConfig{{Handler: "rewrite", StripPathPrefix: r.PathPrefix}}, handlers...)
+	}
+	if weighted {
+		// Registra no access log qual upstream atendeu cada requisição (análise do canário)
+		handlers = append([]handleConfig{upstreamLogHandler()}, handlers...)
+	}
+
+	match := matchConfig{Host: r.Hosts()}
+	if r.PathPrefix != "" {
+		match.Path = []string{r.PathPrefix, r.PathPrefix + "/*"}
+	}
 	route := routeConfig{
-		Match: []matchConfig{
-			{Host: []string{domain}},
-		},
-		Handle: []handleConfig{
-			{
-				Handler: "reverse_proxy",
-				Upstreams: []upstream{
-					{Dial: fmt.Sprintf("%s:%d", upstreamHost, port)},
-				},
-			},
-		},
+		ID:       r.ID,
+		Match:    []matchConfig{match},
+		Handle:   handlers,
 		Terminal: true,
 	}
 
@@ -72,123 +264,321 @@ func (m *Manager) AddRoute(ctx context.Context, domain string, upstreamHost stri
 		return fmt.Errorf("falha ao serializar rota: %w", err)
 	}
 
-	// POST /config/apps/http/servers/srv0/routes/...
-	url := fmt.Sprintf("%s/config/apps/http/servers/srv0/routes", m.adminURL)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	// 1. Rota existente que mudaria de precedência (ex: prefixo alterado): é recriada na
+	// posição certa, senão poderia esconder ou ser escondida por outra rota do mesmo host
+	routes, err := m.listRoutes(ctx)
 	if err != nil {
-		return fmt.Errorf("falha ao criar request: %w", err)
+		return err
+	}
+	for i, existing := range routes {
+		if existing.ID != r.ID || !misplaced(routes, i, route) {
+			continue
+		}
+		status, respBody, err := m.do(ctx, http.MethodDelete, "/id/"+r.ID, nil)
+		if err != nil {
+			return err
+		}
+		if status >= 400 && status != http.StatusNotFound {
+			return fmt.Errorf("Caddy retornou erro %d: %s", status, respBody)
+		}
+		return m.insertRoute(ctx, route, body)
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := m.httpClient.Do(req)
+	// 2. Rota já existe: substitui no lugar (PATCH /id/<tag>)
+	status, respBody, err := m.do(ctx, http.MethodPatch, "/id/"+r.ID, body)
 	if err != nil {
-		return fmt.Errorf("falha ao comunicar com Caddy: %w", err)
+		return err
+	}
+	if status < 400 {
+		return nil
+	}
+	if status != http.StatusNotFound {
+		return fmt.Errorf("Caddy retornou erro %d: %s", status, respBody)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode >= 400 {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("Caddy retornou erro %d: %s", resp.StatusCode, string(bodyBytes))
+	// 3. Primeira rota com este ID: garante que o app HTTP e o servidor existem
+	if err := m.ensureServer(ctx); err != nil {
+		return err
 	}
 
+	// 3.1. Remove rotas antigas sem ID para os mesmos endereços
+	// (criadas por versões anteriores do OI), que teriam prioridade sobre a nova
+	if err := m.removeUntagged(ctx, r.Sites()); err != nil {
+		return err
+	}
+
+	// 4. Cria a rota na posição de sua precedência
+	return m.insertRoute(ctx, route, body)
+}
+
+// insertRoute cria a rota antes da primeira rota concorrente de menor precedência
+// (PUT /config/.../routes/<índice>) ou no fim da lista (POST /config/.../routes)
+func (m *Manager) insertRoute(ctx context.Context, route routeConfig, body []byte) error {
+	routes, err := m.listRoutes(ctx)
+	if err != nil {
+		return err
+	}
+
+	method, path := http.MethodPost, m.routesPath()
+	if i := insertIndex(routes, route); i < len(routes) {
+		method, path = http.MethodPut, fmt.Sprintf("%s/%d", m.routesPath(), i)
+	}
+	status, respBody, err := m.do(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	if status >= 400 {
+		return fmt.Errorf("Caddy retornou erro %d: %s", status, respBody)
+	}
 	return nil
 }
 
-// RemoveRoute remove uma rota de domínio
-func (m *Manager) RemoveRoute(ctx context.Context, domain string) error {
-	// Primeiro, precisa encontrar o índice da rota
-	// GET /config/apps/http/servers/srv0/routes
-	url := fmt.Sprintf("%s/config/apps/http/servers/srv0/routes", m.adminURL)
+// RemoveRoute remove a rota de um serviço pelo @id (DELETE /id/<tag>)
+// Rotas antigas sem ID para os mesmos endereços também são removidas
+func (m *Manager) RemoveRoute(ctx context.Context, r domain.Route) error {
+	if r.ID != "" {
+		status, respBody, err := m.do(ctx, http.MethodDelete, "/id/"+r.ID, nil)
+		if err != nil {
+			return err
+		}
+		if status >= 400 && status != http.StatusNotFound {
+			return fmt.Errorf("Caddy retornou erro %d: %s", status, respBody)
+		}
+	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if sites := r.Sites(); len(sites) > 0 {
+		return m.removeUntagged(ctx, sites)
+	}
+	return nil
+}
+
+// removeUntagged remove rotas sem @id que atendem algum dos endereços
+// Só existem em configurações criadas antes dos IDs; os índices são removidos do
+// maior para o menor para não deslocar os que ainda faltam
+func (m *Manager) removeUntagged(ctx context.Context, sites []domain.Site) error {
+	routes, err := m.listRoutes(ctx)
 	if err != nil {
-		return fmt.Errorf("falha ao criar request: %w", err)
+		return err
 	}
 
-	resp, err := m.httpClient.Do(req)
+	for i := len(routes) - 1; i >= 0; i-- {
+		if routes[i].ID != "" || !routes[i].servesAny(sites) {
+			continue
+		}
+		status, respBody, err := m.do(ctx, http.MethodDelete, fmt.Sprintf("%s/%d", m.routesPath(), i), nil)
+		if err != nil {
+			return fmt.Errorf("falha ao remover rota: %w", err)
+		}
+		if status >= 400 && status != http.StatusNotFound {
+			return fmt.Errorf("Caddy retornou erro %d: %s", status, respBody)
+		}
+	}
+	return nil
+}
+
+// HasRoute verifica se existe rota para o endereço
+func (m *Manager) HasRoute(ctx context.Context, site string) (bool, error) {
+	upstreams, err := m.GetUpstreams(ctx, site)
 	if err != nil {
-		return fmt.Errorf("falha ao comunicar com Caddy: %w", err)
+		return false, err
 	}
-	defer resp.Body.Close()
+	return len(upstreams) > 0, nil
+}
 
-	if resp.StatusCode == 404 {
-		return nil // Não existe, nada a fazer
+// GetUpstreams retorna os upstreams atuais do endereço "host[/prefixo]"
+// Como o Caddy usa a primeira rota que casa, é ela que é considerada
+func (m *Manager) GetUpstreams(ctx context.Context, site string) ([]string, error) {
+	target, err := domain.ParseSite(site)
+	if err != nil {
+		return nil, err
+	}
+	routes, err := m.listRoutes(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	var routes []routeConfig
-	if err := json.NewDecoder(resp.Body).Decode(&routes); err != nil {
-		return fmt.Errorf("falha ao parsear rotas: %w", err)
-	}
-
-	// Encontra o índice da rota com o domínio
-	for i, route := range routes {
-		for _, match := range route.Match {
-			for _, host := range match.Host {
-				if host == domain {
-					// DELETE /config/apps/http/servers/srv0/routes/<index>
-					deleteURL := fmt.Sprintf("%s/config/apps/http/servers/srv0/routes/%d", m.adminURL, i)
-					delReq, _ := http.NewRequestWithContext(ctx, http.MethodDelete, deleteURL, nil)
-					delResp, err := m.httpClient.Do(delReq)
-					if err != nil {
-						return fmt.Errorf("falha ao remover rota: %w", err)
-					}
-					delResp.Body.Close()
-					return nil
-				}
+	for _, route := range routes {
+		if !route.serves(target) {
+			continue
+		}
+		var upstreams []string
+		for _, handle := range route.Handle {
+			if handle.Handler != "reverse_proxy" {
+				continue
+			}
+			for _, u := range handle.Upstreams {
+				upstreams = append(upstreams, u.Dial)
 			}
 		}
+		return upstreams, nil
 	}
 
-	return nil // Rota não encontrada, ok
+	return nil, nil
 }
 
-// HasRoute verifica se uma rota existe
-func (m *Manager) HasRoute(ctx context.Context, domain string) (bool, error) {
-	upstream, err := m.GetUpstream(ctx, domain)
+// listRoutes retorna as rotas do servidor gerenciado (vazio se ainda não houver)
+func (m *Manager) listRoutes(ctx context.Context) ([]routeConfig, error) {
+	status, body, err := m.do(ctx, http.MethodGet, m.routesPath(), nil)
 	if err != nil {
-		return false, err
+		return nil, err
+	}
+	// Servidor ainda não criado: o Caddy responde 404 ou 400 (caminho inválido)
+	if status == http.StatusNotFound || status == http.StatusBadRequest {
+		return nil, nil
+	}
+	if status >= 400 {
+		return nil, fmt.Errorf("Caddy retornou erro %d: %s", status, body)
 	}
-	return upstream != "", nil
+
+	var routes []routeConfig
+	if err := json.Unmarshal(body, &routes); err != nil {
+		return nil, fmt.Errorf("falha ao parsear rotas: %w", err)
+	}
+	return routes, nil
 }
 
-// GetUpstream retorna o upstream atual para um domínio
-func (m *Manager) GetUpstream(ctx context.Context, domain string) (string, error) {
-	url := fmt.Sprintf("%s/config/apps/http/servers/srv0/routes", m.adminURL)
+// routesPath é o caminho (na Admin API) da lista de rotas do servidor gerenciado
+func (m *Manager) routesPath() string {
+	return m.serverPath() + "/routes"
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return "", fmt.Errorf("falha ao criar request: %w", err)
+func (m *Manager) serverPath() string {
+	return "/config/apps/http/servers/" + m.server
+}
+
+// serverConfig é a configuração mínima do servidor HTTP criado pelo OI
+type serverConfig struct {
+	Listen []string      `json:"listen"`
+	Routes []routeConfig `json:"routes"`
+}
+
+// ensureServer cria o app HTTP e o servidor gerenciado quando não existem
+// (ex: Caddy recém-instalado, com configuração vazia)
+// Percorre o caminho apps -> http -> servers -> <server> e cria a partir do
+// primeiro nível ausente, sem tocar no que já existe
+func (m *Manager) ensureServer(ctx context.Context) error {
+	if m.bootstrapped {
+		return nil
 	}
 
-	resp, err := m.httpClient.Do(req)
+	exists, err := m.exists(ctx, m.serverPath())
 	if err != nil {
-		return "", fmt.Errorf("falha ao comunicar com Caddy: %w", err)
+		return err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == 404 {
-		return "", nil
+	if !exists {
+		fmt.Printf("🔧 Criando servidor HTTP '%s' no Caddy (:80/:443)...\n", m.server)
+
+		// Cada nível do caminho até o servidor é uma chave dentro do anterior
+		server := serverConfig{Listen: []string{":80", ":443"}, Routes: []routeConfig{}}
+		path, value, err := m.wrapMissing(ctx, "/config/", []string{"apps", "http", "servers", m.server}, server)
+		if err != nil {
+			return err
+		}
+
+		// PUT cria a primeira chave ausente dentro do último nível existente;
+		// com a configuração vazia, a raiz inteira é definida (POST /config/)
+		method := http.MethodPut
+		if path == "/config/" {
+			method = http.MethodPost
+		}
+
+		body, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("falha ao serializar configuração: %w", err)
+		}
+
+		status, respBody, err := m.do(ctx, method, path, body)
+		if err != nil {
+			return err
+		}
+		if status >= 400 {
+			return fmt.Errorf("falha ao criar servidor HTTP no Caddy (%d): %s", status, respBody)
+		}
 	}
 
-	var routes []routeConfig
-	if err := json.NewDecoder(resp.Body).Decode(&routes); err != nil {
-		return "", fmt.Errorf("falha ao parsear rotas: %w", err)
+	m.bootstrapped = true
+	return nil
+}
+
+// wrapMissing prepara a gravação de value em base/keys..., criando os níveis ausentes
+// Encontra o nível mais profundo que já existe (base inclusive) e embrulha value em cada
+// nível ausente abaixo dele; retorna o caminho a gravar, a primeira chave ausente dentro
+// do nível existente, ou base quando nem keys[0] pode ser alcançada
+func (m *Manager) wrapMissing(ctx context.Context, base string, keys []string, value interface{}) (string, interface{}, error) {
+	level := func(depth int) string {
+		if depth == 0 {
+			return base
+		}
+		return strings.TrimSuffix(base, "/") + "/" + strings.Join(keys[:depth], "/")
 	}
 
-	for _, route := range routes {
-		for _, match := range route.Match {
-			for _, host := range match.Host {
-				if host == domain {
-					if len(route.Handle) > 0 && len(route.Handle[0].Upstreams) > 0 {
-						return route.Handle[0].Upstreams[0].Dial, nil
-					}
-				}
-			}
+	// -1 = nem base existe
+	depth := len(keys) - 1
+	for ; depth >= 0; depth-- {
+		exists, err := m.exists(ctx, level(depth))
+		if err != nil {
+			return "", nil, err
 		}
+		if exists {
+			break
+		}
+	}
+
+	for i := len(keys) - 1; i > depth; i-- {
+		value = map[string]interface{}{keys[i]: value}
+	}
+	if depth < 0 {
+		return base, value, nil
+	}
+	return level(depth + 1), value, nil
+}
+
+// HasServer verifica se o servidor HTTP gerenciado já existe no Caddy
+func (m *Manager) HasServer(ctx context.Context) (bool, error) {
+	return m.exists(ctx, m.serverPath())
+}
+
+// exists verifica se há um valor (não nulo) no caminho da configuração
+func (m *Manager) exists(ctx context.Context, path string) (bool, error) {
+	status, body, err := m.do(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return false, err
 	}
+	if status >= 400 {
+		// Caminho intermediário inexistente
+		return false, nil
+	}
+	trimmed := strings.TrimSpace(string(body))
+	return trimmed != "" && trimmed != "null", nil
+}
 
-	return "", nil
+// do executa uma requisição na Admin API e retorna status e corpo da resposta
+func (m *Manager) do(ctx context.Context, method, path string, body []byte) (int, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, m.adminURL+path, reader)
+	if err != nil {
+		return 0, nil, fmt.Errorf("falha ao criar request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("falha ao comunicar com Caddy: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("falha ao ler resposta do Caddy: %w", err)
+	}
+	return resp.StatusCode, respBody, nil
 }
 
 // Reload força recarregamento da configuração
@@ -0,0 +1,515 @@
+package caddy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crom-tech/oi/internal/adapter/caddy/caddytest"
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+const routesPath = "/config/apps/http/servers/srv0/routes"
+
+func newTestManager(t *testing.T) (*Manager, *caddytest.Server) {
+	t.Helper()
+	srv := caddytest.NewServer(t)
+	return NewManager(srv.URL, "", ""), srv
+}
+
+// routes retorna as rotas do servidor srv0 gravadas no stub
+func routes(t *testing.T, srv *caddytest.Server) []routeConfig {
+	t.Helper()
+	var result []routeConfig
+	if err := json.Unmarshal(srv.Get("apps/http/servers/srv0/routes"), &result); err != nil {
+		t.Fatalf("rotas inválidas no stub: %v", err)
+	}
+	return result
+}
+
+func route(id, domainName string, upstreams ...string) domain.Route {
+	r := domain.Route{ID: id, Domain: domainName}
+	for _, u := range upstreams {
+		host, port, _ := strings.Cut(u, ":")
+		p, _ := strconv.Atoi(port)
+		r.Upstreams = append(r.Upstreams, domain.Upstream{Host: host, Port: p})
+	}
+	return r
+}
+
+func dials(r routeConfig) []string {
+	var result []string
+	for _, h := range r.Handle {
+		for _, u := range h.Upstreams {
+			result = append(result, u.Dial)
+		}
+	}
+	return result
+}
+
+// legacyConfig tem rotas sem @id (versões antigas do OI) entre rotas de outros sites
+const legacyConfig = `{
+  "apps": {"http": {"servers": {"srv0": {"listen": [":443"], "routes": [
+    {"match": [{"host": ["loja.com"]}], "handle": [{"handler": "reverse_proxy", "upstreams": [{"dial": "antigo-1:80"}]}], "terminal": true},
+    {"match": [{"host": ["blog.com"]}], "handle": [{"handler": "reverse_proxy", "upstreams": [{"dial": "blog:80"}]}], "terminal": true},
+    {"match": [{"host": ["loja.com"]}], "handle": [{"handler": "reverse_proxy", "upstreams": [{"dial": "antigo-2:80"}]}], "terminal": true}
+  ]}}}}
+}`
+
+func TestAddRouteBootstrapsEmptyCaddy(t *testing.T) {
+	m, srv := newTestManager(t)
+
+	if err := m.AddRoute(context.Background(), route("oi-loja", "loja.com", "oi-loja-abc:80")); err != nil {
+		t.Fatal(err)
+	}
+
+	var server serverConfig
+	if err := json.Unmarshal(srv.Get("apps/http/servers/srv0"), &server); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(server.Listen, ",") != ":80,:443" {
+		t.Fatalf("listen = %v", server.Listen)
+	}
+
+	got := routes(t, srv)
+	if len(got) != 1 || got[0].ID != "oi-loja" || !got[0].hasHost("loja.com") || !got[0].Terminal {
+		t.Fatalf("rota criada incorretamente: %+v", got)
+	}
+	if d := dials(got[0]); strings.Join(d, ",") != "oi-loja-abc:80" {
+		t.Fatalf("upstreams = %v", d)
+	}
+	if got[0].Handle[0].LoadBalancing != nil {
+		t.Fatal("um único upstream não deveria ter load_balancing")
+	}
+}
+
+func TestAddRouteKeepsOtherServers(t *testing.T) {
+	m, srv := newTestManager(t)
+	srv.Load(t, `{"apps": {"http": {"servers": {"admin": {"listen": [":8080"], "routes": []}}}}}`)
+
+	if err := m.AddRoute(context.Background(), route("oi-loja", "loja.com", "oi-loja-abc:80")); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(srv.Get("apps/http/servers/admin/listen")); got != `[":8080"]` {
+		t.Fatalf("servidor existente foi alterado: %s", got)
+	}
+	if len(routes(t, srv)) != 1 {
+		t.Fatal("rota não criada no servidor srv0")
+	}
+}
+
+func TestAddRouteReplacesByID(t *testing.T) {
+	m, srv := newTestManager(t)
+	ctx := context.Background()
+
+	if err := m.AddRoute(ctx, route("oi-blog", "blog.com", "blog:80")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddRoute(ctx, route("oi-loja", "loja.com", "oi-loja-v1:80")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Novo deploy com 2 réplicas: troca no lugar, sem duplicar a rota
+	next := route("oi-loja", "loja.com", "oi-loja-v2:80", "oi-loja-v2-2:80")
+	next.Policy = domain.PolicyLeastConn
+	if err := m.AddRoute(ctx, next); err != nil {
+		t.Fatal(err)
+	}
+
+	got := routes(t, srv)
+	if len(got) != 2 || got[0].ID != "oi-blog" || got[1].ID != "oi-loja" {
+		t.Fatalf("rotas = %+v", got)
+	}
+	if d := dials(got[1]); strings.Join(d, ",") != "oi-loja-v2:80,oi-loja-v2-2:80" {
+		t.Fatalf("upstreams = %v", d)
+	}
+	lb := got[1].Handle[0].LoadBalancing
+	if lb == nil || lb.SelectionPolicy.Policy != domain.PolicyLeastConn {
+		t.Fatalf("load_balancing = %+v", lb)
+	}
+
+	requests := srv.Requests()
+	if last := requests[len(requests)-1]; last != "PATCH /id/oi-loja" {
+		t.Fatalf("substituição deveria ser um único PATCH, última requisição: %s", last)
+	}
+}
+
+func TestAddRouteWeightedLogsUpstream(t *testing.T) {
+	m, srv := newTestManager(t)
+	r := route("oi-loja", "loja.com", "estavel:80", "canario:80")
+	r.Upstreams[0].Weight = 90
+	r.Upstreams[1].Weight = 10
+
+	if err := m.AddRoute(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	got := routes(t, srv)[0]
+	if len(got.Handle) != 2 || got.Handle[0].Handler != "log_append" || got.Handle[0].Key != upstreamLogKey {
+		t.Fatalf("handlers = %+v", got.Handle)
+	}
+	policy := got.Handle[1].LoadBalancing.SelectionPolicy
+	if policy.Policy != domain.PolicyWeightedRoundRobin || len(policy.Weights) != 2 || policy.Weights[0] != 90 || policy.Weights[1] != 10 {
+		t.Fatalf("selection_policy = %+v", policy)
+	}
+}
+
+func TestAddRouteRemovesUntaggedRoutes(t *testing.T) {
+	m, srv := newTestManager(t)
+	srv.Load(t, legacyConfig)
+
+	if err := m.AddRoute(context.Background(), route("oi-loja", "loja.com", "oi-loja-v1:80")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Os índices 2 e 0 são removidos (nessa ordem) e a rota do blog fica intacta
+	got := routes(t, srv)
+	if len(got) != 2 || !got[0].hasHost("blog.com") || got[1].ID != "oi-loja" {
+		t.Fatalf("rotas = %+v", got)
+	}
+	upstreams, err := m.GetUpstreams(context.Background(), "loja.com")
+	if err != nil || strings.Join(upstreams, ",") != "oi-loja-v1:80" {
+		t.Fatalf("GetUpstreams = %v, %v", upstreams, err)
+	}
+}
+
+func TestAddRouteOrdersPathPrefixesAndWildcards(t *testing.T) {
+	m, srv := newTestManager(t)
+	ctx := context.Background()
+
+	tenants := route("oi-tenants", "*.loja.com", "tenants:80")
+	site := route("oi-loja", "loja.com", "site:80")
+	site.Aliases = []string{"www.loja.com"}
+	api := route("oi-api", "loja.com", "api:80")
+	api.PathPrefix = "/v2"
+	api.StripPrefix = true
+	for _, r := range []domain.Route{tenants, site, api} {
+		if err := m.AddRoute(ctx, r); err != nil {
+			t.Fatalf("AddRoute %s: %v", r.ID, err)
+		}
+	}
+
+	// O Caddy avalia as rotas em ordem: prefixo primeiro, depois host exato, depois curinga
+	got := routes(t, srv)
+	var ids []string
+	for _, r := range got {
+		ids = append(ids, r.ID)
+	}
+	if strings.Join(ids, ",") != "oi-api,oi-loja,oi-tenants" {
+		t.Fatalf("ordem das rotas = %v", ids)
+	}
+	if match := got[0].Match[0]; strings.Join(match.Host, ",") != "loja.com" || strings.Join(match.Path, ",") != "/v2,/v2/*" {
+		t.Errorf("match com prefixo inesperado: %+v", match)
+	}
+	if h := got[0].Handle[0]; h.Handler != "rewrite" || h.StripPathPrefix != "/v2" {
+		t.Errorf("handlers = %+v", got[0].Handle)
+	}
+	if match := got[1].Match[0]; strings.Join(match.Host, ",") != "loja.com,www.loja.com" || len(match.Path) != 0 {
+		t.Errorf("match com aliases inesperado: %+v", match)
+	}
+
+	// Atualizar uma rota já na posição certa não a move
+	if err := m.AddRoute(ctx, route("oi-tenants", "*.loja.com", "tenants-v2:80")); err != nil {
+		t.Fatal(err)
+	}
+	requests := srv.Requests()
+	if last := requests[len(requests)-1]; last != "PATCH /id/oi-tenants" {
+		t.Fatalf("atualização deveria ser um único PATCH, última requisição: %s", last)
+	}
+
+	for site, want := range map[string]string{
+		"www.loja.com": "site:80",
+		"loja.com/v2":  "api:80",
+		"*.loja.com":   "tenants-v2:80",
+	} {
+		if upstreams, err := m.GetUpstreams(ctx, site); err != nil || strings.Join(upstreams, ",") != want {
+			t.Errorf("GetUpstreams(%s) = %v, %v; esperado %s", site, upstreams, err, want)
+		}
+	}
+}
+
+func TestAddRouteErrors(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sem ID", func(t *testing.T) {
+		m, _ := newTestManager(t)
+		if err := m.AddRoute(ctx, route("", "loja.com", "a:80")); err == nil {
+			t.Fatal("esperado erro para rota sem ID")
+		}
+	})
+
+	t.Run("PATCH com erro", func(t *testing.T) {
+		m, srv := newTestManager(t)
+		srv.Override(http.MethodPatch, "/id/oi-loja", http.StatusInternalServerError, `{"error": "boom"}`)
+		err := m.AddRoute(ctx, route("oi-loja", "loja.com", "a:80"))
+		if err == nil || !strings.Contains(err.Error(), "500") {
+			t.Fatalf("esperado erro 500, obtido %v", err)
+		}
+		if len(routes(t, srv)) != 0 {
+			t.Fatal("nenhuma rota deveria ter sido criada")
+		}
+	})
+
+	t.Run("POST recusado", func(t *testing.T) {
+		m, srv := newTestManager(t)
+		srv.Override(http.MethodPost, routesPath, http.StatusBadRequest, `{"error": "loading config: invalid handler"}`)
+		err := m.AddRoute(ctx, route("oi-loja", "loja.com", "a:80"))
+		if err == nil || !strings.Contains(err.Error(), "invalid handler") {
+			t.Fatalf("esperado erro do Caddy, obtido %v", err)
+		}
+	})
+
+	t.Run("criação do servidor recusada", func(t *testing.T) {
+		m, srv := newTestManager(t)
+		srv.Override(http.MethodPost, "/config/", http.StatusForbidden, "")
+		if err := m.AddRoute(ctx, route("oi-loja", "loja.com", "a:80")); err == nil {
+			t.Fatal("esperado erro ao criar servidor HTTP")
+		}
+	})
+
+	t.Run("Caddy fora do ar", func(t *testing.T) {
+		m, srv := newTestManager(t)
+		srv.Close()
+		if err := m.AddRoute(ctx, route("oi-loja", "loja.com", "a:80")); err == nil {
+			t.Fatal("esperado erro de conexão")
+		}
+	})
+}
+
+func TestRemoveRoute(t *testing.T) {
+	ctx := context.Background()
+	m, srv := newTestManager(t)
+	srv.Load(t, legacyConfig)
+
+	if err := m.AddRoute(ctx, route("oi-blog", "blog.com", "oi-blog-v1:80")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RemoveRoute(ctx, domain.Route{ID: "oi-blog", Domain: "blog.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Remove a rota com @id e a antiga sem ID do mesmo domínio; as de loja.com ficam
+	got := routes(t, srv)
+	if len(got) != 2 || !got[0].hasHost("loja.com") || !got[1].hasHost("loja.com") {
+		t.Fatalf("rotas = %+v", got)
+	}
+
+	// Rota que já não existe (404) não é erro
+	if err := m.RemoveRoute(ctx, domain.Route{ID: "oi-blog", Domain: "blog.com"}); err != nil {
+		t.Fatalf("remoção repetida falhou: %v", err)
+	}
+
+	// Somente pelo domínio: as duas rotas sem ID saem
+	if err := m.RemoveRoute(ctx, domain.Route{Domain: "loja.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := routes(t, srv); len(got) != 0 {
+		t.Fatalf("rotas restantes: %+v", got)
+	}
+}
+
+func TestRemoveRouteErrors(t *testing.T) {
+	ctx := context.Background()
+	m, srv := newTestManager(t)
+	if err := m.AddRoute(ctx, route("oi-loja", "loja.com", "a:80")); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.Override(http.MethodDelete, "/id/oi-loja", http.StatusInternalServerError, "boom")
+	if err := m.RemoveRoute(ctx, domain.Route{ID: "oi-loja", Domain: "loja.com"}); err == nil {
+		t.Fatal("esperado erro 500 no DELETE")
+	}
+
+	srv.ClearOverrides()
+	srv.Override(http.MethodGet, routesPath, http.StatusOK, "not json")
+	if err := m.RemoveRoute(ctx, domain.Route{ID: "oi-loja", Domain: "loja.com"}); err == nil {
+		t.Fatal("esperado erro com lista de rotas malformada")
+	}
+}
+
+func TestGetUpstreamsAndHasRoute(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(t)
+
+	// Servidor ainda não criado: sem rotas e sem erro
+	upstreams, err := m.GetUpstreams(ctx, "loja.com")
+	if err != nil || upstreams != nil {
+		t.Fatalf("GetUpstreams sem servidor = %v, %v", upstreams, err)
+	}
+	if ok, err := m.HasRoute(ctx, "loja.com"); err != nil || ok {
+		t.Fatalf("HasRoute sem servidor = %v, %v", ok, err)
+	}
+
+	r := route("oi-loja", "loja.com", "estavel:80", "canario:80")
+	r.Upstreams[0].Weight, r.Upstreams[1].Weight = 90, 10
+	if err := m.AddRoute(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+
+	// O handler log_append do canário é ignorado
+	upstreams, err = m.GetUpstreams(ctx, "loja.com")
+	if err != nil || strings.Join(upstreams, ",") != "estavel:80,canario:80" {
+		t.Fatalf("GetUpstreams = %v, %v", upstreams, err)
+	}
+	if ok, err := m.HasRoute(ctx, "loja.com"); err != nil || !ok {
+		t.Fatalf("HasRoute = %v, %v", ok, err)
+	}
+	if ok, err := m.HasRoute(ctx, "outro.com"); err != nil || ok {
+		t.Fatalf("HasRoute de domínio sem rota = %v, %v", ok, err)
+	}
+}
+
+func TestGetUpstreamsUsesFirstMatchingRoute(t *testing.T) {
+	m, srv := newTestManager(t)
+	srv.Load(t, legacyConfig)
+
+	upstreams, err := m.GetUpstreams(context.Background(), "loja.com")
+	if err != nil || strings.Join(upstreams, ",") != "antigo-1:80" {
+		t.Fatalf("GetUpstreams = %v, %v", upstreams, err)
+	}
+}
+
+func TestGetUpstreamsErrors(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"erro interno", http.StatusInternalServerError, `{"error": "boom"}`},
+		{"JSON malformado", http.StatusOK, `[{"match": `},
+		{"tipo inesperado", http.StatusOK, `{"routes": []}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, srv := newTestManager(t)
+			srv.Override(http.MethodGet, routesPath, tc.status, tc.body)
+			if _, err := m.GetUpstreams(ctx, "loja.com"); err == nil {
+				t.Fatal("esperado erro")
+			}
+			if _, err := m.HasRoute(ctx, "loja.com"); err == nil {
+				t.Fatal("esperado erro em HasRoute")
+			}
+		})
+	}
+}
+
+func TestHealth(t *testing.T) {
+	ctx := context.Background()
+	m, srv := newTestManager(t)
+
+	if err := m.Health(ctx); err != nil {
+		t.Fatalf("Health = %v", err)
+	}
+
+	srv.Override(http.MethodGet, "/config/", http.StatusServiceUnavailable, "")
+	if err := m.Health(ctx); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("esperado erro 503, obtido %v", err)
+	}
+
+	srv.Close()
+	if err := m.Health(ctx); err == nil {
+		t.Fatal("esperado erro com Caddy fora do ar")
+	}
+}
+
+func TestEnableTrafficLog(t *testing.T) {
+	ctx := context.Background()
+	m, srv := newTestManager(t)
+
+	if err := m.EnableTrafficLog(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(srv.Get("apps/http/servers/srv0/logs/default_logger_name")); got != `"oi"` {
+		t.Fatalf("default_logger_name = %s", got)
+	}
+	var logger struct {
+		Writer  map[string]string `json:"writer"`
+		Include []string          `json:"include"`
+	}
+	if err := json.Unmarshal(srv.Get("logging/logs/oi-access"), &logger); err != nil {
+		t.Fatal(err)
+	}
+	if logger.Writer["filename"] != DefaultAccessLog || len(logger.Include) != 1 || logger.Include[0] != "http.log.access.oi" {
+		t.Fatalf("logger = %+v", logger)
+	}
+
+	// Idempotente: chamar de novo não duplica nem falha
+	if err := m.EnableTrafficLog(ctx); err != nil {
+		t.Fatalf("segunda chamada: %v", err)
+	}
+}
+
+func TestTrafficStatsReadsIncrementally(t *testing.T) {
+	ctx := context.Background()
+	srv := caddytest.NewServer(t)
+	logPath := filepath.Join(t.TempDir(), "access.log")
+	m := NewManager(srv.URL, "", logPath)
+
+	since := time.Unix(1000, 0)
+	entry := func(ts int, upstream string, status int) string {
+		return fmt.Sprintf(`{"ts":%d,"request":{"host":"loja.com"},"duration":0.01,"status":%d,"upstream":%q}`+"\n", ts, status, upstream)
+	}
+	appendLog := func(lines ...string) {
+		t.Helper()
+		f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		for _, l := range lines {
+			f.WriteString(l)
+		}
+	}
+	stats := func(upstream string) domain.TrafficStats {
+		t.Helper()
+		s, err := m.TrafficStats(ctx, []string{"loja.com"}, []string{upstream}, since)
+		if err != nil {
+			t.Fatalf("TrafficStats: %v", err)
+		}
+		return s
+	}
+
+	if s := stats("canario:80"); s.Requests != 0 {
+		t.Errorf("sem access log: %+v", s)
+	}
+
+	appendLog(entry(900, "canario:80", 200), entry(1001, "canario:80", 200), entry(1002, "estavel:80", 502))
+	if s := stats("canario:80"); s.Requests != 1 {
+		t.Errorf("canário = %+v; esperado 1 requisição (a anterior a since fica de fora)", s)
+	}
+	if s := stats("estavel:80"); s.Requests != 1 || s.Errors != 1 {
+		t.Errorf("estável = %+v; esperado 1 requisição com erro", s)
+	}
+
+	// Só as linhas novas são lidas; a linha ainda sem "\n" fica para depois
+	offset := m.traffic.offset
+	partial := entry(1004, "canario:80", 200)
+	appendLog(entry(1003, "canario:80", 500), partial[:20])
+	if s := stats("canario:80"); s.Requests != 2 || s.Errors != 1 {
+		t.Errorf("canário = %+v; esperado 2 requisições e 1 erro", s)
+	}
+	if m.traffic.offset <= offset {
+		t.Errorf("offset = %d; esperado avançar de %d", m.traffic.offset, offset)
+	}
+	appendLog(partial[20:])
+	if s := stats("canario:80"); s.Requests != 3 {
+		t.Errorf("canário = %+v; esperado 3 requisições com a linha completada", s)
+	}
+
+	// Um arquivo novo no caminho (rotação) é lido do início
+	if err := os.Remove(logPath); err != nil {
+		t.Fatal(err)
+	}
+	appendLog(entry(1005, "canario:80", 200))
+	if s := stats("canario:80"); s.Requests != 1 {
+		t.Errorf("após rotação = %+v; esperado 1 requisição", s)
+	}
+}
@@ -5,8 +5,6 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/crom-tech/oi/internal/adapter/caddy"
-	"github.com/crom-tech/oi/internal/adapter/docker"
 	"github.com/crom-tech/oi/internal/config"
 	"github.com/crom-tech/oi/internal/core/port"
 	"github.com/crom-tech/oi/internal/core/service"
@@ -16,15 +14,17 @@ import (
 func NewDownCommand() *cobra.Command {
 	var path string
 	var project string
-	var noCaddy bool
+	var noProxy bool
 	var all bool
+	var volumes bool
 
 	cmd := &cobra.Command{
 		Use:     "down",
 		Aliases: []string{"remove", "rm"},
 		Short:   "Remove containers e recursos (alias: remove)",
 		Long: `Para e remove containers gerenciados pelo OI.
-Use --all para remover TODOS os projetos e limpar o sistema.`,
+Use --all para remover TODOS os projetos e limpar o sistema.
+Volumes nomeados são preservados; use --volumes para removê-los também (dados são perdidos).`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			projectName := ""
 
@@ -43,34 +43,34 @@ Use --all para remover TODOS os projetos e limpar o sistema.`,
 				}
 			}
 
-			// Cria Docker client
-			dockerClient, err := docker.NewClient()
+			// Cria o client do runtime (Docker ou Podman)
+			containerRuntime, err := newRuntime()
 			if err != nil {
-				return fmt.Errorf("❌ Erro ao conectar com Docker: %w", err)
+				return fmt.Errorf("❌ Erro ao conectar com o runtime de containers: %w", err)
 			}
-			defer dockerClient.Close()
+			defer containerRuntime.Close()
 
-			// Cria Caddy manager (opcional)
+			// Conecta no proxy (opcional)
 			var proxyManager port.ProxyManager // Interface nil por padrão
-			if !noCaddy {
-				caddyManager := caddy.NewManager("")
-				if err := caddyManager.Health(cmd.Context()); err == nil {
-					proxyManager = caddyManager
+			if !noProxy {
+				if pm, err := connectProxy(cmd.Context()); err == nil {
+					proxyManager = pm
 				}
 			}
 
 			// Cria orchestrator
-			orchestrator := service.NewOrchestrator(dockerClient, proxyManager)
+			orchestrator := service.NewOrchestrator(containerRuntime, proxyManager, newStateStore())
 
 			// Executa down
-			return orchestrator.Down(cmd.Context(), projectName)
+			return orchestrator.Down(cmd.Context(), projectName, volumes)
 		},
 	}
 
 	cmd.Flags().StringVarP(&path, "file", "f", ".", "Caminho para oi.json ou diretório")
 	cmd.Flags().StringVarP(&project, "project", "p", "", "Nome do projeto (sobrescreve oi.json)")
-	cmd.Flags().BoolVar(&noCaddy, "no-caddy", false, "Desabilita integração com Caddy")
+	addNoProxyFlag(cmd, &noProxy, "Desabilita integração com o proxy (Caddy, Traefik, Nginx ou embutido)")
 	cmd.Flags().BoolVar(&all, "all", false, "Remove TODOS os containers e redes do OI")
+	cmd.Flags().BoolVar(&volumes, "volumes", false, "Remove também os volumes nomeados (apaga os dados)")
 
 	return cmd
 }
@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/crom-tech/oi/internal/adapter/caddy"
+	"github.com/crom-tech/oi/internal/adapter/docker"
+	"github.com/crom-tech/oi/internal/adapter/nginx"
+	"github.com/crom-tech/oi/internal/adapter/oiproxy"
+	"github.com/crom-tech/oi/internal/adapter/podman"
+	"github.com/crom-tech/oi/internal/adapter/traefik"
+	"github.com/crom-tech/oi/internal/config"
+	"github.com/crom-tech/oi/internal/core/port"
+)
+
+// globalFlags guarda as flags persistentes do comando raiz
+// Têm prioridade sobre variáveis de ambiente e ~/.oi/config.json
+var globalFlags struct {
+	runtime     string
+	proxy       string
+	caddyAdmin  string
+	caddyServer string
+}
+
+// AddGlobalFlags registra as flags válidas para todos os comandos
+func AddGlobalFlags(root *cobra.Command) {
+	root.PersistentFlags().StringVar(&globalFlags.runtime, "runtime", "", "Runtime de containers: docker ou podman (padrão docker)")
+	root.PersistentFlags().StringVar(&globalFlags.proxy, "proxy", "", "Proxy reverso: caddy, traefik, nginx ou builtin (padrão caddy)")
+	root.PersistentFlags().StringVar(&globalFlags.caddyAdmin, "caddy-admin", "", "URL da Admin API do Caddy (padrão http://localhost:2019)")
+	root.PersistentFlags().StringVar(&globalFlags.caddyServer, "caddy-server", "", "Servidor HTTP do Caddy gerenciado pelo OI (padrão srv0)")
+}
+
+// loadGlobalConfig carrega a configuração global e aplica as flags
+// Se o arquivo for inválido, avisa e segue com ambiente e padrões
+func loadGlobalConfig() *config.GlobalConfig {
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		fmt.Printf("⚠️  Aviso: configuração global ignorada: %v\n", err)
+		cfg = &config.GlobalConfig{}
+	}
+
+	if globalFlags.runtime != "" {
+		cfg.Runtime.Engine = globalFlags.runtime
+	}
+	if globalFlags.proxy != "" {
+		cfg.Proxy = globalFlags.proxy
+	}
+	if globalFlags.caddyAdmin != "" {
+		cfg.Caddy.AdminURL = globalFlags.caddyAdmin
+	}
+	if globalFlags.caddyServer != "" {
+		cfg.Caddy.Server = globalFlags.caddyServer
+	}
+	return cfg
+}
+
+// runtimeClient é o runtime de containers usado pelos comandos (docker.Client ou podman.Client)
+type runtimeClient interface {
+	port.ContainerRuntime
+	Ping(ctx context.Context) error
+	Version(ctx context.Context) (string, error)
+	Host() string
+	Login(ctx context.Context, server, username, password string) (string, error)
+	SetCredentials(creds map[string]docker.Credentials)
+	Close() error
+}
+
+// newRuntime cria o client do runtime configurado (flag, OI_RUNTIME ou ~/.oi/config.json)
+// com as credenciais de registry de ~/.oi/config.json
+// (as do ~/.docker/config.json são lidas pelo próprio client)
+func newRuntime() (runtimeClient, error) {
+	cfg := loadGlobalConfig()
+
+	var client runtimeClient
+	switch engine := cfg.Runtime.EngineOrDefault(); engine {
+	case config.RuntimeDocker:
+		var dockerClient *docker.Client
+		var err error
+		if cfg.Runtime.Host != "" {
+			dockerClient, err = docker.NewClientWithHost(cfg.Runtime.Host)
+		} else {
+			dockerClient, err = docker.NewClient()
+		}
+		if err != nil {
+			return nil, err
+		}
+		client = dockerClient
+	case config.RuntimePodman:
+		podmanClient, err := podman.NewClient(cfg.Runtime.Host)
+		if err != nil {
+			return nil, err
+		}
+		client = podmanClient
+	default:
+		return nil, fmt.Errorf("runtime desconhecido '%s' (use %s ou %s)", engine, config.RuntimeDocker, config.RuntimePodman)
+	}
+
+	if len(cfg.Registry) > 0 {
+		creds := make(map[string]docker.Credentials, len(cfg.Registry))
+		for server, auth := range cfg.Registry {
+			creds[server] = docker.Credentials{Username: auth.Username, Password: auth.Secret()}
+		}
+		client.SetCredentials(creds)
+	}
+	return client, nil
+}
+
+// newCaddyManager cria o Caddy manager conforme flags, ambiente e ~/.oi/config.json
+func newCaddyManager() *caddy.Manager {
+	cfg := loadGlobalConfig()
+	return caddy.NewManager(cfg.Caddy.AdminURL, cfg.Caddy.Server, cfg.Caddy.AccessLog)
+}
+
+// newTraefikManager cria o Traefik manager conforme ambiente e ~/.oi/config.json
+func newTraefikManager() *traefik.Manager {
+	cfg := loadGlobalConfig()
+	return traefik.NewManager(cfg.Traefik.APIURL, cfg.Traefik.Dir, cfg.Traefik.EntryPoints, cfg.Traefik.CertResolver)
+}
+
+// newNginxManager cria o Nginx manager conforme ambiente e ~/.oi/config.json
+func newNginxManager() *nginx.Manager {
+	cfg := loadGlobalConfig()
+	return nginx.NewManager(cfg.Nginx.Dir, cfg.Nginx.Binary, cfg.Nginx.PidFile)
+}
+
+// newBuiltinProxyManager cria o client do proxy embutido conforme ambiente e ~/.oi/config.json
+func newBuiltinProxyManager() *oiproxy.Manager {
+	return oiproxy.NewManager(loadGlobalConfig().Builtin.Socket)
+}
+
+// newProxyManager cria o proxy configurado (flag, OI_PROXY ou ~/.oi/config.json)
+func newProxyManager() (port.ProxyManager, error) {
+	switch proxy := loadGlobalConfig().ProxyOrDefault(); proxy {
+	case config.ProxyCaddy:
+		return newCaddyManager(), nil
+	case config.ProxyTraefik:
+		return newTraefikManager(), nil
+	case config.ProxyNginx:
+		return newNginxManager(), nil
+	case config.ProxyBuiltin:
+		return newBuiltinProxyManager(), nil
+	default:
+		return nil, fmt.Errorf("proxy desconhecido '%s' (use %s, %s, %s ou %s)", proxy,
+			config.ProxyCaddy, config.ProxyTraefik, config.ProxyNginx, config.ProxyBuiltin)
+	}
+}
+
+// connectProxy cria o proxy configurado e verifica se está acessível
+func connectProxy(ctx context.Context) (port.ProxyManager, error) {
+	proxyManager, err := newProxyManager()
+	if err != nil {
+		return nil, err
+	}
+	if err := proxyManager.Health(ctx); err != nil {
+		return nil, fmt.Errorf("%s não disponível: %w", proxyName(), err)
+	}
+	return proxyManager, nil
+}
+
+// proxyName retorna o nome do proxy configurado para mensagens (ex: "Caddy")
+func proxyName() string {
+	switch proxy := loadGlobalConfig().ProxyOrDefault(); proxy {
+	case config.ProxyTraefik:
+		return "Traefik"
+	case config.ProxyNginx:
+		return "Nginx"
+	case config.ProxyBuiltin:
+		return "Proxy embutido"
+	case config.ProxyCaddy:
+		return "Caddy"
+	default:
+		return proxy
+	}
+}
@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/crom-tech/oi/internal/adapter/state"
+	"github.com/crom-tech/oi/internal/core/port"
+)
+
+// newStateStore cria o StateStore padrão (~/.oi/state)
+// Se não for possível, retorna nil e o orquestrador segue sem persistir estado
+func newStateStore() port.StateStore {
+	store, err := state.NewFileStore("")
+	if err != nil {
+		fmt.Printf("⚠️  Aviso: estado local indisponível: %v\n", err)
+		return nil
+	}
+	return store
+}
+
+// addNoProxyFlag registra --no-proxy e o nome antigo --no-caddy, obsoleto, na mesma variável
+func addNoProxyFlag(cmd *cobra.Command, noProxy *bool, usage string) {
+	cmd.Flags().BoolVar(noProxy, "no-proxy", false, usage)
+	cmd.Flags().BoolVar(noProxy, "no-caddy", false, usage)
+	_ = cmd.Flags().MarkDeprecated("no-caddy", "use --no-proxy")
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/crom-tech/oi/internal/adapter/state"
+	"github.com/crom-tech/oi/internal/config"
+)
+
+// NewHistoryCommand cria o comando "oi history"
+func NewHistoryCommand() *cobra.Command {
+	var path string
+	var project string
+	var all bool
+	var asJSON bool
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Mostra o histórico de deploys",
+		Long: `Lista o que foi implantado, quando, por quem e com qual resultado.
+O histórico fica em ~/.oi/state/<projeto>/history.jsonl.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projectName := project
+			if projectName == "" && !all {
+				// Tenta carregar do oi.json; sem ele, mostra todos os projetos
+				intent, err := config.LoadIntent(path)
+				if err == nil {
+					projectName = intent.Nome
+				}
+			}
+
+			store, err := state.NewFileStore("")
+			if err != nil {
+				return fmt.Errorf("❌ Erro ao acessar estado local: %w", err)
+			}
+
+			records, err := store.History(cmd.Context(), projectName)
+			if err != nil {
+				return fmt.Errorf("❌ Erro ao ler histórico: %w", err)
+			}
+
+			if limit > 0 && len(records) > limit {
+				records = records[len(records)-limit:]
+			}
+
+			if asJSON {
+				return writeJSON(os.Stdout, records)
+			}
+
+			if len(records) == 0 {
+				fmt.Println("📭 Nenhum deploy registrado")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "DATA\tPROJETO\tAÇÃO\tVERSÃO\tRESULTADO\tUSUÁRIO\tMOTIVO")
+			fmt.Fprintln(w, "----\t-------\t----\t------\t---------\t-------\t------")
+
+			for _, r := range records {
+				outcome := "✅ " + r.Outcome
+				if !r.Succeeded() {
+					outcome = "❌ " + r.Outcome
+				}
+
+				version := r.Version
+				if len(version) > 8 {
+					version = version[:8]
+				}
+
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					r.Timestamp.Local().Format("2006-01-02 15:04:05"),
+					r.Project,
+					r.Action,
+					version,
+					outcome,
+					r.User,
+					r.Reason,
+				)
+			}
+
+			w.Flush()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&path, "file", "f", ".", "Caminho para oi.json ou diretório")
+	cmd.Flags().StringVarP(&project, "project", "p", "", "Nome do projeto")
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "Mostra o histórico de todos os projetos")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Saída em JSON")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 0, "Mostra apenas as N entradas mais recentes")
+
+	return cmd
+}
@@ -1,14 +1,15 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"runtime"
 
 	"github.com/spf13/cobra"
 
-	"github.com/crom-tech/oi/internal/adapter/caddy"
-	"github.com/crom-tech/oi/internal/adapter/docker"
+	"github.com/crom-tech/oi/internal/adapter/podman"
+	"github.com/crom-tech/oi/internal/config"
 )
 
 // NewInfoCommand cria o comando "oi info"
@@ -16,45 +17,63 @@ func NewInfoCommand(version string) *cobra.Command {
 	return &cobra.Command{
 		Use:   "info",
 		Short: "Exibe informações do sistema e ambiente",
-		Long:  `Mostra detalhes sobre a instalação do OI, versões de dependências (Docker, Caddy) e saúde do sistema.`,
+		Long:  `Mostra detalhes sobre a instalação do OI, o runtime de containers (Docker ou Podman), o proxy (Caddy, Traefik, Nginx ou embutido) e a saúde do sistema.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			fmt.Printf("📦 OI - Orquestrador de Intenção\n")
 			fmt.Printf("   Versão: %s\n", version)
 			fmt.Printf("   OS/Arch: %s/%s\n", runtime.GOOS, runtime.GOARCH)
 			fmt.Println()
 
-			// Check Docker
-			fmt.Printf("🐳 Docker:\n")
-			dockerClient, err := docker.NewClient()
+			// Check Runtime
+			engine := loadGlobalConfig().Runtime.EngineOrDefault()
+			icon := "🐳"
+			if engine == config.RuntimePodman {
+				icon = "🦭"
+			}
+			fmt.Printf("%s Runtime: %s\n", icon, engine)
+			containerRuntime, err := newRuntime()
 			if err != nil {
 				fmt.Printf("   ❌ Erro ao conectar: %v\n", err)
 			} else {
-				if err := dockerClient.Ping(cmd.Context()); err != nil {
-					fmt.Printf("   ❌ Daemon não acessível: %v\n", err)
+				fmt.Printf("   Socket: %s\n", containerRuntime.Host())
+				if err := containerRuntime.Ping(cmd.Context()); err != nil {
+					fmt.Printf("   ❌ Runtime não acessível: %v\n", err)
 				} else {
-					fmt.Printf("   ✅ Daemon acessível\n")
+					if v, err := containerRuntime.Version(cmd.Context()); err == nil {
+						fmt.Printf("   ✅ Acessível (versão %s)\n", v)
+					} else {
+						fmt.Printf("   ✅ Acessível\n")
+					}
 
 					// List managed networks
-					nets, err := dockerClient.ListNetworks(cmd.Context())
+					nets, err := containerRuntime.ListNetworks(cmd.Context())
 					if err == nil {
 						fmt.Printf("   🌐 Redes Gerenciadas: %d\n", len(nets))
 					}
 				}
-				dockerClient.Close()
+				containerRuntime.Close()
 			}
-			fmt.Println()
 
-			// Check Caddy
-			fmt.Printf("🔒 Caddy Proxy:\n")
-			caddyManager := caddy.NewManager("")
-			if err := caddyManager.Health(cmd.Context()); err != nil {
-				fmt.Printf("   ⚠️  Caddy não detectado ou inacessível via API (:2019)\n")
-				fmt.Printf("       (Isso é normal se você usa --no-caddy)\n")
-			} else {
-				fmt.Printf("   ✅ API acessível\n")
+			// Outros runtimes encontrados na máquina
+			for _, name := range []string{config.RuntimeDocker, config.RuntimePodman} {
+				if host, ok := detectSocket(name); ok && name != engine {
+					fmt.Printf("   ℹ️  %s também detectado em %s (use --runtime %s)\n", name, host, name)
+				}
 			}
 			fmt.Println()
 
+			// Check Proxy
+			switch loadGlobalConfig().ProxyOrDefault() {
+			case config.ProxyTraefik:
+				printTraefikInfo(cmd.Context())
+			case config.ProxyNginx:
+				printNginxInfo(cmd.Context())
+			case config.ProxyBuiltin:
+				printBuiltinProxyInfo(cmd.Context())
+			default:
+				printCaddyInfo(cmd.Context())
+			}
+
 			// Check Config File
 			if _, err := os.Stat("oi.json"); err == nil {
 				fmt.Printf("📄 Arquivo oi.json detectado no diretório atual.\n")
@@ -66,3 +85,87 @@ func NewInfoCommand(version string) *cobra.Command {
 		},
 	}
 }
+
+// detectSocket procura o socket padrão de um runtime na máquina
+func detectSocket(engine string) (string, bool) {
+	switch engine {
+	case config.RuntimeDocker:
+		if host := os.Getenv("DOCKER_HOST"); host != "" {
+			return host, true
+		}
+		if info, err := os.Stat("/var/run/docker.sock"); err == nil && info.Mode()&os.ModeSocket != 0 {
+			return "unix:///var/run/docker.sock", true
+		}
+	case config.RuntimePodman:
+		return podman.DetectSocket()
+	}
+	return "", false
+}
+
+// printCaddyInfo mostra a configuração do Caddy e se a Admin API está acessível
+func printCaddyInfo(ctx context.Context) {
+	fmt.Printf("🔒 Caddy Proxy:\n")
+	caddyManager := newCaddyManager()
+	fmt.Printf("   Admin API: %s\n", caddyManager.AdminURL())
+	fmt.Printf("   Servidor gerenciado: %s\n", caddyManager.Server())
+	fmt.Printf("   Access log (canários): %s\n", caddyManager.AccessLog())
+	if err := caddyManager.Health(ctx); err != nil {
+		fmt.Printf("   ⚠️  Caddy não detectado ou inacessível via API\n")
+		fmt.Printf("       (Isso é normal se você usa --no-proxy)\n")
+	} else {
+		fmt.Printf("   ✅ API acessível\n")
+		if exists, err := caddyManager.HasServer(ctx); err == nil && !exists {
+			fmt.Printf("   ℹ️  Servidor '%s' ainda não existe (será criado no próximo 'oi up')\n", caddyManager.Server())
+		}
+	}
+	fmt.Println()
+}
+
+// printTraefikInfo mostra a configuração do Traefik e se a API está acessível
+func printTraefikInfo(ctx context.Context) {
+	fmt.Printf("🔀 Traefik Proxy:\n")
+	traefikManager := newTraefikManager()
+	fmt.Printf("   API: %s\n", traefikManager.APIURL())
+	fmt.Printf("   Diretório de rotas (file provider): %s\n", traefikManager.Dir())
+	if err := traefikManager.Health(ctx); err != nil {
+		fmt.Printf("   ⚠️  Traefik não detectado ou inacessível via API: %v\n", err)
+		fmt.Printf("       (Isso é normal se você usa --no-proxy)\n")
+	} else {
+		fmt.Printf("   ✅ API acessível, file provider ativo\n")
+	}
+	fmt.Println()
+}
+
+// printNginxInfo mostra a configuração do nginx e se o processo master está rodando
+func printNginxInfo(ctx context.Context) {
+	fmt.Printf("🟩 Nginx Proxy:\n")
+	nginxManager := newNginxManager()
+	fmt.Printf("   Diretório de server blocks: %s\n", nginxManager.Dir())
+	if bin, ok := nginxManager.Binary(); ok {
+		fmt.Printf("   Executável: %s (validação com nginx -t)\n", bin)
+	} else {
+		fmt.Printf("   Executável: não encontrado (checagem de sintaxe interna, reload via %s)\n", nginxManager.PidFile())
+	}
+	if err := nginxManager.Health(ctx); err != nil {
+		fmt.Printf("   ⚠️  Nginx não detectado: %v\n", err)
+		fmt.Printf("       (Isso é normal se você usa --no-proxy)\n")
+	} else {
+		fmt.Printf("   ✅ Processo master rodando\n")
+	}
+	fmt.Println()
+}
+
+// printBuiltinProxyInfo mostra se o "oi proxy serve" está rodando e quantas rotas tem
+func printBuiltinProxyInfo(ctx context.Context) {
+	fmt.Printf("🔀 Proxy embutido:\n")
+	proxyManager := newBuiltinProxyManager()
+	fmt.Printf("   Admin API: %s\n", proxyManager.Socket())
+	listen, routes, err := proxyManager.Status(ctx)
+	if err != nil {
+		fmt.Printf("   ⚠️  Proxy não está rodando (inicie com 'oi proxy serve'): %v\n", err)
+		fmt.Printf("       (Isso é normal se você usa --no-proxy)\n")
+	} else {
+		fmt.Printf("   ✅ Escutando em %s, %d rota(s)\n", listen, routes)
+	}
+	fmt.Println()
+}
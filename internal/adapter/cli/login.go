@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/moby/term"
+	"github.com/spf13/cobra"
+)
+
+// NewLoginCommand cria o comando "oi login"
+func NewLoginCommand() *cobra.Command {
+	var username string
+	var password string
+	var passwordStdin bool
+
+	cmd := &cobra.Command{
+		Use:   "login [registry]",
+		Short: "Autentica em um registry de imagens",
+		Long: `Valida usuário e senha (ou token) no registry e grava as credenciais no
+~/.docker/config.json, usando o credential helper configurado se houver.
+Os pulls de 'oi up' passam a usar essas credenciais.
+
+Sem registry, autentica no Docker Hub.
+
+Exemplos:
+  oi login ghcr.io -u meu-usuario
+  echo $TOKEN | oi login ghcr.io -u meu-usuario --password-stdin`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			server := ""
+			if len(args) > 0 {
+				server = args[0]
+			}
+
+			reader := bufio.NewReader(os.Stdin)
+			if username == "" {
+				fmt.Print("Usuário: ")
+				line, err := reader.ReadString('\n')
+				if err != nil && err != io.EOF {
+					return fmt.Errorf("❌ Erro ao ler usuário: %w", err)
+				}
+				username = strings.TrimSpace(line)
+			}
+			if username == "" {
+				return fmt.Errorf("❌ Usuário é obrigatório")
+			}
+
+			switch {
+			case passwordStdin:
+				data, err := io.ReadAll(reader)
+				if err != nil {
+					return fmt.Errorf("❌ Erro ao ler senha do stdin: %w", err)
+				}
+				password = strings.TrimRight(string(data), "\r\n")
+			case password != "":
+				fmt.Println("⚠️  Aviso: --password fica no histórico do shell, prefira --password-stdin")
+			default:
+				secret, err := readSecret(reader, "Senha: ")
+				if err != nil {
+					return fmt.Errorf("❌ Erro ao ler senha: %w", err)
+				}
+				password = secret
+			}
+			if password == "" {
+				return fmt.Errorf("❌ Senha é obrigatória")
+			}
+
+			containerRuntime, err := newRuntime()
+			if err != nil {
+				return fmt.Errorf("❌ Erro ao conectar com o runtime de containers: %w", err)
+			}
+			defer containerRuntime.Close()
+
+			registry, err := containerRuntime.Login(cmd.Context(), server, username, password)
+			if err != nil {
+				return fmt.Errorf("❌ %w", err)
+			}
+
+			fmt.Printf("✅ Autenticado em %s\n", registry)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&username, "username", "u", "", "Usuário")
+	cmd.Flags().StringVarP(&password, "password", "p", "", "Senha ou token (prefira --password-stdin)")
+	cmd.Flags().BoolVar(&passwordStdin, "password-stdin", false, "Lê a senha ou token do stdin")
+
+	return cmd
+}
+
+// readSecret lê uma linha do terminal sem ecoar os caracteres
+// Fora de um terminal (ex: pipe), lê a linha normalmente
+func readSecret(reader *bufio.Reader, prompt string) (string, error) {
+	fmt.Print(prompt)
+
+	fd, isTerminal := term.GetFdInfo(os.Stdin)
+	if isTerminal {
+		state, err := term.SaveState(fd)
+		if err != nil {
+			return "", err
+		}
+		if err := term.DisableEcho(fd, state); err != nil {
+			return "", err
+		}
+		defer func() {
+			term.RestoreTerminal(fd, state)
+			fmt.Println()
+		}()
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
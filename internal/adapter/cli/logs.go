@@ -7,7 +7,6 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/crom-tech/oi/internal/adapter/docker"
 	"github.com/crom-tech/oi/internal/config"
 	"github.com/crom-tech/oi/internal/core/service"
 )
@@ -16,6 +15,7 @@ import (
 func NewLogsCommand() *cobra.Command {
 	var path string
 	var project string
+	var serviceName string
 	var tail string
 
 	cmd := &cobra.Command{
@@ -23,12 +23,13 @@ func NewLogsCommand() *cobra.Command {
 		Short: "Stream de logs do container (ao vivo)",
 		Long:  `Exibe e acompanha os logs do container do projeto. Similar ao 'docker logs -f'.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runLogs(cmd.Context(), path, project, true, tail)
+			return runLogs(cmd.Context(), path, project, serviceName, true, tail)
 		},
 	}
 
 	cmd.Flags().StringVarP(&path, "file", "f", ".", "Caminho para oi.json")
 	cmd.Flags().StringVarP(&project, "project", "p", "", "Nome do projeto")
+	cmd.Flags().StringVarP(&serviceName, "service", "s", "", "Nome do serviço (padrão: todos)")
 	cmd.Flags().StringVar(&tail, "tail", "all", "Número de linhas para mostrar")
 
 	return cmd
@@ -38,6 +39,7 @@ func NewLogsCommand() *cobra.Command {
 func NewLogCommand() *cobra.Command {
 	var path string
 	var project string
+	var serviceName string
 	var tail string
 
 	cmd := &cobra.Command{
@@ -45,18 +47,19 @@ func NewLogCommand() *cobra.Command {
 		Short: "Exibe logs do container (dump completo)",
 		Long:  `Despeja todo o log do container e sai. Útil para grep ou análise rápida.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runLogs(cmd.Context(), path, project, false, tail)
+			return runLogs(cmd.Context(), path, project, serviceName, false, tail)
 		},
 	}
 
 	cmd.Flags().StringVarP(&path, "file", "f", ".", "Caminho para oi.json")
 	cmd.Flags().StringVarP(&project, "project", "p", "", "Nome do projeto")
+	cmd.Flags().StringVarP(&serviceName, "service", "s", "", "Nome do serviço (padrão: todos)")
 	cmd.Flags().StringVar(&tail, "tail", "all", "Número de linhas para mostrar")
 
 	return cmd
 }
 
-func runLogs(ctx context.Context, path, project string, follow bool, tail string) error {
+func runLogs(ctx context.Context, path, project, serviceName string, follow bool, tail string) error {
 	projectName := project
 	if projectName == "" {
 		intent, err := config.LoadIntent(path)
@@ -66,12 +69,12 @@ func runLogs(ctx context.Context, path, project string, follow bool, tail string
 		projectName = intent.Nome
 	}
 
-	dockerClient, err := docker.NewClient()
+	containerRuntime, err := newRuntime()
 	if err != nil {
-		return fmt.Errorf("❌ Erro ao conectar com Docker: %w", err)
+		return fmt.Errorf("❌ Erro ao conectar com o runtime de containers: %w", err)
 	}
-	defer dockerClient.Close()
+	defer containerRuntime.Close()
 
-	orchestrator := service.NewOrchestrator(dockerClient, nil)
-	return orchestrator.Logs(ctx, projectName, os.Stdout, os.Stderr, follow, tail)
+	orchestrator := service.NewOrchestrator(containerRuntime, nil, nil)
+	return orchestrator.Logs(ctx, projectName, serviceName, os.Stdout, os.Stderr, follow, tail)
 }
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/crom-tech/oi/internal/config"
+	"github.com/crom-tech/oi/internal/core/service"
+)
+
+// NewOutdatedCommand cria o comando "oi outdated"
+func NewOutdatedCommand() *cobra.Command {
+	var path string
+	var project string
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "outdated",
+		Short: "Lista serviços cuja tag aponta para outra imagem no registry",
+		Long: `Compara o digest implantado de cada serviço (label io.oi.digest) com o que o
+registry serve hoje para a mesma tag, sem baixar nada.
+
+Serviços desatualizados mudariam no próximo 'oi up' (ex: nginx:alpine recebeu
+uma nova imagem). Imagens construídas com 'build' não são comparadas.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projectName := project
+			if projectName == "" && !all {
+				intent, err := config.LoadIntent(path)
+				if err == nil {
+					projectName = intent.Nome
+				}
+			}
+
+			containerRuntime, err := newRuntime()
+			if err != nil {
+				return fmt.Errorf("❌ Erro ao conectar com o runtime de containers: %w", err)
+			}
+			defer containerRuntime.Close()
+
+			orchestrator := service.NewOrchestrator(containerRuntime, nil, newStateStore())
+
+			var filterProject string
+			if !all {
+				filterProject = projectName
+			}
+
+			fmt.Println("🔎 Consultando registries...")
+			drifts, err := orchestrator.Outdated(cmd.Context(), filterProject)
+			if err != nil {
+				return fmt.Errorf("❌ Erro ao verificar imagens: %w", err)
+			}
+			if len(drifts) == 0 {
+				fmt.Println("📭 Nenhum serviço em execução")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "PROJETO\tSERVIÇO\tIMAGEM\tEM EXECUÇÃO\tREGISTRY\tSITUAÇÃO")
+			fmt.Fprintln(w, "-------\t-------\t------\t-----------\t--------\t--------")
+
+			var outdated []string
+			for _, d := range drifts {
+				svc := d.Service
+				if svc == "" {
+					svc = "-"
+				}
+
+				situation := "✅ atualizado"
+				latest := shortDigest(d.Latest)
+				switch {
+				case d.Built:
+					situation = "🔨 build local"
+					latest = "-"
+				case d.Error != "":
+					situation = "⚠️  erro: " + d.Error
+				case d.Running == "":
+					situation = "❓ digest desconhecido (implantado antes do label)"
+				case d.Outdated():
+					situation = "🆕 desatualizado"
+				}
+				if d.Outdated() {
+					label := d.Project
+					if d.Service != "" {
+						label += "/" + d.Service
+					}
+					outdated = append(outdated, label)
+				}
+
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", d.Project, svc, d.Image, shortDigest(d.Running), latest, situation)
+			}
+			w.Flush()
+
+			fmt.Println()
+			if len(outdated) == 0 {
+				fmt.Println("✅ Nenhum serviço mudaria no próximo 'oi up' por causa da imagem")
+				return nil
+			}
+			fmt.Printf("🆕 %d serviço(s) mudariam no próximo 'oi up':\n", len(outdated))
+			for _, label := range outdated {
+				fmt.Printf("   • %s\n", label)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&path, "file", "f", ".", "Caminho para oi.json ou diretório")
+	cmd.Flags().StringVarP(&project, "project", "p", "", "Nome do projeto")
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "Verifica todos os projetos OI")
+
+	return cmd
+}
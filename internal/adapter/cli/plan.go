@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/crom-tech/oi/internal/config"
+	"github.com/crom-tech/oi/internal/core/domain"
+	"github.com/crom-tech/oi/internal/core/port"
+	"github.com/crom-tech/oi/internal/core/service"
+)
+
+// NewPlanCommand cria o comando "oi plan"
+func NewPlanCommand() *cobra.Command {
+	var path string
+	var noProxy bool
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "plan [arquivo]",
+		Short: "Mostra o que 'oi up' mudaria, sem aplicar",
+		Long: `Compara o oi.json com os containers em execução e as rotas do proxy,
+exibindo as diferenças sem alterar nada. Equivalente a 'oi up --dry-run'.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				path = args[0]
+			}
+
+			intent, err := config.LoadIntent(path)
+			if err != nil {
+				return fmt.Errorf("❌ Falha ao carregar %s: %w", path, err)
+			}
+
+			containerRuntime, err := newRuntime()
+			if err != nil {
+				return fmt.Errorf("❌ Erro ao conectar com o runtime de containers: %w", err)
+			}
+			defer containerRuntime.Close()
+
+			var proxyManager port.ProxyManager
+			if !noProxy {
+				if pm, err := connectProxy(cmd.Context()); err == nil {
+					proxyManager = pm
+				}
+			}
+
+			orchestrator := service.NewOrchestrator(containerRuntime, proxyManager, newStateStore())
+			plans, err := orchestrator.Plan(cmd.Context(), *intent)
+			if err != nil {
+				return fmt.Errorf("❌ Erro ao planejar: %w", err)
+			}
+
+			if asJSON {
+				return writeJSON(os.Stdout, plans)
+			}
+			for _, plan := range plans {
+				renderPlan(os.Stdout, plan)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&path, "file", "f", ".", "Caminho para oi.json ou diretório")
+	addNoProxyFlag(cmd, &noProxy, "Ignora as rotas do proxy")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Saída em JSON")
+
+	return cmd
+}
+
+// renderPlan exibe o plano em formato legível
+func renderPlan(w io.Writer, plan *domain.Plan) {
+	name := plan.Project
+	if plan.Service != "" {
+		name += "/" + plan.Service
+	}
+
+	switch plan.Action {
+	case domain.PlanCreate:
+		fmt.Fprintf(w, "📋 Plano para '%s': novo deploy\n", name)
+	case domain.PlanReconcile:
+		fmt.Fprintf(w, "📋 Plano para '%s': versão %s já implantada, nada a recriar\n", name, shortID(plan.Current))
+		if len(plan.Routes) == 0 {
+			fmt.Fprintf(w, "   (proxy em dia)\n")
+		}
+	default:
+		fmt.Fprintf(w, "📋 Plano para '%s': atualizar versão %s\n", name, shortID(plan.Current))
+	}
+
+	if len(plan.Changes) == 0 && plan.Action == domain.PlanUpdate {
+		fmt.Fprintf(w, "   (intenção idêntica à versão atual)\n")
+	}
+	for _, c := range plan.Changes {
+		switch {
+		case c.From == "":
+			fmt.Fprintf(w, "   + %s: %s\n", c.Field, c.To)
+		case c.To == "":
+			fmt.Fprintf(w, "   - %s: %s\n", c.Field, c.From)
+		default:
+			fmt.Fprintf(w, "   ~ %s: %s -> %s\n", c.Field, c.From, c.To)
+		}
+	}
+
+	if len(plan.Routes) > 0 {
+		fmt.Fprintf(w, "🔀 Proxy:\n")
+		for _, r := range plan.Routes {
+			switch r.Action {
+			case domain.RouteAdd:
+				fmt.Fprintf(w, "   + %s -> %s\n", r.Domain, r.To)
+			case domain.RouteUpdate:
+				fmt.Fprintf(w, "   ~ %s: %s -> %s\n", r.Domain, r.From, r.To)
+			case domain.RouteRemove:
+				fmt.Fprintf(w, "   - %s (%s)\n", r.Domain, r.From)
+			}
+		}
+	}
+
+	for _, name := range plan.Stop {
+		fmt.Fprintf(w, "🛑 Parar: %s\n", name)
+	}
+	for _, name := range plan.Remove {
+		fmt.Fprintf(w, "🧹 Remover: %s\n", name)
+	}
+}
+
+// writeJSON escreve v como JSON indentado
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// shortID retorna os 8 primeiros caracteres de uma versão ou ID
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// shortDigest abrevia um digest para exibição ("nginx@sha256:1a2b..." -> "sha256:1a2b3c4d5e6f")
+func shortDigest(digest string) string {
+	if digest == "" {
+		return "-"
+	}
+	hash := domain.DigestHash(digest)
+	if algo, hex, ok := strings.Cut(hash, ":"); ok && len(hex) > 12 {
+		return algo + ":" + hex[:12]
+	}
+	return hash
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/crom-tech/oi/internal/config"
+	"github.com/crom-tech/oi/internal/core/service"
+)
+
+// NewPromoteCommand cria o comando "oi promote"
+func NewPromoteCommand() *cobra.Command {
+	var path string
+	var project string
+	var serviceName string
+
+	cmd := &cobra.Command{
+		Use:   "promote",
+		Short: "Envia 100% do tráfego ao canário em andamento",
+		Long: `Promove o canário iniciado com 'oi up --canary': o proxy passa a enviar
+todo o tráfego para a nova versão, que se torna a ativa, e a versão
+estável é parada e retida para rollback como em um deploy normal.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCanaryCommand(cmd, path, project, func(o *service.Orchestrator, projectName string) error {
+				return o.Promote(cmd.Context(), projectName, serviceName)
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&path, "file", "f", ".", "Caminho para oi.json ou diretório")
+	cmd.Flags().StringVarP(&project, "project", "p", "", "Nome do projeto (sobrescreve oi.json)")
+	cmd.Flags().StringVarP(&serviceName, "service", "s", "", "Serviço (obrigatório com vários canários no projeto)")
+
+	return cmd
+}
+
+// NewAbortCommand cria o comando "oi abort"
+func NewAbortCommand() *cobra.Command {
+	var path string
+	var project string
+	var serviceName string
+
+	cmd := &cobra.Command{
+		Use:   "abort",
+		Short: "Descarta o canário em andamento",
+		Long: `Aborta o canário iniciado com 'oi up --canary': o proxy volta a enviar
+todo o tráfego para a versão estável e os containers do canário são removidos.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCanaryCommand(cmd, path, project, func(o *service.Orchestrator, projectName string) error {
+				return o.Abort(cmd.Context(), projectName, serviceName)
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&path, "file", "f", ".", "Caminho para oi.json ou diretório")
+	cmd.Flags().StringVarP(&project, "project", "p", "", "Nome do projeto (sobrescreve oi.json)")
+	cmd.Flags().StringVarP(&serviceName, "service", "s", "", "Serviço (obrigatório com vários canários no projeto)")
+
+	return cmd
+}
+
+// runCanaryCommand monta o orquestrador para promote/abort
+// Diferente do rollback, o proxy é obrigatório: sem ele não há como mover o tráfego
+func runCanaryCommand(cmd *cobra.Command, path, project string, run func(*service.Orchestrator, string) error) error {
+	projectName := project
+	if projectName == "" {
+		intent, err := config.LoadIntent(path)
+		if err != nil {
+			return fmt.Errorf("❌ Especifique --project ou tenha um oi.json válido")
+		}
+		projectName = intent.Nome
+	}
+
+	containerRuntime, err := newRuntime()
+	if err != nil {
+		return fmt.Errorf("❌ Erro ao conectar com o runtime de containers: %w", err)
+	}
+	defer containerRuntime.Close()
+
+	proxyManager, err := connectProxy(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	orchestrator := service.NewOrchestrator(containerRuntime, proxyManager, newStateStore())
+	return run(orchestrator, projectName)
+}
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/crom-tech/oi/internal/adapter/oiproxy"
+)
+
+// NewProxyCommand cria o comando "oi proxy" (proxy reverso embutido)
+func NewProxyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "proxy",
+		Short: "Proxy reverso embutido, sem depender de Caddy, Traefik ou nginx",
+		Long: `O proxy embutido roteia por domínio e prefixo de caminho para os containers do OI, com troca
+atômica de upstreams no Blue-Green e suporte a websockets.
+
+Rode 'oi proxy serve' como serviço e use --proxy builtin (ou "proxy": "builtin"
+no ~/.oi/config.json) nos demais comandos.`,
+	}
+	cmd.AddCommand(newProxyServeCommand())
+	cmd.AddCommand(newProxyRoutesCommand())
+	return cmd
+}
+
+// newProxyServeCommand cria o comando "oi proxy serve"
+func newProxyServeCommand() *cobra.Command {
+	var listen string
+	var socket string
+	var statePath string
+	var noResolve bool
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Roda o proxy embutido (tráfego HTTP + admin API no socket unix)",
+		Long: `Escuta o tráfego HTTP e encaminha cada requisição para os upstreams da rota
+do domínio (Host). As rotas chegam pela admin API no socket unix, usada pelo
+'oi up', e são gravadas no arquivo de estado para sobreviver a reinícios.
+
+Como o proxy roda fora das networks dos containers, o nome de cada upstream é
+traduzido para o IP do container pelo runtime (desligue com --no-resolve se o
+proxy rodar numa network com DNS dos containers).
+
+O proxy não termina TLS: publique-o atrás de um balanceador ou use a porta 80.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := loadGlobalConfig()
+			if listen == "" {
+				listen = cfg.Builtin.Listen
+			}
+			if listen == "" {
+				listen = oiproxy.DefaultListen
+			}
+			if socket == "" {
+				socket = cfg.Builtin.Socket
+			}
+			if socket == "" {
+				socket = oiproxy.DefaultSocket
+			}
+			if statePath == "" {
+				statePath = cfg.Builtin.State
+			}
+			if statePath == "" {
+				statePath = oiproxy.DefaultState
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+
+			var resolver oiproxy.Resolver
+			if !noResolve {
+				containerRuntime, err := newRuntime()
+				if err != nil {
+					fmt.Printf("⚠️  Aviso: runtime de containers indisponível, upstreams resolvidos por DNS: %v\n", err)
+				} else {
+					defer containerRuntime.Close()
+					resolver = func(ctx context.Context, addr string) (string, error) {
+						host, port, err := net.SplitHostPort(addr)
+						if err != nil {
+							return "", err
+						}
+						ctr, err := containerRuntime.Inspect(ctx, host)
+						if err != nil {
+							return "", err
+						}
+						// Sem IP alcançável (ex: Podman rootless), usa a porta publicada no host
+						if ctr.IP != "" {
+							return net.JoinHostPort(ctr.IP, port), nil
+						}
+						if ctr.PublicPort != 0 {
+							return net.JoinHostPort("127.0.0.1", strconv.Itoa(ctr.PublicPort)), nil
+						}
+						return "", nil
+					}
+				}
+			}
+
+			server := oiproxy.NewServer(statePath, resolver)
+			if err := server.Load(); err != nil {
+				return fmt.Errorf("❌ Erro ao carregar rotas: %w", err)
+			}
+
+			fmt.Printf("🔀 Proxy embutido escutando em %s\n", listen)
+			fmt.Printf("   Admin API: %s\n", socket)
+			fmt.Printf("   Rotas: %d carregadas de %s\n", len(server.Routes()), statePath)
+			if err := server.Serve(ctx, listen, socket); err != nil {
+				return fmt.Errorf("❌ %w", err)
+			}
+			fmt.Println("\n👋 Encerrando proxy")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&listen, "listen", "", "Endereço do tráfego HTTP (padrão :80)")
+	cmd.Flags().StringVar(&socket, "socket", "", "Socket unix da admin API (padrão /run/oi/proxy.sock)")
+	cmd.Flags().StringVar(&statePath, "state", "", "Arquivo das rotas persistidas (padrão /var/lib/oi/proxy-routes.json)")
+	cmd.Flags().BoolVar(&noResolve, "no-resolve", false, "Não traduz nomes de containers pelo runtime (usa só DNS)")
+
+	return cmd
+}
+
+// newProxyRoutesCommand cria o comando "oi proxy routes"
+func newProxyRoutesCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "routes",
+		Short: "Lista as rotas carregadas no proxy embutido",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			proxyManager := newBuiltinProxyManager()
+			routes, err := proxyManager.Routes(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("❌ Proxy embutido não acessível em %s: %w", proxyManager.Socket(), err)
+			}
+			if len(routes) == 0 {
+				fmt.Println("📭 Nenhuma rota no proxy embutido")
+				return nil
+			}
+			for _, r := range routes {
+				fmt.Printf("🔀 %s (%s, %s)\n", r.Label(), r.ID, r.PolicyOrDefault())
+				for _, u := range r.Upstreams {
+					if u.Weight > 0 {
+						fmt.Printf("   → %s (peso %d)\n", u.Address(), u.Weight)
+					} else {
+						fmt.Printf("   → %s\n", u.Address())
+					}
+				}
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/crom-tech/oi/internal/config"
+	"github.com/crom-tech/oi/internal/core/port"
+	"github.com/crom-tech/oi/internal/core/service"
+)
+
+// NewRollbackCommand cria o comando "oi rollback"
+func NewRollbackCommand() *cobra.Command {
+	var path string
+	var project string
+	var serviceName string
+	var to string
+	var noProxy bool
+
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Volta para uma versão anterior retida",
+		Long: `Inicia uma versão anterior (mantida parada após o último 'oi up'),
+aguarda o health check, aponta o proxy para ela e para a versão atual.
+
+Sem --to, volta para a versão retida mais recente.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projectName := project
+			if projectName == "" {
+				intent, err := config.LoadIntent(path)
+				if err != nil {
+					return fmt.Errorf("❌ Especifique --project ou tenha um oi.json válido")
+				}
+				projectName = intent.Nome
+			}
+
+			containerRuntime, err := newRuntime()
+			if err != nil {
+				return fmt.Errorf("❌ Erro ao conectar com o runtime de containers: %w", err)
+			}
+			defer containerRuntime.Close()
+
+			var proxyManager port.ProxyManager
+			if !noProxy {
+				if pm, err := connectProxy(cmd.Context()); err != nil {
+					fmt.Printf("⚠️  %v, pulando configuração de proxy\n", err)
+				} else {
+					proxyManager = pm
+				}
+			}
+
+			orchestrator := service.NewOrchestrator(containerRuntime, proxyManager, newStateStore())
+			return orchestrator.Rollback(cmd.Context(), projectName, serviceName, to)
+		},
+	}
+
+	cmd.Flags().StringVarP(&path, "file", "f", ".", "Caminho para oi.json ou diretório")
+	cmd.Flags().StringVarP(&project, "project", "p", "", "Nome do projeto (sobrescreve oi.json)")
+	cmd.Flags().StringVarP(&serviceName, "service", "s", "", "Serviço (obrigatório em projetos com vários serviços)")
+	cmd.Flags().StringVar(&to, "to", "", "Versão alvo (prefixo exibido em 'oi status')")
+	addNoProxyFlag(cmd, &noProxy, "Desabilita integração com o proxy (Caddy, Traefik, Nginx ou embutido)")
+
+	return cmd
+}
@@ -5,7 +5,6 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/crom-tech/oi/internal/adapter/docker"
 	"github.com/crom-tech/oi/internal/config"
 	"github.com/crom-tech/oi/internal/core/service"
 )
@@ -35,13 +34,13 @@ func NewStartCommand() *cobra.Command {
 				}
 			}
 
-			dockerClient, err := docker.NewClient()
+			containerRuntime, err := newRuntime()
 			if err != nil {
-				return fmt.Errorf("❌ Erro ao conectar com Docker: %w", err)
+				return fmt.Errorf("❌ Erro ao conectar com o runtime de containers: %w", err)
 			}
-			defer dockerClient.Close()
+			defer containerRuntime.Close()
 
-			orchestrator := service.NewOrchestrator(dockerClient, nil)
+			orchestrator := service.NewOrchestrator(containerRuntime, nil, newStateStore())
 			return orchestrator.Start(cmd.Context(), projectName)
 		},
 	}
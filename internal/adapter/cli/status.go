@@ -7,8 +7,8 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/crom-tech/oi/internal/adapter/docker"
 	"github.com/crom-tech/oi/internal/config"
+	"github.com/crom-tech/oi/internal/core/domain"
 	"github.com/crom-tech/oi/internal/core/service"
 )
 
@@ -33,15 +33,15 @@ func NewStatusCommand() *cobra.Command {
 				}
 			}
 
-			// Cria Docker client
-			dockerClient, err := docker.NewClient()
+			// Cria o client do runtime (Docker ou Podman)
+			containerRuntime, err := newRuntime()
 			if err != nil {
-				return fmt.Errorf("❌ Erro ao conectar com Docker: %w", err)
+				return fmt.Errorf("❌ Erro ao conectar com o runtime de containers: %w", err)
 			}
-			defer dockerClient.Close()
+			defer containerRuntime.Close()
 
-			// Cria orchestrator (sem proxy para status)
-			orchestrator := service.NewOrchestrator(dockerClient, nil)
+			// Cria orchestrator (sem proxy para status; o estado local mostra canários)
+			orchestrator := service.NewOrchestrator(containerRuntime, nil, newStateStore())
 
 			// Lista containers
 			var filterProject string
@@ -62,42 +62,71 @@ func NewStatusCommand() *cobra.Command {
 				return nil
 			}
 
-			// Formata tabela
+			// Formata tabela: réplicas agrupadas por versão
 			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-			fmt.Fprintln(w, "PROJETO\tNOME\tSTATUS\tHEALTH\tVERSÃO")
-			fmt.Fprintln(w, "-------\t----\t------\t------\t------")
+			fmt.Fprintln(w, "PROJETO\tSERVIÇO\tVERSÃO\tDIGEST\tRÉPLICAS\tNOME\tSTATUS\tHEALTH")
+			fmt.Fprintln(w, "-------\t-------\t------\t------\t--------\t----\t------\t------")
 
-			for _, c := range containers {
-				statusIcon := "⏸️"
-				if c.Status == "running" {
-					statusIcon = "▶️"
+			for _, group := range domain.GroupByVersion(containers) {
+				version := group.Version
+				if len(version) > 8 {
+					version = version[:8]
 				}
 
-				healthIcon := "❓"
-				switch c.Health {
-				case "healthy":
-					healthIcon = "💚"
-				case "unhealthy":
-					healthIcon = "❤️"
-				case "starting":
-					healthIcon = "💛"
+				service := group.Service
+				if service == "" {
+					service = "-"
 				}
 
-				version := c.Version
-				if len(version) > 8 {
-					version = version[:8]
+				replicas := fmt.Sprintf("%d/%d", group.Running(), len(group.Containers))
+
+				for i, c := range group.Containers {
+					statusIcon := "⏸️"
+					if c.Status == "running" {
+						statusIcon = "▶️"
+					}
+
+					healthIcon := "❓"
+					switch c.Health {
+					case "healthy":
+						healthIcon = "💚"
+					case "unhealthy":
+						healthIcon = "❤️"
+					case "starting":
+						healthIcon = "💛"
+					}
+
+					// Projeto, serviço, versão e digest só aparecem na primeira réplica do grupo
+					if i == 0 {
+						fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t", c.Project, service, version, shortDigest(c.Digest), replicas)
+					} else {
+						fmt.Fprint(w, "\t\t\t\t\t")
+					}
+					fmt.Fprintf(w, "%s\t%s %s\t%s\n",
+						c.Name,
+						statusIcon, c.Status,
+						healthIcon,
+					)
 				}
-
-				fmt.Fprintf(w, "%s\t%s\t%s %s\t%s\t%s\n",
-					c.Project,
-					c.Name,
-					statusIcon, c.Status,
-					healthIcon,
-					version,
-				)
 			}
 
 			w.Flush()
+
+			canaries, err := orchestrator.Canaries(cmd.Context(), filterProject)
+			if err != nil {
+				return fmt.Errorf("❌ Erro ao listar canários: %w", err)
+			}
+			for _, c := range canaries {
+				label := c.Project
+				if c.Service != "" {
+					label += "/" + c.Service
+				}
+				fmt.Printf("\n🐤 Canário em andamento em '%s': %s recebe %d%% do tráfego (estável: %s, desde %s)\n",
+					label, shortID(c.Version), c.Weight, shortID(c.Stable), c.StartedAt.Local().Format("2006-01-02 15:04:05"))
+			}
+			if len(canaries) > 0 {
+				fmt.Println("   Use 'oi promote' ou 'oi abort' para concluir")
+			}
 			return nil
 		},
 	}
@@ -2,13 +2,13 @@ package cli
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/spf13/cobra"
 
-	"github.com/crom-tech/oi/internal/adapter/caddy"
-	"github.com/crom-tech/oi/internal/adapter/docker"
 	"github.com/crom-tech/oi/internal/config"
+	"github.com/crom-tech/oi/internal/core/domain"
 	"github.com/crom-tech/oi/internal/core/port"
 	"github.com/crom-tech/oi/internal/core/service"
 )
@@ -16,10 +16,14 @@ import (
 // NewUpCommand cria o comando "oi up"
 func NewUpCommand() *cobra.Command {
 	var path string
-	var noCaddy bool
+	var noProxy bool
 	var live bool
 	var all bool
 	var filter string
+	var dryRun bool
+	var force bool
+	var asJSON bool
+	var canary int
 
 	cmd := &cobra.Command{
 		Use:   "up",
@@ -28,8 +32,19 @@ func NewUpCommand() *cobra.Command {
 (Docker/Rede/SSL) corresponda exatamente à intenção descrita.
 
 Usa Blue-Green deployment para zero-downtime.
-Se o deploy falhar, mantém a versão anterior funcional.`,
+Se o deploy falhar, mantém a versão anterior funcional.
+Se nada mudou (intenção e digest da imagem), apenas reconcilia o proxy.
+
+Com --canary N, a nova versão recebe N% do tráfego ao lado da atual
+até 'oi promote' (troca completa) ou 'oi abort' (descarta o canário).`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if asJSON && !dryRun {
+				return fmt.Errorf("❌ --json só pode ser usado com --dry-run")
+			}
+			if canary < 0 || canary > 99 {
+				return fmt.Errorf("❌ --canary deve estar entre 1 e 99")
+			}
+
 			var targetFiles []string
 
 			// 1. Determina arquivos alvo
@@ -55,40 +70,64 @@ Se o deploy falhar, mantém a versão anterior funcional.`,
 				return fmt.Errorf("❌ Nenhum arquivo de configuração encontrado")
 			}
 
-			fmt.Printf("🎯 Processando %d arquivo(s)...\n", len(targetFiles))
+			if !asJSON {
+				fmt.Printf("🎯 Processando %d arquivo(s)...\n", len(targetFiles))
+			}
 
 			// Cria clientes (reutilizados para todos os deploys)
-			dockerClient, err := docker.NewClient()
+			containerRuntime, err := newRuntime()
 			if err != nil {
-				return fmt.Errorf("❌ Erro ao conectar com Docker: %w", err)
+				return fmt.Errorf("❌ Erro ao conectar com o runtime de containers: %w", err)
 			}
-			defer dockerClient.Close()
+			defer containerRuntime.Close()
 
 			var proxyManager port.ProxyManager
-			if !noCaddy {
-				caddyManager := caddy.NewManager("")
-				if err := caddyManager.Health(cmd.Context()); err != nil {
-					fmt.Printf("⚠️  Caddy não disponível, pulando configuração de proxy\n")
+			if !noProxy {
+				if pm, err := connectProxy(cmd.Context()); err != nil {
+					if !asJSON {
+						fmt.Printf("⚠️  %v, pulando configuração de proxy\n", err)
+					}
 				} else {
-					proxyManager = caddyManager
+					proxyManager = pm
 				}
 			}
 
-			orchestrator := service.NewOrchestrator(dockerClient, proxyManager)
+			orchestrator := service.NewOrchestrator(containerRuntime, proxyManager, newStateStore())
 
 			// 2. Loop de execução
 			var errs []error
+			var plans []*domain.Plan
 			for _, p := range targetFiles {
-				fmt.Printf("\n📂 Lendo configuração: %s\n", p)
+				if !asJSON {
+					fmt.Printf("\n📂 Lendo configuração: %s\n", p)
+				}
 
 				intent, err := config.LoadIntent(p)
 				if err != nil {
-					fmt.Printf("❌ Falha ao carregar %s: %v\n", p, err)
+					fmt.Fprintf(os.Stderr, "❌ Falha ao carregar %s: %v\n", p, err)
 					errs = append(errs, err)
 					continue
 				}
 
-				if err := orchestrator.Up(cmd.Context(), *intent, live); err != nil {
+				// --dry-run: apenas planeja, sem tocar em nada
+				if dryRun {
+					intentPlans, err := orchestrator.Plan(cmd.Context(), *intent)
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "❌ Falha ao planejar %s: %v\n", intent.Nome, err)
+						errs = append(errs, err)
+						continue
+					}
+					plans = append(plans, intentPlans...)
+					if !asJSON {
+						for _, plan := range intentPlans {
+							renderPlan(os.Stdout, plan)
+						}
+					}
+					continue
+				}
+
+				opts := service.UpOptions{Live: live, Force: force, Canary: canary}
+				if err := orchestrator.Up(cmd.Context(), *intent, opts); err != nil {
 					fmt.Printf("❌ Falha no deploy de %s: %v\n", intent.Nome, err)
 					errs = append(errs, err)
 				} else {
@@ -96,6 +135,12 @@ Se o deploy falhar, mantém a versão anterior funcional.`,
 				}
 			}
 
+			if asJSON {
+				if err := writeJSON(os.Stdout, plans); err != nil {
+					return err
+				}
+			}
+
 			if len(errs) > 0 {
 				return fmt.Errorf("ocorreram %d erros durante o processamento", len(errs))
 			}
@@ -104,10 +149,14 @@ Se o deploy falhar, mantém a versão anterior funcional.`,
 	}
 
 	cmd.Flags().StringVarP(&path, "file", "f", ".", "Caminho para oi.json ou diretório contendo")
-	cmd.Flags().BoolVar(&noCaddy, "no-caddy", false, "Desabilita integração com Caddy")
+	addNoProxyFlag(cmd, &noProxy, "Desabilita integração com o proxy (Caddy, Traefik, Nginx ou embutido)")
 	cmd.Flags().BoolVar(&live, "live", false, "Habilita modo de desenvolvimento com volumes")
 	cmd.Flags().BoolVar(&all, "all", false, "Processa todos os arquivos .json no diretório atual")
 	cmd.Flags().StringVar(&filter, "filter", "", "Filtra arquivos por padrão glob (ex: 'data/oi-*.json')")
+	cmd.Flags().BoolVar(&force, "force", false, "Recria o container mesmo se nada mudou")
+	cmd.Flags().IntVar(&canary, "canary", 0, "Envia N% do tráfego para a nova versão (ver 'oi promote' e 'oi abort')")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Mostra o que mudaria, sem aplicar (igual a 'oi plan')")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Com --dry-run, exibe os planos em JSON")
 
 	return cmd
 }
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/crom-tech/oi/internal/config"
+	"github.com/crom-tech/oi/internal/core/service"
+)
+
+// NewVolumesCommand cria o comando "oi volumes"
+func NewVolumesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "volumes",
+		Aliases: []string{"volume", "vol"},
+		Short:   "Gerencia os volumes nomeados dos projetos",
+		Long: `Volumes nomeados são declarados na seção "volumes" do oi.json e
+sobrevivem a deploys e a 'oi down'. Use os subcomandos para listá-los ou removê-los.`,
+	}
+
+	cmd.AddCommand(newVolumesListCommand())
+	cmd.AddCommand(newVolumesRemoveCommand())
+
+	return cmd
+}
+
+// newVolumesListCommand cria o comando "oi volumes ls"
+func newVolumesListCommand() *cobra.Command {
+	var path string
+	var project string
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "Lista volumes nomeados",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projectName := project
+			if projectName == "" && !all {
+				// Tenta carregar do oi.json; sem ele, lista todos os projetos
+				intent, err := config.LoadIntent(path)
+				if err == nil {
+					projectName = intent.Nome
+				}
+			}
+
+			containerRuntime, err := newRuntime()
+			if err != nil {
+				return fmt.Errorf("❌ Erro ao conectar com o runtime de containers: %w", err)
+			}
+			defer containerRuntime.Close()
+
+			orchestrator := service.NewOrchestrator(containerRuntime, nil, nil)
+			volumes, err := orchestrator.Volumes(cmd.Context(), projectName)
+			if err != nil {
+				return fmt.Errorf("❌ %w", err)
+			}
+
+			if len(volumes) == 0 {
+				fmt.Println("📭 Nenhum volume encontrado")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "PROJETO\tVOLUME\tNOME\tCRIADO")
+			fmt.Fprintln(w, "-------\t------\t----\t------")
+
+			for _, v := range volumes {
+				created := "-"
+				if !v.CreatedAt.IsZero() {
+					created = v.CreatedAt.Local().Format("2006-01-02 15:04:05")
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", v.Project, v.Key, v.Name, created)
+			}
+
+			w.Flush()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&path, "file", "f", ".", "Caminho para oi.json ou diretório")
+	cmd.Flags().StringVarP(&project, "project", "p", "", "Nome do projeto (sobrescreve oi.json)")
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "Lista volumes de todos os projetos")
+
+	return cmd
+}
+
+// newVolumesRemoveCommand cria o comando "oi volumes rm"
+func newVolumesRemoveCommand() *cobra.Command {
+	var path string
+	var project string
+
+	cmd := &cobra.Command{
+		Use:     "rm <volume>...",
+		Aliases: []string{"remove"},
+		Short:   "Remove volumes nomeados (apaga os dados)",
+		Long: `Remove volumes pelo nome declarado no oi.json (ex: pgdata) ou pelo nome
+real exibido em 'oi volumes ls' (ex: oi-loja.pgdata).
+Volumes em uso por algum container, mesmo parado, não podem ser removidos.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projectName := project
+			if projectName == "" {
+				// Sem projeto, apenas nomes reais são inequívocos
+				intent, err := config.LoadIntent(path)
+				if err == nil {
+					projectName = intent.Nome
+				}
+			}
+
+			containerRuntime, err := newRuntime()
+			if err != nil {
+				return fmt.Errorf("❌ Erro ao conectar com o runtime de containers: %w", err)
+			}
+			defer containerRuntime.Close()
+
+			orchestrator := service.NewOrchestrator(containerRuntime, nil, nil)
+			if err := orchestrator.RemoveVolumes(cmd.Context(), projectName, args); err != nil {
+				return fmt.Errorf("❌ %w", err)
+			}
+
+			fmt.Println("✅ Volumes removidos")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&path, "file", "f", ".", "Caminho para oi.json ou diretório")
+	cmd.Flags().StringVarP(&project, "project", "p", "", "Nome do projeto (sobrescreve oi.json)")
+
+	return cmd
+}
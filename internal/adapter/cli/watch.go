@@ -0,0 +1,216 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/crom-tech/oi/internal/config"
+	"github.com/crom-tech/oi/internal/core/domain"
+	"github.com/crom-tech/oi/internal/core/port"
+	"github.com/crom-tech/oi/internal/core/service"
+)
+
+// NewWatchImagesCommand cria o comando "oi watch-images"
+func NewWatchImagesCommand() *cobra.Command {
+	var interval time.Duration
+	var once bool
+	var noProxy bool
+
+	cmd := &cobra.Command{
+		Use:   "watch-images [arquivos...]",
+		Short: "Reimplanta serviços com auto_update quando sai uma imagem nova",
+		Long: `Consulta periodicamente o registry de cada serviço com 'auto_update' no
+oi.json e, quando há uma imagem nova, faz o mesmo deploy Blue-Green do 'oi up':
+a nova versão só recebe tráfego se passar no health check, senão a atual fica.
+
+Sem arquivos, acompanha os oi.json dos projetos em execução (do último 'oi up'
+de cada um no histórico). Com as políticas patch e minor, a nova tag só é
+gravada no oi.json depois que o deploy dela passa no health check; uma imagem
+que falhou não é tentada de novo até sair outra.
+
+Políticas (auto_update):
+  tag     mesma tag, digest novo (ex: nginx:alpine republicada)
+  patch   também avança 1.25.3 -> 1.25.4
+  minor   também avança 1.25.3 -> 1.26.0`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if interval <= 0 {
+				return fmt.Errorf("❌ --interval deve ser positivo")
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+
+			containerRuntime, err := newRuntime()
+			if err != nil {
+				return fmt.Errorf("❌ Erro ao conectar com o runtime de containers: %w", err)
+			}
+			defer containerRuntime.Close()
+
+			w := &imageWatcher{
+				files:    args,
+				interval: interval,
+				noProxy:  noProxy,
+				runtime:  containerRuntime,
+				state:    newStateStore(),
+				checked:  make(map[string]time.Time),
+				failed:   make(map[string]string),
+			}
+
+			if once {
+				w.cycle(ctx)
+				return nil
+			}
+
+			fmt.Printf("👀 Observando imagens a cada %s (Ctrl+C para sair)\n", interval)
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				w.cycle(ctx)
+				select {
+				case <-ctx.Done():
+					fmt.Println("\n👋 Encerrando watcher")
+					return nil
+				case <-ticker.C:
+				}
+			}
+		},
+	}
+
+	cmd.Flags().DurationVar(&interval, "interval", 5*time.Minute, "Intervalo entre verificações (auto_update.interval pode espaçar mais)")
+	cmd.Flags().BoolVar(&once, "once", false, "Faz uma única verificação e sai (ex: para usar com cron)")
+	addNoProxyFlag(cmd, &noProxy, "Desabilita integração com o proxy (Caddy, Traefik, Nginx ou embutido)")
+
+	return cmd
+}
+
+// imageWatcher guarda o estado do 'oi watch-images' entre as verificações
+type imageWatcher struct {
+	files    []string
+	interval time.Duration
+	noProxy  bool
+	runtime  port.ContainerRuntime
+	state    port.StateStore
+	// checked guarda a última verificação de cada serviço ("arquivo#serviço")
+	checked map[string]time.Time
+	// failed guarda a imagem ("imagem@digest") cujo deploy falhou em cada serviço,
+	// para não reimplantá-la a cada ciclo
+	failed map[string]string
+}
+
+// cycle verifica todos os serviços com auto_update e implanta os que têm imagem nova
+// Falhas são exibidas e não interrompem o watcher
+func (w *imageWatcher) cycle(ctx context.Context) {
+	// O proxy é verificado a cada ciclo: sem ele, um deploy publicaria a porta no host
+	// em vez de trocar a rota, então a verificação é adiada
+	var proxyManager port.ProxyManager
+	if !w.noProxy {
+		pm, err := connectProxy(ctx)
+		if err != nil {
+			fmt.Printf("⚠️  [%s] %v, verificação adiada\n", timestamp(), err)
+			return
+		}
+		proxyManager = pm
+	}
+	orchestrator := service.NewOrchestrator(w.runtime, proxyManager, w.state)
+
+	files := w.files
+	if len(files) == 0 {
+		sources, err := orchestrator.DeployedSources(ctx)
+		if err != nil {
+			fmt.Printf("❌ [%s] %v\n", timestamp(), err)
+			return
+		}
+		files = sources
+	}
+
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return
+		}
+		w.checkFile(ctx, orchestrator, file)
+	}
+}
+
+// checkFile verifica os serviços de um oi.json
+func (w *imageWatcher) checkFile(ctx context.Context, orchestrator *service.Orchestrator, file string) {
+	intent, err := config.LoadIntent(file)
+	if err != nil {
+		fmt.Printf("❌ [%s] Falha ao carregar %s: %v\n", timestamp(), file, err)
+		return
+	}
+
+	services, err := serviceIntents(intent)
+	if err != nil {
+		fmt.Printf("❌ [%s] %s: %v\n", timestamp(), file, err)
+		return
+	}
+
+	for _, svc := range services {
+		if svc.AutoUpdate == nil {
+			continue
+		}
+
+		key := file + "#" + svc.Servico
+		if last, ok := w.checked[key]; ok && time.Since(last) < svc.AutoUpdate.IntervalOr(w.interval) {
+			continue
+		}
+		w.checked[key] = time.Now()
+
+		update, err := orchestrator.CheckImageUpdate(ctx, svc)
+		if err != nil {
+			fmt.Printf("⚠️  [%s] %s: %v\n", timestamp(), svc.Label(), err)
+			continue
+		}
+		if update == nil {
+			continue
+		}
+
+		target := update.To + "@" + update.Latest
+		if w.failed[key] == target {
+			continue
+		}
+
+		// O deploy usa uma cópia da intenção com a nova imagem: o oi.json só muda depois
+		// do sucesso, para um deploy que falhou não virar a fonte da verdade
+		deploy := svc
+		if update.TagChanged() {
+			fmt.Printf("\n🆕 [%s] %s: nova versão %s -> %s (política %s)\n", timestamp(), svc.Label(), update.From, update.To, update.Policy)
+			deploy.Origem = update.To
+		} else {
+			fmt.Printf("\n🆕 [%s] %s: %s tem imagem nova (%s -> %s)\n", timestamp(), svc.Label(), update.From, shortDigest(update.Running), shortDigest(update.Latest))
+		}
+
+		if err := orchestrator.Up(ctx, deploy, service.UpOptions{}); err != nil {
+			w.failed[key] = target
+			fmt.Printf("❌ Atualização de %s falhou, versão anterior mantida: %v\n", svc.Label(), err)
+			continue
+		}
+		delete(w.failed, key)
+		fmt.Printf("✅ %s atualizado\n", svc.Label())
+
+		if update.TagChanged() {
+			if err := config.RewriteImage(file, update.From, update.To); err != nil {
+				fmt.Printf("⚠️  %s está no ar, mas falhou ao atualizar %s (troque a origem manualmente): %v\n", update.To, file, err)
+				continue
+			}
+			fmt.Printf("📝 %s atualizado\n", file)
+		}
+	}
+}
+
+// serviceIntents retorna a intenção de cada serviço (ou a própria, em projetos de serviço único)
+func serviceIntents(intent *domain.Intent) ([]domain.Intent, error) {
+	if intent.IsMultiService() {
+		return intent.ServicesInOrder()
+	}
+	return []domain.Intent{*intent}, nil
+}
+
+func timestamp() string {
+	return time.Now().Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,371 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types/registry"
+
+	"github.com/crom-tech/oi/internal/atomicfile"
+)
+
+// Endereço usado pelo Docker para as credenciais do Docker Hub
+const (
+	dockerHubServer = "https://index.docker.io/v1/"
+	dockerHubDomain = "docker.io"
+)
+
+// Credentials são as credenciais de um registry declaradas fora do Docker
+// (ex: seção "registry" de ~/.oi/config.json)
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// SetCredentials define credenciais por registry (ex: "ghcr.io") com prioridade
+// sobre as do ~/.docker/config.json
+func (c *Client) SetCredentials(creds map[string]Credentials) {
+	c.credentials = make(map[string]Credentials, len(creds))
+	for server, cred := range creds {
+		c.credentials[NormalizeRegistry(server)] = cred
+	}
+}
+
+// Login valida as credenciais no registry (via daemon) e as grava no
+// ~/.docker/config.json, usando o credential helper configurado se houver
+// Retorna o registry normalizado em que as credenciais foram gravadas
+func (c *Client) Login(ctx context.Context, server, username, password string) (string, error) {
+	server = NormalizeRegistry(server)
+	auth := registry.AuthConfig{
+		Username:      username,
+		Password:      password,
+		ServerAddress: configKey(server),
+	}
+
+	resp, err := c.cli.RegistryLogin(ctx, auth)
+	if err != nil {
+		return "", fmt.Errorf("falha ao autenticar em %s: %w", server, err)
+	}
+	if resp.IdentityToken != "" {
+		auth.Password = ""
+		auth.IdentityToken = resp.IdentityToken
+	}
+
+	if err := storeCredentials(server, auth); err != nil {
+		return "", err
+	}
+	return server, nil
+}
+
+// registryAuth retorna o RegistryAuth codificado para o pull da imagem
+// Vazio (pull anônimo) se não houver credenciais para o registry
+func (c *Client) registryAuth(imageName string) (string, error) {
+	server := imageRegistry(imageName)
+	auth, found, err := c.lookupAuth(server)
+	if err != nil || !found {
+		return "", err
+	}
+	encoded, err := registry.EncodeAuthConfig(auth)
+	if err != nil {
+		return "", fmt.Errorf("falha ao codificar credenciais de %s: %w", server, err)
+	}
+	return encoded, nil
+}
+
+// buildAuthConfigs retorna as credenciais de todos os registries conhecidos,
+// para imagens base privadas usadas no FROM dos Dockerfiles
+func (c *Client) buildAuthConfigs() map[string]registry.AuthConfig {
+	servers := make(map[string]bool)
+	for server := range c.credentials {
+		servers[server] = true
+	}
+	if cfg, err := loadDockerConfig(); err == nil {
+		for key := range cfg.Auths {
+			servers[NormalizeRegistry(key)] = true
+		}
+		for key := range cfg.CredHelpers {
+			servers[NormalizeRegistry(key)] = true
+		}
+	}
+
+	auths := make(map[string]registry.AuthConfig, len(servers))
+	for server := range servers {
+		if auth, found, err := c.lookupAuth(server); err == nil && found {
+			auths[configKey(server)] = auth
+		}
+	}
+	return auths
+}
+
+// lookupAuth procura as credenciais de um registry, na ordem:
+//  1. credenciais do OI (SetCredentials)
+//  2. credHelpers do ~/.docker/config.json para o registry
+//  3. credsStore (helper padrão) do ~/.docker/config.json
+//  4. auths do ~/.docker/config.json
+func (c *Client) lookupAuth(server string) (registry.AuthConfig, bool, error) {
+	if cred, ok := c.credentials[server]; ok {
+		return registry.AuthConfig{
+			Username:      cred.Username,
+			Password:      cred.Password,
+			ServerAddress: configKey(server),
+		}, true, nil
+	}
+
+	cfg, err := loadDockerConfig()
+	if err != nil {
+		return registry.AuthConfig{}, false, err
+	}
+
+	if helper := cfg.helperFor(server); helper != "" {
+		auth, found, err := helperGet(helper, configKey(server))
+		if err != nil || found {
+			return auth, found, err
+		}
+	}
+
+	for key, entry := range cfg.Auths {
+		if NormalizeRegistry(key) != server {
+			continue
+		}
+		auth, err := entry.decode(configKey(server))
+		if err != nil {
+			return registry.AuthConfig{}, false, fmt.Errorf("credenciais inválidas para %s em %s: %w", server, dockerConfigPath(), err)
+		}
+		return auth, auth.Username != "" || auth.IdentityToken != "", nil
+	}
+
+	return registry.AuthConfig{}, false, nil
+}
+
+// NormalizeRegistry reduz o endereço de um registry ao host usado como chave
+// (ex: "https://ghcr.io/" -> "ghcr.io"; "https://index.docker.io/v1/" -> "docker.io")
+func NormalizeRegistry(server string) string {
+	server = strings.TrimPrefix(server, "https://")
+	server = strings.TrimPrefix(server, "http://")
+	if i := strings.Index(server, "/"); i >= 0 {
+		server = server[:i]
+	}
+	switch server {
+	case "", "index.docker.io", "registry-1.docker.io":
+		return dockerHubDomain
+	}
+	return server
+}
+
+// configKey retorna a chave usada pelo Docker para o registry no config.json
+// O Docker Hub mantém o endereço histórico da API v1
+func configKey(server string) string {
+	if server == dockerHubDomain {
+		return dockerHubServer
+	}
+	return server
+}
+
+// imageRegistry retorna o registry de uma imagem (ex: "ghcr.io/org/app" -> "ghcr.io")
+func imageRegistry(imageName string) string {
+	named, err := reference.ParseNormalizedNamed(imageName)
+	if err != nil {
+		return dockerHubDomain
+	}
+	return NormalizeRegistry(reference.Domain(named))
+}
+
+// dockerConfig são os campos usados do ~/.docker/config.json
+type dockerConfig struct {
+	Auths       map[string]authEntry `json:"auths"`
+	CredsStore  string               `json:"credsStore"`
+	CredHelpers map[string]string    `json:"credHelpers"`
+}
+
+// authEntry é uma credencial gravada diretamente no config.json
+type authEntry struct {
+	// Auth é "usuário:senha" em base64
+	Auth          string `json:"auth,omitempty"`
+	Username      string `json:"username,omitempty"`
+	Password      string `json:"password,omitempty"`
+	IdentityToken string `json:"identitytoken,omitempty"`
+}
+
+func (e authEntry) decode(serverAddress string) (registry.AuthConfig, error) {
+	auth := registry.AuthConfig{
+		Username:      e.Username,
+		Password:      e.Password,
+		IdentityToken: e.IdentityToken,
+		ServerAddress: serverAddress,
+	}
+	if e.Auth != "" {
+		decoded, err := base64.StdEncoding.DecodeString(e.Auth)
+		if err != nil {
+			return auth, err
+		}
+		user, pass, ok := strings.Cut(string(decoded), ":")
+		if !ok {
+			return auth, errors.New("campo auth deve ser usuário:senha em base64")
+		}
+		auth.Username, auth.Password = user, pass
+	}
+	return auth, nil
+}
+
+// helperFor retorna o credential helper do registry (credHelpers ou credsStore)
+func (cfg *dockerConfig) helperFor(server string) string {
+	for key, helper := range cfg.CredHelpers {
+		if NormalizeRegistry(key) == server {
+			return helper
+		}
+	}
+	return cfg.CredsStore
+}
+
+// dockerConfigPath retorna o caminho do config.json do Docker ($DOCKER_CONFIG ou ~/.docker)
+func dockerConfigPath() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return filepath.Join(dir, "config.json")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".docker", "config.json")
+	}
+	return filepath.Join(home, ".docker", "config.json")
+}
+
+// loadDockerConfig lê o config.json do Docker (vazio se não existir)
+func loadDockerConfig() (*dockerConfig, error) {
+	cfg := &dockerConfig{}
+	path := dockerConfigPath()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("erro ao parsear %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// helperCredentials é o formato trocado com os docker-credential-<helper>
+type helperCredentials struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+// helperGet consulta um credential helper (docker-credential-<helper> get)
+func helperGet(helper, serverAddress string) (registry.AuthConfig, bool, error) {
+	out, err := runHelper(helper, "get", []byte(serverAddress))
+	if err != nil {
+		// Os helpers respondem "credentials not found" quando não há registro
+		if strings.Contains(strings.ToLower(string(out)+err.Error()), "credentials not found") {
+			return registry.AuthConfig{}, false, nil
+		}
+		return registry.AuthConfig{}, false, err
+	}
+
+	var creds helperCredentials
+	if err := json.Unmarshal(out, &creds); err != nil {
+		return registry.AuthConfig{}, false, fmt.Errorf("resposta inválida de docker-credential-%s: %w", helper, err)
+	}
+
+	auth := registry.AuthConfig{ServerAddress: serverAddress}
+	// Usuário "<token>" indica um identity token (ex: Docker Desktop, ACR)
+	if creds.Username == "<token>" {
+		auth.IdentityToken = creds.Secret
+	} else {
+		auth.Username, auth.Password = creds.Username, creds.Secret
+	}
+	return auth, true, nil
+}
+
+// runHelper executa docker-credential-<helper> <action> com input no stdin
+func runHelper(helper, action string, input []byte) ([]byte, error) {
+	cmd := exec.Command("docker-credential-"+helper, action)
+	cmd.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(string(out) + " " + stderr.String())
+		return out, fmt.Errorf("docker-credential-%s %s falhou: %s: %w", helper, action, msg, err)
+	}
+	return out, nil
+}
+
+// storeCredentials grava as credenciais no helper do registry ou, sem helper,
+// no campo auths do ~/.docker/config.json (preservando o restante do arquivo)
+func storeCredentials(server string, auth registry.AuthConfig) error {
+	cfg, err := loadDockerConfig()
+	if err != nil {
+		return err
+	}
+
+	if helper := cfg.helperFor(server); helper != "" {
+		creds := helperCredentials{ServerURL: auth.ServerAddress, Username: auth.Username, Secret: auth.Password}
+		if auth.IdentityToken != "" {
+			creds.Username, creds.Secret = "<token>", auth.IdentityToken
+		}
+		input, err := json.Marshal(creds)
+		if err != nil {
+			return fmt.Errorf("falha ao serializar credenciais: %w", err)
+		}
+		_, err = runHelper(helper, "store", input)
+		return err
+	}
+
+	path := dockerConfigPath()
+	raw := make(map[string]json.RawMessage)
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return fmt.Errorf("erro ao parsear %s: %w", path, err)
+		}
+	}
+
+	auths := make(map[string]authEntry)
+	if existing, ok := raw["auths"]; ok {
+		if err := json.Unmarshal(existing, &auths); err != nil {
+			return fmt.Errorf("erro ao parsear auths de %s: %w", path, err)
+		}
+	}
+
+	entry := authEntry{IdentityToken: auth.IdentityToken}
+	if auth.IdentityToken == "" {
+		entry.Auth = base64.StdEncoding.EncodeToString([]byte(auth.Username + ":" + auth.Password))
+	}
+	auths[auth.ServerAddress] = entry
+
+	encoded, err := json.Marshal(auths)
+	if err != nil {
+		return fmt.Errorf("falha ao serializar credenciais: %w", err)
+	}
+	raw["auths"] = encoded
+
+	data, err := json.MarshalIndent(raw, "", "\t")
+	if err != nil {
+		return fmt.Errorf("falha ao serializar %s: %w", path, err)
+	}
+	return writeConfigFile(path, append(data, '\n'))
+}
+
+// writeConfigFile grava o config.json de forma atômica, com permissão 0600
+func writeConfigFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("falha ao criar %s: %w", filepath.Dir(path), err)
+	}
+
+	if err := atomicfile.Write(path, data, 0600); err != nil {
+		return fmt.Errorf("falha ao gravar %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package docker
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/docker/docker/api/types/registry"
+)
+
+func TestNormalizeRegistry(t *testing.T) {
+	tests := []struct {
+		server string
+		want   string
+	}{
+		{server: "ghcr.io", want: "ghcr.io"},
+		{server: "https://ghcr.io/", want: "ghcr.io"},
+		{server: "http://localhost:5000/v2/", want: "localhost:5000"},
+		{server: "https://index.docker.io/v1/", want: "docker.io"},
+		{server: "index.docker.io", want: "docker.io"},
+		{server: "registry-1.docker.io", want: "docker.io"},
+		{server: "docker.io", want: "docker.io"},
+		{server: "", want: "docker.io"},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeRegistry(tt.server); got != tt.want {
+			t.Errorf("NormalizeRegistry(%q) = %q; esperado %q", tt.server, got, tt.want)
+		}
+	}
+}
+
+func TestConfigKey(t *testing.T) {
+	tests := []struct {
+		server string
+		want   string
+	}{
+		{server: "docker.io", want: "https://index.docker.io/v1/"},
+		{server: "ghcr.io", want: "ghcr.io"},
+		{server: "localhost:5000", want: "localhost:5000"},
+	}
+
+	for _, tt := range tests {
+		if got := configKey(tt.server); got != tt.want {
+			t.Errorf("configKey(%q) = %q; esperado %q", tt.server, got, tt.want)
+		}
+	}
+}
+
+func TestAuthEntryDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		entry   authEntry
+		want    registry.AuthConfig
+		wantErr bool
+	}{
+		{
+			name:  "auth em base64",
+			entry: authEntry{Auth: base64.StdEncoding.EncodeToString([]byte("ana:s3:cret"))},
+			want:  registry.AuthConfig{Username: "ana", Password: "s3:cret", ServerAddress: "ghcr.io"},
+		},
+		{
+			name:  "usuário e senha separados",
+			entry: authEntry{Username: "ana", Password: "s3cret"},
+			want:  registry.AuthConfig{Username: "ana", Password: "s3cret", ServerAddress: "ghcr.io"},
+		},
+		{
+			name:  "identity token",
+			entry: authEntry{IdentityToken: "tok"},
+			want:  registry.AuthConfig{IdentityToken: "tok", ServerAddress: "ghcr.io"},
+		},
+		{
+			name:    "base64 inválido",
+			entry:   authEntry{Auth: "não é base64!"},
+			wantErr: true,
+		},
+		{
+			name:    "auth sem dois pontos",
+			entry:   authEntry{Auth: base64.StdEncoding.EncodeToString([]byte("ana"))},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.entry.decode("ghcr.io")
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("decode = %+v; esperado erro", got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("decode = %+v, %v; esperado %+v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+// writeDockerConfig grava o config.json num DOCKER_CONFIG temporário e retorna o caminho
+func writeDockerConfig(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", dir)
+	path := filepath.Join(dir, "config.json")
+	if content != "" {
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+// installHelper coloca no PATH um docker-credential-teste que conhece
+// helper.example.com e responde "credentials not found" para os demais
+func installHelper(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("credential helper de teste é um script sh")
+	}
+	dir := t.TempDir()
+	script := `#!/bin/sh
+server=$(cat)
+case "$server" in
+  helper.example.com) echo '{"ServerURL":"helper.example.com","Username":"do-helper","Secret":"s3cret"}' ;;
+  token.example.com) echo '{"ServerURL":"token.example.com","Username":"<token>","Secret":"tok"}' ;;
+  *) echo "credentials not found in native keychain"; exit 1 ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(dir, "docker-credential-teste"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestLookupAuth(t *testing.T) {
+	installHelper(t)
+	basic := func(user, pass string) string {
+		return base64.StdEncoding.EncodeToString([]byte(user + ":" + pass))
+	}
+
+	tests := []struct {
+		name   string
+		config string
+		oi     map[string]Credentials
+		server string
+		want   registry.AuthConfig
+		found  bool
+	}{
+		{
+			name:   "credenciais do OI têm prioridade",
+			config: `{"auths": {"ghcr.io": {"auth": "` + basic("docker", "x") + `"}}, "credsStore": "teste"}`,
+			oi:     map[string]Credentials{"https://ghcr.io/": {Username: "oi", Password: "y"}},
+			server: "ghcr.io",
+			want:   registry.AuthConfig{Username: "oi", Password: "y", ServerAddress: "ghcr.io"},
+			found:  true,
+		},
+		{
+			name:   "credHelpers do registry antes de auths",
+			config: `{"auths": {"helper.example.com": {"auth": "` + basic("docker", "x") + `"}}, "credHelpers": {"https://helper.example.com": "teste"}}`,
+			server: "helper.example.com",
+			want:   registry.AuthConfig{Username: "do-helper", Password: "s3cret", ServerAddress: "helper.example.com"},
+			found:  true,
+		},
+		{
+			name:   "credsStore com identity token",
+			config: `{"credsStore": "teste"}`,
+			server: "token.example.com",
+			want:   registry.AuthConfig{IdentityToken: "tok", ServerAddress: "token.example.com"},
+			found:  true,
+		},
+		{
+			name:   "helper sem a credencial cai para auths",
+			config: `{"auths": {"ghcr.io": {"auth": "` + basic("docker", "x") + `"}}, "credsStore": "teste"}`,
+			server: "ghcr.io",
+			want:   registry.AuthConfig{Username: "docker", Password: "x", ServerAddress: "ghcr.io"},
+			found:  true,
+		},
+		{
+			name:   "chave histórica do Docker Hub",
+			config: `{"auths": {"https://index.docker.io/v1/": {"auth": "` + basic("hub", "z") + `"}}}`,
+			server: "docker.io",
+			want:   registry.AuthConfig{Username: "hub", Password: "z", ServerAddress: "https://index.docker.io/v1/"},
+			found:  true,
+		},
+		{
+			name:   "registry sem credenciais",
+			config: `{"auths": {"ghcr.io": {"auth": "` + basic("docker", "x") + `"}}}`,
+			server: "quay.io",
+		},
+		{
+			name:   "sem config.json",
+			server: "ghcr.io",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeDockerConfig(t, tt.config)
+			c := &Client{}
+			c.SetCredentials(tt.oi)
+
+			got, found, err := c.lookupAuth(tt.server)
+			if err != nil {
+				t.Fatalf("lookupAuth: %v", err)
+			}
+			if found != tt.found || got != tt.want {
+				t.Errorf("lookupAuth(%q) = %+v, %v; esperado %+v, %v", tt.server, got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+func TestLookupAuthInvalidEntry(t *testing.T) {
+	writeDockerConfig(t, `{"auths": {"ghcr.io": {"auth": "%%%"}}}`)
+	c := &Client{}
+	if _, _, err := c.lookupAuth("ghcr.io"); err == nil {
+		t.Error("lookupAuth aceitou auth com base64 inválido")
+	}
+}
+
+func TestStoreCredentialsKeepsOtherKeys(t *testing.T) {
+	path := writeDockerConfig(t, `{
+	"auths": {"ghcr.io": {"auth": "Z2g6eA=="}},
+	"currentContext": "remoto",
+	"proxies": {"default": {"httpProxy": "http://proxy:3128"}}
+}`)
+
+	auth := registry.AuthConfig{Username: "ana", Password: "s3cret", ServerAddress: configKey("docker.io")}
+	if err := storeCredentials("docker.io", auth); err != nil {
+		t.Fatalf("storeCredentials: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("config.json inválido: %v", err)
+	}
+	if string(raw["currentContext"]) != `"remoto"` {
+		t.Errorf("currentContext = %s; esperado preservado", raw["currentContext"])
+	}
+	var proxies map[string]map[string]string
+	if err := json.Unmarshal(raw["proxies"], &proxies); err != nil || proxies["default"]["httpProxy"] != "http://proxy:3128" {
+		t.Errorf("proxies = %s; esperado preservado", raw["proxies"])
+	}
+
+	cfg, err := loadDockerConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Auths) != 2 || cfg.Auths["ghcr.io"].Auth != "Z2g6eA==" {
+		t.Errorf("auths = %+v; esperado ghcr.io preservado e docker.io adicionado", cfg.Auths)
+	}
+	c := &Client{}
+	if got, found, err := c.lookupAuth("docker.io"); err != nil || !found || got != auth {
+		t.Errorf("lookupAuth = %+v, %v, %v; esperado %+v", got, found, err, auth)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("permissão = %o; esperado 600", perm)
+	}
+}
@@ -0,0 +1,111 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/moby/patternmatcher"
+	"github.com/moby/patternmatcher/ignorefile"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// Build constrói uma imagem a partir de um Dockerfile local e retorna o ID da imagem
+// O contexto é enviado como tar, respeitando o .dockerignore do diretório (mesmas regras
+// do Docker CLI)
+func (c *Client) Build(ctx context.Context, build domain.Build, tag string) (string, error) {
+	dockerfile := filepath.ToSlash(filepath.Clean(build.DockerfileOrDefault()))
+
+	if _, err := os.Stat(filepath.Join(build.Context, dockerfile)); err != nil {
+		return "", fmt.Errorf("dockerfile não encontrado em %s: %w", build.Context, err)
+	}
+
+	excludes, err := readDockerignore(build.Context, dockerfile)
+	if err != nil {
+		return "", err
+	}
+
+	// O tar é gerado em paralelo para não carregar o contexto inteiro em memória
+	// Metadados do host (dono dos arquivos) não fazem parte do build
+	reader, err := archive.TarWithOptions(build.Context, &archive.TarOptions{
+		ExcludePatterns: excludes,
+		ChownOpts:       &idtools.Identity{UID: 0, GID: 0},
+	})
+	if err != nil {
+		return "", fmt.Errorf("falha ao empacotar contexto de build: %w", err)
+	}
+	defer reader.Close()
+
+	buildArgs := make(map[string]*string, len(build.Args))
+	for k, v := range build.Args {
+		value := v
+		buildArgs[k] = &value
+	}
+
+	resp, err := c.cli.ImageBuild(ctx, reader, types.ImageBuildOptions{
+		Tags:        []string{tag},
+		Dockerfile:  dockerfile,
+		BuildArgs:   buildArgs,
+		Target:      build.Target,
+		Remove:      true,
+		ForceRemove: true,
+		// Imagens base privadas (FROM) usam as mesmas credenciais do pull
+		AuthConfigs: c.buildAuthConfigs(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("falha ao iniciar build: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Exibe a saída do build e captura o ID da imagem (mensagem aux)
+	imageID := ""
+	err = jsonmessage.DisplayJSONMessagesStream(resp.Body, os.Stdout, 0, false, func(msg jsonmessage.JSONMessage) {
+		var aux types.BuildResult
+		if msg.Aux != nil && json.Unmarshal(*msg.Aux, &aux) == nil && aux.ID != "" {
+			imageID = aux.ID
+		}
+	})
+	if err != nil {
+		return "", fmt.Errorf("falha no build: %w", err)
+	}
+
+	// Builders antigos não enviam o aux: consulta a imagem pela tag
+	if imageID == "" {
+		inspect, _, err := c.cli.ImageInspectWithRaw(ctx, tag)
+		if err != nil {
+			return "", fmt.Errorf("falha ao inspecionar imagem construída: %w", err)
+		}
+		imageID = inspect.ID
+	}
+
+	return imageID, nil
+}
+
+// readDockerignore lê os padrões do .dockerignore do contexto (se existir)
+// O Dockerfile é sempre enviado: se algum padrão o exclui, ganha uma exceção ("!")
+func readDockerignore(contextDir string, dockerfile string) ([]string, error) {
+	file, err := os.Open(filepath.Join(contextDir, ".dockerignore"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler .dockerignore: %w", err)
+	}
+	defer file.Close()
+
+	patterns, err := ignorefile.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler .dockerignore: %w", err)
+	}
+	if excluded, _ := patternmatcher.MatchesOrParentMatches(dockerfile, patterns); excluded {
+		patterns = append(patterns, "!"+dockerfile)
+	}
+	return patterns, nil
+}
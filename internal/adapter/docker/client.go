@@ -9,12 +9,14 @@ import (
 	"strings"
 	"time"
 
+	"github.com/distribution/reference"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/go-connections/nat"
 
@@ -25,6 +27,11 @@ import (
 // Client implementa port.ContainerRuntime usando Docker SDK
 type Client struct {
 	cli *client.Client
+	// credentials são as credenciais de registry do OI (ver SetCredentials)
+	credentials map[string]Credentials
+	// loopbackPublish indica que os IPs dos containers não são alcançáveis pelo host
+	// (ver SetLoopbackPublish)
+	loopbackPublish bool
 }
 
 // NewClient cria uma nova instância do Docker client
@@ -40,6 +47,42 @@ func NewClient() (*Client, error) {
 	return &Client{cli: cli}, nil
 }
 
+// NewClientWithHost cria o client apontando para um socket específico
+// (ex: "unix:///run/podman/podman.sock" para a API compatível do Podman)
+func NewClientWithHost(host string) (*Client, error) {
+	cli, err := client.NewClientWithOpts(
+		client.WithHost(host),
+		client.WithAPIVersionNegotiation(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao criar client para %s: %w", host, err)
+	}
+
+	return &Client{cli: cli}, nil
+}
+
+// SetLoopbackPublish faz Create publicar a porta de todo container em 127.0.0.1 (porta
+// aleatória) e Inspect omitir o IP da network, para runtimes cujas networks não são
+// alcançáveis pelo host (ex: Podman rootless); health checks HTTP e o proxy embutido
+// passam a usar a porta publicada
+func (c *Client) SetLoopbackPublish(enabled bool) {
+	c.loopbackPublish = enabled
+}
+
+// Host retorna o endereço do daemon (ex: "unix:///var/run/docker.sock")
+func (c *Client) Host() string {
+	return c.cli.DaemonHost()
+}
+
+// Version retorna a versão do daemon
+func (c *Client) Version(ctx context.Context) (string, error) {
+	v, err := c.cli.ServerVersion(ctx)
+	if err != nil {
+		return "", fmt.Errorf("falha ao consultar versão do daemon: %w", err)
+	}
+	return v.Version, nil
+}
+
 // Close fecha a conexão com o Docker
 func (c *Client) Close() error {
 	return c.cli.Close()
@@ -76,61 +119,134 @@ func (c *Client) List(ctx context.Context, project string) ([]domain.Container,
 	return result, nil
 }
 
-// Pull baixa uma imagem do registry
-func (c *Client) Pull(ctx context.Context, imageName string) error {
-	reader, err := c.cli.ImagePull(ctx, imageName, image.PullOptions{})
+// Pull baixa uma imagem do registry e retorna o digest resolvido
+func (c *Client) Pull(ctx context.Context, imageName string) (string, error) {
+	auth, err := c.registryAuth(imageName)
 	if err != nil {
-		return fmt.Errorf("falha ao baixar imagem %s: %w", imageName, err)
+		return "", err
+	}
+
+	reader, err := c.cli.ImagePull(ctx, imageName, image.PullOptions{RegistryAuth: auth})
+	if err != nil {
+		if auth == "" && (errdefs.IsUnauthorized(err) || errdefs.IsNotFound(err)) {
+			return "", fmt.Errorf("falha ao baixar imagem %s (registry privado? use 'oi login %s'): %w", imageName, imageRegistry(imageName), err)
+		}
+		return "", fmt.Errorf("falha ao baixar imagem %s: %w", imageName, err)
 	}
 	defer reader.Close()
 
-	// Consome o output (necessário para completar o pull)
-	_, err = io.Copy(io.Discard, reader)
+	// Exibe o progresso (o pull só termina quando o stream é consumido)
+	digest, err := displayPull(reader, os.Stdout)
 	if err != nil {
-		return fmt.Errorf("falha ao completar pull da imagem: %w", err)
+		return "", fmt.Errorf("falha ao baixar imagem %s: %w", imageName, err)
 	}
 
-	return nil
+	// O digest anunciado no stream é o do manifest efetivamente baixado
+	if digest != "" {
+		if named, err := reference.ParseNormalizedNamed(imageName); err == nil {
+			return reference.FamiliarName(named) + "@" + digest, nil
+		}
+	}
+	return c.imageDigest(ctx, imageName)
+}
+
+// RemoteDigest consulta o manifest da imagem no registry, sem baixá-la
+// O digest é o mesmo anunciado no pull (manifest list em imagens multi-arquitetura)
+func (c *Client) RemoteDigest(ctx context.Context, imageName string) (string, error) {
+	auth, err := c.registryAuth(imageName)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := c.cli.DistributionInspect(ctx, imageName, auth)
+	if err != nil {
+		return "", fmt.Errorf("falha ao consultar %s no registry: %w", imageName, err)
+	}
+	return string(info.Descriptor.Digest), nil
+}
+
+// imageDigest retorna o repo digest da imagem local (ex: "nginx@sha256:...")
+// Imagens sem digest (nunca enviadas a um registry) usam o ID local
+func (c *Client) imageDigest(ctx context.Context, imageName string) (string, error) {
+	info, _, err := c.cli.ImageInspectWithRaw(ctx, imageName)
+	if err != nil {
+		return "", fmt.Errorf("falha ao inspecionar imagem %s: %w", imageName, err)
+	}
+
+	repo := imageName
+	if i := strings.LastIndex(repo, "@"); i >= 0 {
+		repo = repo[:i]
+	} else if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		repo = repo[:i]
+	}
+	for _, d := range info.RepoDigests {
+		if strings.HasPrefix(d, repo+"@") {
+			return d, nil
+		}
+	}
+	if len(info.RepoDigests) > 0 {
+		return info.RepoDigests[0], nil
+	}
+	return info.ID, nil
 }
 
 // Create cria um novo container baseado na intenção
-func (c *Client) Create(ctx context.Context, intent domain.Intent, version string, publishPort bool, live bool) (string, error) {
-	containerName := c.containerName(intent.Nome, version)
+func (c *Client) Create(ctx context.Context, intent domain.Intent, version string, replica int, publishPort bool, live bool) (string, error) {
+	containerName := c.containerName(intent.Nome, intent.Servico, version, replica)
 	networkName := c.networkName(intent.Nome)
 
 	// Configuração do container
 	// Se a porta for 0 na intenção, usamos 80 como porta interna padrão do container
 	// mas deixamos o bind externo como aleatório
-	internalPort := intent.Porta
-	if internalPort == 0 {
-		internalPort = 80 // Porta padrão interna comum
-	}
+	internalPort := intent.InternalPort()
 	exposedPort := nat.Port(fmt.Sprintf("%d/tcp", internalPort))
 
+	containerLabels := labels.OILabels(
+		intent.Nome,
+		intent.Servico,
+		version,
+		domain.JoinSites(intent.Sites()),
+		intent.Porta,
+	)
+	containerLabels[labels.Replica] = labels.ReplicaValue(replica)
+	if intent.Balanceamento != "" {
+		containerLabels[labels.Policy] = intent.Balanceamento
+	}
+	if intent.Digest != "" {
+		containerLabels[labels.Digest] = intent.Digest
+	}
+	if intent.RemoverPrefixo {
+		containerLabels[labels.StripPrefix] = "true"
+	}
+	if hc := intent.HealthCheck.LabelValue(); hc != "" {
+		containerLabels[labels.HealthCheck] = hc
+	}
+
 	config := &container.Config{
-		Image: intent.Origem,
-		Labels: labels.OILabels(
-			intent.Nome,
-			version,
-			intent.Dominio,
-			intent.Porta,
-		),
+		Image:  intent.Origem,
+		Labels: containerLabels,
 		ExposedPorts: nat.PortSet{
 			exposedPort: struct{}{},
 		},
+		Env: intent.EnvList(),
 		Tty: true, // Importante para logs coloridos
 	}
 
 	// Configuração do host (recursos e portas)
 	hostConfig := &container.HostConfig{
 		Resources: container.Resources{
-			NanoCPUs: c.parseCPU(intent.Recursos.CPU),
-			Memory:   c.parseMemory(intent.Recursos.Memoria),
+			NanoCPUs: intent.Recursos.NanoCPUs(),
+			Memory:   intent.Recursos.MemoryBytes(),
 		},
 		RestartPolicy: container.RestartPolicy{
 			Name: "unless-stopped",
 		},
 	}
+	mounts, err := c.volumeMounts(ctx, intent)
+	if err != nil {
+		return "", err
+	}
+	hostConfig.Mounts = mounts
 
 	// Live Mode: Volumes e Command
 	if live {
@@ -165,8 +281,8 @@ func (c *Client) Create(ctx context.Context, intent domain.Intent, version strin
 	// Se publishPort for true, mapeia a porta no host
 	if publishPort {
 		hostPort := fmt.Sprintf("%d", intent.Porta)
-		if intent.Porta == 0 {
-			hostPort = "0" // Docker aloca porta aleatória
+		if intent.Porta == 0 || replica > 1 {
+			hostPort = "0" // Docker aloca porta aleatória (réplicas extras não disputam a porta)
 		}
 
 		hostConfig.PortBindings = nat.PortMap{
@@ -177,12 +293,22 @@ func (c *Client) Create(ctx context.Context, intent domain.Intent, version strin
 				},
 			},
 		}
+	} else if c.loopbackPublish {
+		// Só o host local alcança a porta; o Docker aloca uma porta aleatória
+		hostConfig.PortBindings = nat.PortMap{
+			exposedPort: []nat.PortBinding{{HostIP: "127.0.0.1", HostPort: "0"}},
+		}
 	}
 
 	// Configuração de rede
+	// Serviços são acessíveis pelo nome na network do projeto (ex: "db:5432")
+	endpoint := &network.EndpointSettings{}
+	if intent.Servico != "" {
+		endpoint.Aliases = []string{intent.Servico}
+	}
 	networkingConfig := &network.NetworkingConfig{
 		EndpointsConfig: map[string]*network.EndpointSettings{
-			networkName: {},
+			networkName: endpoint,
 		},
 	}
 
@@ -286,8 +412,41 @@ func (c *Client) Inspect(ctx context.Context, containerID string) (*domain.Conta
 		ID:      info.ID,
 		Name:    strings.TrimPrefix(info.Name, "/"),
 		Project: info.Config.Labels[labels.Project],
+		Service: info.Config.Labels[labels.Service],
 		Version: info.Config.Labels[labels.Version],
 		Image:   info.Config.Image,
+		ImageID: info.Image,
+		Env:     parseEnv(info.Config.Env),
+		Domain:  info.Config.Labels[labels.Domain],
+		Port:    labels.ParsePort(info.Config.Labels[labels.Port]),
+		Replica: labels.ParseReplica(info.Config.Labels[labels.Replica]),
+		Policy:  info.Config.Labels[labels.Policy],
+		Digest:  info.Config.Labels[labels.Digest],
+
+		StripPrefix: info.Config.Labels[labels.StripPrefix] == "true",
+		HealthCheck: domain.ParseHealthCheckLabel(info.Config.Labels[labels.HealthCheck]),
+	}
+
+	if info.HostConfig != nil {
+		ctr.NanoCPUs = info.HostConfig.NanoCPUs
+		ctr.Memory = info.HostConfig.Memory
+	}
+	if ctr.Volumes, err = c.namedVolumes(ctx, ctr.Project, info.Mounts); err != nil {
+		return nil, err
+	}
+
+	// Endereço na network do projeto (usado para health check HTTP)
+	if info.NetworkSettings != nil && !c.loopbackPublish {
+		if ep, ok := info.NetworkSettings.Networks[c.networkName(ctr.Project)]; ok && ep != nil {
+			ctr.IP = ep.IPAddress
+		} else {
+			for _, ep := range info.NetworkSettings.Networks {
+				if ep != nil && ep.IPAddress != "" {
+					ctr.IP = ep.IPAddress
+					break
+				}
+			}
+		}
 	}
 
 	// Descobrir porta pública mapeada
@@ -421,8 +580,23 @@ func (c *Client) Logs(ctx context.Context, containerID string, stdout, stderr io
 }
 
 // containerName gera o nome do container
-func (c *Client) containerName(project, version string) string {
-	return fmt.Sprintf("oi-%s-%s", project, version[:8])
+// A primeira réplica mantém o nome sem sufixo; as demais recebem "-<n>"
+func (c *Client) containerName(project, service, version string, replica int) string {
+	name := fmt.Sprintf("oi-%s-%s", project, version[:8])
+	if service != "" {
+		name = fmt.Sprintf("oi-%s-%s-%s", project, service, version[:8])
+	}
+	if replica > 1 {
+		name = fmt.Sprintf("%s-%d", name, replica)
+	}
+	return name
+}
+
+// volumeName gera o nome real de um volume nomeado do projeto: "oi-<projeto>.<nome>"
+// O "." não ocorre em nomes de volume, então projetos diferentes nunca geram o mesmo nome
+// (com "-", o projeto "a" com o volume "b-c" colidia com o projeto "a-b" com o volume "c")
+func (c *Client) volumeName(project, name string) string {
+	return fmt.Sprintf("oi-%s.%s", project, name)
 }
 
 // networkName gera o nome da network
@@ -465,48 +639,32 @@ func (c *Client) toDomainContainer(ctr types.Container) domain.Container {
 		ID:        ctr.ID,
 		Name:      name,
 		Project:   ctr.Labels[labels.Project],
+		Service:   ctr.Labels[labels.Service],
 		Version:   ctr.Labels[labels.Version],
 		Image:     ctr.Image,
+		Domain:    ctr.Labels[labels.Domain],
+		Port:      labels.ParsePort(ctr.Labels[labels.Port]),
+		Replica:   labels.ParseReplica(ctr.Labels[labels.Replica]),
+		Policy:    ctr.Labels[labels.Policy],
+		Digest:    ctr.Labels[labels.Digest],
 		Status:    status,
 		Health:    health,
 		CreatedAt: time.Unix(ctr.Created, 0),
 		// PublicPort não está disponível em ContainerList de forma fácil sem Inspect
 		// Deixamos 0 ou tentamos parsear de Ports se disponível
-	}
-}
 
-// parseCPU converte string de CPU para NanoCPUs
-// Exemplo: "0.5" -> 500000000
-func (c *Client) parseCPU(cpu string) int64 {
-	if cpu == "" {
-		return 0
+		StripPrefix: ctr.Labels[labels.StripPrefix] == "true",
+		HealthCheck: domain.ParseHealthCheckLabel(ctr.Labels[labels.HealthCheck]),
 	}
-	var value float64
-	fmt.Sscanf(cpu, "%f", &value)
-	return int64(value * 1e9)
 }
 
-// parseMemory converte string de memória para bytes
-// Exemplo: "512mb" -> 536870912
-func (c *Client) parseMemory(mem string) int64 {
-	if mem == "" {
-		return 0
-	}
-
-	mem = strings.ToLower(mem)
-	var value int64
-	var unit string
-
-	fmt.Sscanf(mem, "%d%s", &value, &unit)
-
-	switch unit {
-	case "kb", "k":
-		return value * 1024
-	case "mb", "m":
-		return value * 1024 * 1024
-	case "gb", "g":
-		return value * 1024 * 1024 * 1024
-	default:
-		return value
+// parseEnv converte a lista KEY=VALUE do Docker em mapa
+func parseEnv(env []string) map[string]string {
+	result := make(map[string]string, len(env))
+	for _, kv := range env {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			result[k] = v
+		}
 	}
+	return result
 }
@@ -0,0 +1,83 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/moby/term"
+)
+
+// digestStatusPrefix é o status em que o daemon anuncia o digest do manifest baixado
+const digestStatusPrefix = "Digest: "
+
+// displayPull exibe o stream JSON de um pull e retorna o digest anunciado nele
+// Em terminais, mostra barras de progresso por camada; fora deles (CI, logs),
+// uma linha por mudança de estado, sem as atualizações de progresso
+// Erros enviados no meio do stream (ex: "manifest unknown") são retornados
+func displayPull(stream io.Reader, out io.Writer) (string, error) {
+	if fd, isTerminal := term.GetFdInfo(out); isTerminal {
+		sniffer := &digestSniffer{}
+		err := jsonmessage.DisplayJSONMessagesStream(io.TeeReader(stream, sniffer), out, fd, true, nil)
+		return sniffer.digest, err
+	}
+
+	digest := ""
+	decoder := json.NewDecoder(stream)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return digest, nil
+			}
+			return digest, fmt.Errorf("stream do pull inválido: %w", err)
+		}
+		if msg.Error != nil {
+			return digest, msg.Error
+		}
+		if d, ok := strings.CutPrefix(msg.Status, digestStatusPrefix); ok {
+			digest = d
+		}
+		// Atualizações de progresso (Downloading/Extracting x MB) só fazem sentido em terminais
+		if msg.Progress != nil || msg.ProgressMessage != "" || msg.Status == "" {
+			continue
+		}
+		if msg.ID != "" {
+			fmt.Fprintf(out, "   %s: %s\n", msg.ID, msg.Status)
+		} else {
+			fmt.Fprintf(out, "   %s\n", msg.Status)
+		}
+	}
+}
+
+// digestSniffer observa as mensagens do stream exibido pelo jsonmessage e
+// guarda o digest anunciado (o callback do jsonmessage só recebe mensagens aux)
+type digestSniffer struct {
+	buf    []byte
+	digest string
+}
+
+func (s *digestSniffer) Write(p []byte) (int, error) {
+	s.buf = append(s.buf, p...)
+	for {
+		i := bytes.IndexByte(s.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := s.buf[:i]
+		if bytes.Contains(line, []byte(digestStatusPrefix)) {
+			var msg jsonmessage.JSONMessage
+			if json.Unmarshal(line, &msg) == nil {
+				if d, ok := strings.CutPrefix(msg.Status, digestStatusPrefix); ok {
+					s.digest = d
+				}
+			}
+		}
+		s.buf = s.buf[i+1:]
+	}
+	return len(p), nil
+}
@@ -0,0 +1,238 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/distribution/reference"
+)
+
+// Host da API de registry do Docker Hub (docker.io é só o nome canônico)
+const dockerHubAPIHost = "registry-1.docker.io"
+
+// maxTagPages limita a paginação de /tags/list (repositórios com milhares de tags)
+const maxTagPages = 20
+
+// ListTags lista as tags do repositório da imagem direto na API do registry (v2)
+// O daemon não expõe essa consulta; a autenticação usa as mesmas credenciais do pull
+func (c *Client) ListTags(ctx context.Context, imageName string) ([]string, error) {
+	named, err := reference.ParseNormalizedNamed(imageName)
+	if err != nil {
+		return nil, fmt.Errorf("imagem inválida %s: %w", imageName, err)
+	}
+
+	server := NormalizeRegistry(reference.Domain(named))
+	host := server
+	if server == dockerHubDomain {
+		host = dockerHubAPIHost
+	}
+	scheme := "https"
+	if isLocalRegistry(host) {
+		scheme = "http"
+	}
+
+	auth, _, err := c.lookupAuth(server)
+	if err != nil {
+		return nil, err
+	}
+	reg := &registryClient{
+		http:     &http.Client{Timeout: 30 * time.Second},
+		username: auth.Username,
+		password: auth.Password,
+	}
+
+	var tags []string
+	next := fmt.Sprintf("%s://%s/v2/%s/tags/list?n=1000", scheme, host, reference.Path(named))
+	for page := 0; next != "" && page < maxTagPages; page++ {
+		var body struct {
+			Tags []string `json:"tags"`
+		}
+		link, err := reg.getJSON(ctx, next, &body)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao listar tags de %s: %w", reference.FamiliarName(named), err)
+		}
+		tags = append(tags, body.Tags...)
+		next = nextPage(next, link)
+	}
+	return tags, nil
+}
+
+// registryClient faz requisições à API v2 de um registry, obtendo tokens sob demanda
+type registryClient struct {
+	http     *http.Client
+	username string
+	password string
+	// token é o bearer token obtido no último desafio de autenticação
+	token string
+}
+
+// getJSON faz GET em rawURL e decodifica a resposta; retorna o header Link (paginação)
+// Em 401, responde ao desafio WWW-Authenticate (Bearer ou Basic) e tenta de novo
+func (r *registryClient) getJSON(ctx context.Context, rawURL string, v interface{}) (string, error) {
+	resp, err := r.do(ctx, rawURL)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if err := r.authenticate(ctx, challenge); err != nil {
+			return "", err
+		}
+		if resp, err = r.do(ctx, rawURL); err != nil {
+			return "", err
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("registry retornou %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return "", fmt.Errorf("resposta inválida do registry: %w", err)
+	}
+	return resp.Header.Get("Link"), nil
+}
+
+func (r *registryClient) do(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao criar request: %w", err)
+	}
+	switch {
+	case r.token != "":
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	case r.username != "":
+		req.SetBasicAuth(r.username, r.password)
+	}
+
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao acessar registry: %w", err)
+	}
+	return resp, nil
+}
+
+// authenticate obtém um token no serviço indicado pelo desafio Bearer
+// (realm, service e scope), com as credenciais do registry se houver
+func (r *registryClient) authenticate(ctx context.Context, challenge string) error {
+	scheme, params := parseChallenge(challenge)
+	switch scheme {
+	case "basic":
+		if r.username == "" {
+			return fmt.Errorf("registry exige autenticação: use 'oi login'")
+		}
+		return nil
+	case "bearer":
+	default:
+		return fmt.Errorf("desafio de autenticação não suportado: %q", challenge)
+	}
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return fmt.Errorf("realm de autenticação inválido: %q", params["realm"])
+	}
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	if scope := params["scope"]; scope != "" {
+		query.Set("scope", scope)
+	}
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return fmt.Errorf("falha ao criar request: %w", err)
+	}
+	if r.username != "" {
+		req.SetBasicAuth(r.username, r.password)
+	}
+
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("falha ao obter token do registry: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("registry recusou a autenticação (%d): use 'oi login'", resp.StatusCode)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return fmt.Errorf("resposta inválida do serviço de token: %w", err)
+	}
+	r.token = token.Token
+	if r.token == "" {
+		r.token = token.AccessToken
+	}
+	if r.token == "" {
+		return fmt.Errorf("serviço de token não retornou um token")
+	}
+	return nil
+}
+
+// parseChallenge interpreta o header WWW-Authenticate
+// Ex: Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="..."
+func parseChallenge(header string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(header), " ")
+	params := make(map[string]string)
+	for rest != "" {
+		var pair string
+		rest = strings.TrimLeft(rest, " ,")
+		key, value, ok := strings.Cut(rest, "=")
+		if !ok {
+			break
+		}
+		if strings.HasPrefix(value, `"`) {
+			end := strings.Index(value[1:], `"`)
+			if end < 0 {
+				break
+			}
+			pair, rest = value[1:end+1], value[end+2:]
+		} else {
+			pair, rest, _ = strings.Cut(value, ",")
+		}
+		params[strings.ToLower(strings.TrimSpace(key))] = pair
+	}
+	return strings.ToLower(scheme), params
+}
+
+// nextPage resolve o header Link (<url>; rel="next") relativo à página atual
+func nextPage(current, link string) string {
+	if link == "" || !strings.Contains(link, `rel="next"`) {
+		return ""
+	}
+	start, end := strings.Index(link, "<"), strings.Index(link, ">")
+	if start < 0 || end <= start {
+		return ""
+	}
+	base, err := url.Parse(current)
+	if err != nil {
+		return ""
+	}
+	ref, err := url.Parse(link[start+1 : end])
+	if err != nil {
+		return ""
+	}
+	return base.ResolveReference(ref).String()
+}
+
+// isLocalRegistry indica registries locais, acessados por HTTP como faz o Docker
+func isLocalRegistry(host string) bool {
+	name := host
+	if i := strings.LastIndex(host, ":"); i >= 0 {
+		name = host[:i]
+	}
+	return name == "localhost" || strings.HasPrefix(name, "127.") || name == "[::1]"
+}
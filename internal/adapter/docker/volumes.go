@@ -0,0 +1,189 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+	"github.com/crom-tech/oi/pkg/labels"
+)
+
+// EnsureVolume garante que o volume nomeado do projeto existe
+// O volume é encontrado pelos labels OI (projeto e nome declarado), nunca só pelo nome:
+// um volume com o nome esperado mas de outra origem é um erro, não é reaproveitado
+func (c *Client) EnsureVolume(ctx context.Context, project string, name string) (string, error) {
+	existing, err := c.findVolume(ctx, project, name)
+	if err != nil {
+		return "", err
+	}
+	if existing != "" {
+		return existing, nil
+	}
+
+	volumeName := c.volumeName(project, name)
+	if v, err := c.cli.VolumeInspect(ctx, volumeName); err == nil {
+		return "", fmt.Errorf("volume %s já existe e não pertence ao volume '%s' do projeto '%s' (labels %s=%q, %s=%q)",
+			volumeName, name, project, labels.Project, v.Labels[labels.Project], labels.Volume, v.Labels[labels.Volume])
+	}
+
+	_, err = c.cli.VolumeCreate(ctx, volume.CreateOptions{
+		Name: volumeName,
+		Labels: map[string]string{
+			labels.Managed: "true",
+			labels.Project: project,
+			labels.Volume:  name,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("falha ao criar volume %s: %w", volumeName, err)
+	}
+
+	return volumeName, nil
+}
+
+// projectVolumes retorna os volumes do projeto pelos labels (nome real -> nome declarado)
+func (c *Client) projectVolumes(ctx context.Context, project string) (map[string]string, error) {
+	filterArgs := filters.NewArgs()
+	filterArgs.Add("label", labels.ManagedFilter())
+	filterArgs.Add("label", labels.ProjectFilter(project))
+
+	resp, err := c.cli.VolumeList(ctx, volume.ListOptions{Filters: filterArgs})
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar volumes: %w", err)
+	}
+	result := make(map[string]string, len(resp.Volumes))
+	for _, v := range resp.Volumes {
+		if key := v.Labels[labels.Volume]; key != "" && v.Labels[labels.Project] == project {
+			result[v.Name] = key
+		}
+	}
+	return result, nil
+}
+
+// findVolume retorna o nome real do volume declarado do projeto ("" se não existir)
+// Também encontra volumes criados com o formato de nome anterior (oi-<projeto>-<nome>)
+func (c *Client) findVolume(ctx context.Context, project string, name string) (string, error) {
+	volumes, err := c.projectVolumes(ctx, project)
+	if err != nil {
+		return "", err
+	}
+	if volumes[c.volumeName(project, name)] == name {
+		return c.volumeName(project, name), nil
+	}
+	var matches []string
+	for real, key := range volumes {
+		if key == name {
+			matches = append(matches, real)
+		}
+	}
+	if len(matches) == 0 {
+		return "", nil
+	}
+	sort.Strings(matches)
+	return matches[0], nil
+}
+
+// ListVolumes retorna os volumes gerenciados pelo OI (de um projeto ou de todos)
+func (c *Client) ListVolumes(ctx context.Context, project string) ([]domain.Volume, error) {
+	filterArgs := filters.NewArgs()
+	filterArgs.Add("label", labels.ManagedFilter())
+	if project != "" {
+		filterArgs.Add("label", labels.ProjectFilter(project))
+	}
+
+	resp, err := c.cli.VolumeList(ctx, volume.ListOptions{Filters: filterArgs})
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar volumes: %w", err)
+	}
+
+	result := make([]domain.Volume, 0, len(resp.Volumes))
+	for _, v := range resp.Volumes {
+		vol := domain.Volume{
+			Name:    v.Name,
+			Project: v.Labels[labels.Project],
+			Key:     v.Labels[labels.Volume],
+			Driver:  v.Driver,
+		}
+		if created, err := time.Parse(time.RFC3339, v.CreatedAt); err == nil {
+			vol.CreatedAt = created
+		}
+		result = append(result, vol)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
+
+// RemoveVolume remove um volume pelo nome real
+// Falha se algum container (mesmo parado) ainda usar o volume
+func (c *Client) RemoveVolume(ctx context.Context, name string) error {
+	if err := c.cli.VolumeRemove(ctx, name, false); err != nil {
+		return fmt.Errorf("falha ao remover volume %s: %w", name, err)
+	}
+	return nil
+}
+
+// volumeMounts converte os volumes da intenção em mounts do Docker
+// Vale para todos os modos (normal e live); os volumes precisam existir (EnsureVolume)
+func (c *Client) volumeMounts(ctx context.Context, intent domain.Intent) ([]mount.Mount, error) {
+	if len(intent.Volumes) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(intent.Volumes))
+	for name := range intent.Volumes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	mounts := make([]mount.Mount, 0, len(names))
+	for _, name := range names {
+		source, err := c.findVolume(ctx, intent.Nome, name)
+		if err != nil {
+			return nil, err
+		}
+		if source == "" {
+			return nil, fmt.Errorf("volume '%s' do projeto '%s' não existe", name, intent.Nome)
+		}
+		mounts = append(mounts, mount.Mount{
+			Type:   mount.TypeVolume,
+			Source: source,
+			Target: intent.Volumes[name],
+		})
+	}
+	return mounts, nil
+}
+
+// namedVolumes extrai dos mounts de um container os volumes nomeados do projeto
+// Os volumes são identificados pelos labels, não pelo formato do nome
+func (c *Client) namedVolumes(ctx context.Context, project string, mounts []types.MountPoint) (map[string]string, error) {
+	volumes := make(map[string]string)
+	hasVolumes := false
+	for _, m := range mounts {
+		hasVolumes = hasVolumes || m.Type == mount.TypeVolume
+	}
+	if !hasVolumes || project == "" {
+		return volumes, nil
+	}
+
+	keys, err := c.projectVolumes(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range mounts {
+		if key, ok := keys[m.Name]; ok && m.Type == mount.TypeVolume {
+			volumes[key] = m.Destination
+		}
+	}
+	return volumes, nil
+}
@@ -0,0 +1,112 @@
+package fake
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// Proxy implementa port.ProxyManager em memória, guardando as rotas por ID
+type Proxy struct {
+	// HealthErr faz Health falhar (proxy fora do ar)
+	HealthErr error
+	// AddRouteErr e RemoveRouteErr fazem a alteração de rota falhar
+	AddRouteErr    error
+	RemoveRouteErr error
+
+	mu     sync.Mutex
+	routes map[string]domain.Route
+}
+
+// NewProxy cria um proxy sem rotas
+func NewProxy() *Proxy {
+	return &Proxy{routes: make(map[string]domain.Route)}
+}
+
+// Route retorna a rota que atende o endereço "host[/prefixo]", se existir
+func (p *Proxy) Route(site string) (domain.Route, bool) {
+	target, err := domain.ParseSite(site)
+	if err != nil {
+		return domain.Route{}, false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, route := range p.routes {
+		if route.Serves(target) {
+			return copyRoute(route), true
+		}
+	}
+	return domain.Route{}, false
+}
+
+// Routes retorna todas as rotas, ordenadas por ID
+func (p *Proxy) Routes() []domain.Route {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	routes := make([]domain.Route, 0, len(p.routes))
+	for _, route := range p.routes {
+		routes = append(routes, copyRoute(route))
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].ID < routes[j].ID })
+	return routes
+}
+
+// AddRoute adiciona ou substitui a rota com o mesmo ID
+func (p *Proxy) AddRoute(ctx context.Context, route domain.Route) error {
+	if p.AddRouteErr != nil {
+		return p.AddRouteErr
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.routes[route.ID] = copyRoute(route)
+	return nil
+}
+
+// RemoveRoute remove a rota pelo ID (e rotas sem ID dos mesmos endereços)
+func (p *Proxy) RemoveRoute(ctx context.Context, route domain.Route) error {
+	if p.RemoveRouteErr != nil {
+		return p.RemoveRouteErr
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.routes, route.ID)
+	for id, r := range p.routes {
+		if r.ID == "" && r.Overlaps(route) {
+			delete(p.routes, id)
+		}
+	}
+	return nil
+}
+
+// HasRoute verifica se existe rota para o endereço
+func (p *Proxy) HasRoute(ctx context.Context, site string) (bool, error) {
+	_, ok := p.Route(site)
+	return ok, nil
+}
+
+// GetUpstreams retorna os upstreams (host:porta) da rota do endereço
+func (p *Proxy) GetUpstreams(ctx context.Context, site string) ([]string, error) {
+	route, ok := p.Route(site)
+	if !ok {
+		return nil, nil
+	}
+	return route.Addresses(), nil
+}
+
+// Reload não faz nada: as rotas valem assim que são gravadas
+func (p *Proxy) Reload(ctx context.Context) error {
+	return nil
+}
+
+// Health retorna HealthErr
+func (p *Proxy) Health(ctx context.Context) error {
+	return p.HealthErr
+}
+
+func copyRoute(route domain.Route) domain.Route {
+	route.Aliases = append([]string(nil), route.Aliases...)
+	route.Upstreams = append([]domain.Upstream(nil), route.Upstreams...)
+	return route
+}
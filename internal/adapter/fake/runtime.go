@@ -0,0 +1,430 @@
+// Package fake implementa os ports do OI em memória, para testes sem Docker nem Caddy
+// Os campos exportados injetam falhas (pull, criação, health check, proxy...)
+package fake
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// Runtime implementa port.ContainerRuntime em memória
+// Containers sobem instantaneamente: Start os deixa running e healthy, a menos que
+// Unhealthy diga o contrário
+type Runtime struct {
+	// PullErr, BuildErr, CreateErr e StartErr fazem a operação correspondente falhar
+	PullErr   error
+	BuildErr  error
+	CreateErr error
+	StartErr  error
+	// Unhealthy indica os containers que nunca ficam healthy (WaitHealthy falha)
+	Unhealthy func(c domain.Container) bool
+	// IP, se definido, é o IP de todos os containers criados (ex: "127.0.0.1" com um
+	// httptest.Server na porta do serviço, para exercitar o health check HTTP)
+	IP string
+
+	mu         sync.Mutex
+	containers map[string]*domain.Container
+	networks   map[string]bool
+	volumes    map[string]domain.Volume
+	digests    map[string]string
+	tags       map[string][]string
+	logs       map[string]string
+	seq        int
+	// clock dá a cada container um CreatedAt distinto e crescente
+	clock time.Time
+}
+
+// NewRuntime cria um runtime vazio
+func NewRuntime() *Runtime {
+	return &Runtime{
+		containers: make(map[string]*domain.Container),
+		networks:   make(map[string]bool),
+		volumes:    make(map[string]domain.Volume),
+		digests:    make(map[string]string),
+		tags:       make(map[string][]string),
+		logs:       make(map[string]string),
+		clock:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// PublishImage simula uma nova publicação da imagem no registry (novo digest na mesma tag)
+func (r *Runtime) PublishImage(image string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+	digest := digestOf(fmt.Sprintf("%s#%d", image, r.seq))
+	r.digests[image] = digest
+	return digest
+}
+
+// SetTags define as tags publicadas no registry para o repositório (ex: "nginx")
+func (r *Runtime) SetTags(repository string, tags []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tags[repository] = tags
+}
+
+// SetLogs define a saída de Logs de um container (padrão: "log de <nome>")
+func (r *Runtime) SetLogs(containerID, output string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logs[containerID] = output
+}
+
+// HasNetwork indica se a network do projeto existe
+func (r *Runtime) HasNetwork(project string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.networks[project]
+}
+
+// List retorna os containers do projeto (todos se project for vazio), do mais antigo ao mais novo
+func (r *Runtime) List(ctx context.Context, project string) ([]domain.Container, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]domain.Container, 0, len(r.containers))
+	for _, c := range r.containers {
+		if project == "" || c.Project == project {
+			result = append(result, copyContainer(c))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+// Pull retorna o digest atual da imagem (ver PublishImage)
+func (r *Runtime) Pull(ctx context.Context, image string) (string, error) {
+	if r.PullErr != nil {
+		return "", r.PullErr
+	}
+	digest, err := r.RemoteDigest(ctx, image)
+	if err != nil {
+		return "", err
+	}
+	repository, _ := domain.SplitImageTag(image)
+	return repository + "@" + digest, nil
+}
+
+// RemoteDigest retorna o digest servido pelo "registry" para a imagem
+func (r *Runtime) RemoteDigest(ctx context.Context, image string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if digest, ok := r.digests[image]; ok {
+		return digest, nil
+	}
+	return digestOf(image), nil
+}
+
+// ListTags retorna as tags definidas com SetTags
+func (r *Runtime) ListTags(ctx context.Context, image string) ([]string, error) {
+	repository, _ := domain.SplitImageTag(image)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.tags[repository]...), nil
+}
+
+// Build retorna um ID de imagem derivado do contexto e da tag
+func (r *Runtime) Build(ctx context.Context, build domain.Build, tag string) (string, error) {
+	if r.BuildErr != nil {
+		return "", r.BuildErr
+	}
+	return digestOf(build.Context + "#" + build.Dockerfile + "#" + tag), nil
+}
+
+// Create registra um container parado para a réplica da versão
+func (r *Runtime) Create(ctx context.Context, intent domain.Intent, version string, replica int, publishPort bool, live bool) (string, error) {
+	if r.CreateErr != nil {
+		return "", r.CreateErr
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.networks[intent.Nome] {
+		return "", fmt.Errorf("network do projeto %s não existe", intent.Nome)
+	}
+
+	r.seq++
+	r.clock = r.clock.Add(time.Second)
+	ctr := &domain.Container{
+		ID:        fmt.Sprintf("ctr-%d", r.seq),
+		Name:      containerName(intent.Nome, intent.Servico, version, replica),
+		Project:   intent.Nome,
+		Service:   intent.Servico,
+		Version:   version,
+		Image:     intent.Origem,
+		ImageID:   digestOf(intent.Origem),
+		Digest:    intent.Digest,
+		Status:    domain.StatusStopped,
+		Health:    domain.HealthUnknown,
+		Domain:    domain.JoinSites(intent.Sites()),
+		Port:      intent.InternalPort(),
+		Env:       make(map[string]string, len(intent.Ambiente)),
+		NanoCPUs:  intent.Recursos.NanoCPUs(),
+		Memory:    intent.Recursos.MemoryBytes(),
+		CreatedAt: r.clock,
+		Replica:   replica,
+		Policy:    intent.Balanceamento,
+		IP:        fmt.Sprintf("10.0.%d.%d", r.seq/250, r.seq%250+2),
+	}
+	for k, v := range intent.Ambiente {
+		ctr.Env[k] = v
+	}
+	ctr.StripPrefix = intent.RemoverPrefixo
+	if r.IP != "" {
+		ctr.IP = r.IP
+	}
+	ctr.HealthCheck = domain.ParseHealthCheckLabel(intent.HealthCheck.LabelValue())
+	if publishPort {
+		ctr.PublicPort = 30000 + r.seq
+	}
+	if len(intent.Volumes) > 0 {
+		ctr.Volumes = make(map[string]string, len(intent.Volumes))
+		for name, path := range intent.Volumes {
+			if _, ok := r.volumes[volumeName(intent.Nome, name)]; !ok {
+				return "", fmt.Errorf("volume %s não existe", name)
+			}
+			ctr.Volumes[name] = path
+		}
+	}
+
+	r.containers[ctr.ID] = ctr
+	return ctr.ID, nil
+}
+
+// Start coloca o container em execução (healthy, ou unhealthy conforme Unhealthy)
+func (r *Runtime) Start(ctx context.Context, containerID string) error {
+	if r.StartErr != nil {
+		return r.StartErr
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ctr, err := r.get(containerID)
+	if err != nil {
+		return err
+	}
+	ctr.Status = domain.StatusRunning
+	ctr.Health = domain.HealthHealthy
+	if r.Unhealthy != nil && r.Unhealthy(copyContainer(ctr)) {
+		ctr.Health = domain.HealthUnhealthy
+	}
+	return nil
+}
+
+// Stop para o container
+func (r *Runtime) Stop(ctx context.Context, containerID string, timeout time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ctr, err := r.get(containerID)
+	if err != nil {
+		return err
+	}
+	ctr.Status = domain.StatusStopped
+	ctr.Health = domain.HealthUnknown
+	return nil
+}
+
+// Remove remove o container; como no Docker, um container rodando exige force
+func (r *Runtime) Remove(ctx context.Context, containerID string, force bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ctr, err := r.get(containerID)
+	if err != nil {
+		return err
+	}
+	if ctr.Status == domain.StatusRunning && !force {
+		return fmt.Errorf("container %s está rodando, pare antes de remover", ctr.Name)
+	}
+	delete(r.containers, containerID)
+	return nil
+}
+
+// WaitHealthy retorna imediatamente: nil se o container estiver rodando e healthy
+func (r *Runtime) WaitHealthy(ctx context.Context, containerID string, timeout time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ctr, err := r.get(containerID)
+	if err != nil {
+		return err
+	}
+	switch {
+	case ctr.Status != domain.StatusRunning:
+		return domain.ErrHealthCheckFailed{ContainerID: containerID, Reason: "container não está rodando"}
+	case ctr.Health != domain.HealthHealthy:
+		return domain.ErrHealthCheckFailed{ContainerID: containerID, Reason: "container reportou unhealthy"}
+	}
+	return nil
+}
+
+// Inspect retorna uma cópia do container
+func (r *Runtime) Inspect(ctx context.Context, containerID string) (*domain.Container, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ctr, err := r.get(containerID)
+	if err != nil {
+		return nil, err
+	}
+	c := copyContainer(ctr)
+	return &c, nil
+}
+
+// EnsureNetwork cria a network do projeto se não existir
+func (r *Runtime) EnsureNetwork(ctx context.Context, project string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.networks[project] = true
+	return networkName(project), nil
+}
+
+// RemoveNetwork remove a network do projeto
+func (r *Runtime) RemoveNetwork(ctx context.Context, project string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.networks[project] {
+		return fmt.Errorf("network %s não existe", networkName(project))
+	}
+	delete(r.networks, project)
+	return nil
+}
+
+// ListNetworks retorna os projetos com network
+func (r *Runtime) ListNetworks(ctx context.Context) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	projects := make([]string, 0, len(r.networks))
+	for p := range r.networks {
+		projects = append(projects, p)
+	}
+	sort.Strings(projects)
+	return projects, nil
+}
+
+// EnsureVolume cria o volume nomeado do projeto se não existir
+func (r *Runtime) EnsureVolume(ctx context.Context, project string, name string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	real := volumeName(project, name)
+	if _, ok := r.volumes[real]; !ok {
+		r.volumes[real] = domain.Volume{Name: real, Project: project, Key: name}
+	}
+	return real, nil
+}
+
+// ListVolumes retorna os volumes do projeto (todos se project for vazio)
+func (r *Runtime) ListVolumes(ctx context.Context, project string) ([]domain.Volume, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []domain.Volume
+	for _, v := range r.volumes {
+		if project == "" || v.Project == project {
+			result = append(result, v)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// RemoveVolume remove um volume pelo nome real
+func (r *Runtime) RemoveVolume(ctx context.Context, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.volumes[name]; !ok {
+		return fmt.Errorf("volume %s não existe", name)
+	}
+	delete(r.volumes, name)
+	return nil
+}
+
+// Logs escreve a saída definida com SetLogs (ou "log de <nome>") em stdout
+func (r *Runtime) Logs(ctx context.Context, containerID string, stdout, stderr io.Writer, follow bool, tail string) error {
+	r.mu.Lock()
+	ctr, err := r.get(containerID)
+	if err != nil {
+		r.mu.Unlock()
+		return err
+	}
+	output, ok := r.logs[containerID]
+	if !ok {
+		output = "log de " + ctr.Name + "\n"
+	}
+	r.mu.Unlock()
+
+	_, err = io.WriteString(stdout, output)
+	return err
+}
+
+// get retorna o container pelo ID (ou nome); r.mu deve estar travado
+func (r *Runtime) get(containerID string) (*domain.Container, error) {
+	if ctr, ok := r.containers[containerID]; ok {
+		return ctr, nil
+	}
+	for _, ctr := range r.containers {
+		if ctr.Name == containerID {
+			return ctr, nil
+		}
+	}
+	return nil, fmt.Errorf("container %s não encontrado", containerID)
+}
+
+// copyContainer copia o container, incluindo os mapas, para que o chamador não altere o estado
+func copyContainer(c *domain.Container) domain.Container {
+	cp := *c
+	if c.Env != nil {
+		cp.Env = make(map[string]string, len(c.Env))
+		for k, v := range c.Env {
+			cp.Env[k] = v
+		}
+	}
+	if c.Volumes != nil {
+		cp.Volumes = make(map[string]string, len(c.Volumes))
+		for k, v := range c.Volumes {
+			cp.Volumes[k] = v
+		}
+	}
+	return cp
+}
+
+// Nomes no mesmo formato do adapter Docker
+
+func containerName(project, service, version string, replica int) string {
+	short := version
+	if len(short) > 8 {
+		short = short[:8]
+	}
+	parts := []string{"oi", project}
+	if service != "" {
+		parts = append(parts, service)
+	}
+	name := strings.Join(append(parts, short), "-")
+	if replica > 1 {
+		name = fmt.Sprintf("%s-%d", name, replica)
+	}
+	return name
+}
+
+func volumeName(project, name string) string {
+	return fmt.Sprintf("oi-%s.%s", project, name)
+}
+
+func networkName(project string) string {
+	return fmt.Sprintf("oi-%s-net", project)
+}
+
+// digestOf gera um digest determinístico para o conteúdo
+func digestOf(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,461 @@
+package nginx
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// directive é uma diretiva do nginx: "nome args...;" ou "nome args... { bloco }"
+type directive struct {
+	name  string
+	args  []string
+	block []directive
+	// hasBlock diferencia "x {}" de "x;"
+	hasBlock bool
+	line     int
+}
+
+// find retorna as diretivas filhas com o nome informado
+func (d directive) find(name string) []directive {
+	var result []directive
+	for _, child := range d.block {
+		if child.name == name {
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
+// token é uma palavra, ";", "{" ou "}" com a linha onde aparece
+type token struct {
+	value string
+	// quoted indica palavra entre aspas (nunca é ";", "{" ou "}")
+	quoted bool
+	line   int
+}
+
+// tokenize separa o texto em tokens, ignorando comentários (#)
+// Aspas simples e duplas agrupam palavras, com escapes por "\"
+func tokenize(text string) ([]token, error) {
+	var tokens []token
+	line := 1
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '#':
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+		case c == ';' || c == '{' || c == '}':
+			tokens = append(tokens, token{value: string(c), line: line})
+			i++
+		case c == '"' || c == '\'':
+			start := line
+			var b strings.Builder
+			i++
+			for {
+				if i >= len(text) {
+					return nil, fmt.Errorf("linha %d: aspas não fechadas", start)
+				}
+				if text[i] == c {
+					i++
+					break
+				}
+				if text[i] == '\\' && i+1 < len(text) {
+					i++
+				}
+				if text[i] == '\n' {
+					line++
+				}
+				b.WriteByte(text[i])
+				i++
+			}
+			tokens = append(tokens, token{value: b.String(), quoted: true, line: start})
+		default:
+			start := i
+			for i < len(text) && !strings.ContainsRune(" \t\r\n;{}#\"'", rune(text[i])) {
+				if text[i] == '\\' && i+1 < len(text) {
+					i++
+				}
+				i++
+			}
+			tokens = append(tokens, token{value: text[start:i], line: line})
+		}
+	}
+	return tokens, nil
+}
+
+// parse lê um arquivo de configuração do nginx
+// Faz só a checagem sintática (chaves balanceadas, diretivas terminadas em ";"),
+// sem conhecer o significado das diretivas
+func parse(text string) ([]directive, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+	block, rest, err := parseBlock(tokens, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("linha %d: \"}\" inesperado", rest[0].line)
+	}
+	return block, nil
+}
+
+// parseBlock lê diretivas até o "}" que fecha o bloco (depth > 0) ou até o fim
+func parseBlock(tokens []token, depth int) ([]directive, []token, error) {
+	var block []directive
+	for len(tokens) > 0 {
+		t := tokens[0]
+		if !t.quoted && t.value == "}" {
+			if depth == 0 {
+				return block, tokens, nil
+			}
+			return block, tokens[1:], nil
+		}
+		if !t.quoted && (t.value == ";" || t.value == "{") {
+			return nil, nil, fmt.Errorf("linha %d: \"%s\" inesperado", t.line, t.value)
+		}
+
+		d := directive{name: t.value, line: t.line}
+		tokens = tokens[1:]
+		for {
+			if len(tokens) == 0 {
+				return nil, nil, fmt.Errorf("linha %d: diretiva \"%s\" sem \";\" ou \"{\"", d.line, d.name)
+			}
+			next := tokens[0]
+			tokens = tokens[1:]
+			if next.quoted || (next.value != ";" && next.value != "{" && next.value != "}") {
+				d.args = append(d.args, next.value)
+				continue
+			}
+			if next.value == "}" {
+				return nil, nil, fmt.Errorf("linha %d: diretiva \"%s\" sem \";\"", d.line, d.name)
+			}
+			if next.value == "{" {
+				var err error
+				d.hasBlock = true
+				if d.block, tokens, err = parseBlock(tokens, depth+1); err != nil {
+					return nil, nil, err
+				}
+			}
+			break
+		}
+		block = append(block, d)
+	}
+	if depth > 0 {
+		return nil, nil, fmt.Errorf("bloco aberto sem \"}\" no fim do arquivo")
+	}
+	return block, nil, nil
+}
+
+// render gera os arquivos das rotas (ID -> conteúdo)
+// Cada arquivo tem o upstream da sua rota. O nginx atende cada host com um único server
+// block, então rotas que dividem um host (ex: loja.com e loja.com/v2) ficam no mesmo
+// server block, com uma location por prefixo; o bloco vai no arquivo da primeira rota
+// (por ID) que atende o host
+func render(routes []domain.Route) map[string]string {
+	sorted := append([]domain.Route(nil), routes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	byHost := make(map[string][]domain.Route)
+	for _, r := range sorted {
+		for _, h := range r.Hosts() {
+			if n := len(byHost[h]); n == 0 || byHost[h][n-1].ID != r.ID {
+				byHost[h] = append(byHost[h], r)
+			}
+		}
+	}
+
+	files := make(map[string]string, len(sorted))
+	for _, r := range sorted {
+		var b strings.Builder
+		fmt.Fprintf(&b, "# Gerado pelo OI (rota %s) - não edite: o arquivo é reescrito a cada deploy\n", r.ID)
+		renderUpstream(&b, r)
+
+		// Hosts dos quais a rota é dona, agrupados pelo conjunto de rotas que os atendem
+		var groups [][]string
+		groupIndex := make(map[string]int)
+		for _, h := range r.Hosts() {
+			owners := byHost[h]
+			if owners[0].ID != r.ID {
+				continue
+			}
+			key := routeIDs(owners)
+			i, ok := groupIndex[key]
+			if !ok {
+				i = len(groups)
+				groupIndex[key] = i
+				groups = append(groups, nil)
+			}
+			if !contains(groups[i], h) {
+				groups[i] = append(groups[i], h)
+			}
+		}
+		for _, hosts := range groups {
+			b.WriteString("\n")
+			renderServer(&b, hosts, byHost[hosts[0]])
+		}
+		files[r.ID] = b.String()
+	}
+	return files
+}
+
+// renderUpstream gera o bloco upstream da rota com as réplicas
+func renderUpstream(b *strings.Builder, r domain.Route) {
+	fmt.Fprintf(b, "upstream %s {\n", r.ID)
+
+	policy := r.PolicyOrDefault()
+	switch policy {
+	case domain.PolicyLeastConn:
+		b.WriteString("    least_conn;\n")
+	case domain.PolicyRandom:
+		b.WriteString("    random;\n")
+	case domain.PolicyIPHash:
+		b.WriteString("    ip_hash;\n")
+	}
+	for i, u := range r.Upstreams {
+		fmt.Fprintf(b, "    server %s", u.Address())
+		if u.Weight > 0 {
+			fmt.Fprintf(b, " weight=%d", u.Weight)
+		}
+		// first: as demais réplicas só recebem tráfego se a primeira cair
+		if policy == domain.PolicyFirst && i > 0 {
+			b.WriteString(" backup")
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+}
+
+// renderServer gera o server block dos hosts, com as locations das rotas que os atendem
+func renderServer(b *strings.Builder, hosts []string, routes []domain.Route) {
+	routes = append([]domain.Route(nil), routes...)
+	sort.SliceStable(routes, func(i, j int) bool { return routes[i].PathPrefix < routes[j].PathPrefix })
+
+	b.WriteString("server {\n")
+	b.WriteString("    listen 80;\n")
+	b.WriteString("    listen [::]:80;\n")
+	names := make([]string, 0, len(hosts))
+	for _, h := range hosts {
+		names = append(names, serverName(h))
+	}
+	fmt.Fprintf(b, "    server_name %s;\n", strings.Join(names, " "))
+	for _, r := range routes {
+		target := "http://" + r.ID
+		// Com a URI no proxy_pass, o nginx troca o trecho casado pela location por "/"
+		if r.StripPrefix {
+			target += "/"
+		}
+		if r.PathPrefix == "" {
+			renderLocation(b, "/", target)
+			continue
+		}
+		// "= /v2" atende o próprio prefixo e "^~ /v2/" os subcaminhos, sem casar "/v2beta"
+		renderLocation(b, "= "+r.PathPrefix, target)
+		renderLocation(b, "^~ "+r.PathPrefix+"/", target)
+	}
+	b.WriteString("}\n")
+}
+
+// wildcardPrefix inicia a regex de server_name gerada para hosts curinga
+const wildcardPrefix = `~^[^.]+\.`
+
+// serverName converte o host no nome usado em server_name
+// No nginx "*.loja.com" também casa "a.b.loja.com"; o curinga do OI vale para um só
+// nível de subdomínio, então vira a regex "~^[^.]+\.loja\.com$"
+func serverName(host string) string {
+	suffix, ok := strings.CutPrefix(host, "*.")
+	if !ok {
+		return host
+	}
+	return wildcardPrefix + regexp.QuoteMeta(suffix) + "$"
+}
+
+// hostOf é o inverso de serverName (outros nomes ficam como estão)
+func hostOf(name string) string {
+	pattern, ok := strings.CutPrefix(name, wildcardPrefix)
+	if !ok {
+		return name
+	}
+	suffix, ok := strings.CutSuffix(pattern, "$")
+	if !ok {
+		return name
+	}
+	return "*." + strings.ReplaceAll(suffix, `\.`, ".")
+}
+
+func renderLocation(b *strings.Builder, match string, target string) {
+	fmt.Fprintf(b, "\n    location %s {\n", match)
+	fmt.Fprintf(b, "        proxy_pass %s;\n", target)
+	b.WriteString("        proxy_http_version 1.1;\n")
+	b.WriteString("        proxy_set_header Host $host;\n")
+	b.WriteString("        proxy_set_header X-Real-IP $remote_addr;\n")
+	b.WriteString("        proxy_set_header X-Forwarded-For $proxy_add_x_forwarded_for;\n")
+	b.WriteString("        proxy_set_header X-Forwarded-Proto $scheme;\n")
+	b.WriteString("    }\n")
+}
+
+func routeIDs(routes []domain.Route) string {
+	ids := make([]string, 0, len(routes))
+	for _, r := range routes {
+		ids = append(ids, r.ID)
+	}
+	return strings.Join(ids, " ")
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// managedFile é o conteúdo de um arquivo de rota do OI já interpretado
+type managedFile []directive
+
+// parseRoutes reconstrói as rotas a partir dos arquivos gerenciados (inverso de render)
+// Os upstreams vêm dos blocos upstream e os endereços das locations dos server blocks,
+// que podem estar no arquivo de outra rota. Rotas sem upstreams ou sem hosts são ignoradas
+func parseRoutes(files []managedFile) []domain.Route {
+	var routes []*domain.Route
+	byID := make(map[string]*domain.Route)
+	get := func(id string) *domain.Route {
+		if r, ok := byID[id]; ok {
+			return r
+		}
+		r := &domain.Route{ID: id}
+		byID[id] = r
+		routes = append(routes, r)
+		return r
+	}
+
+	for _, f := range files {
+		for _, d := range f {
+			if !d.hasBlock {
+				continue
+			}
+			switch d.name {
+			case "upstream":
+				if len(d.args) > 0 {
+					r := get(d.args[0])
+					r.Policy, r.Upstreams = parseUpstream(d)
+				}
+			case "server":
+				var hosts []string
+				for _, name := range d.find("server_name") {
+					hosts = append(hosts, name.args...)
+				}
+				for _, location := range d.find("location") {
+					id, path, strip, ok := parseLocation(location)
+					if !ok {
+						continue
+					}
+					r := get(id)
+					r.PathPrefix, r.StripPrefix = path, strip
+					for _, h := range hosts {
+						addHost(r, strings.ToLower(hostOf(h)))
+					}
+				}
+			}
+		}
+	}
+
+	result := make([]domain.Route, 0, len(routes))
+	for _, r := range routes {
+		if r.Domain != "" && len(r.Upstreams) > 0 {
+			result = append(result, *r)
+		}
+	}
+	return result
+}
+
+// addHost inclui o host na rota (o primeiro vira Domain, os demais Aliases)
+func addHost(r *domain.Route, host string) {
+	switch {
+	case r.Domain == "":
+		r.Domain = host
+	case r.Domain != host && !contains(r.Aliases, host):
+		r.Aliases = append(r.Aliases, host)
+	}
+}
+
+// parseUpstream lê a política e os servidores de um bloco upstream
+func parseUpstream(d directive) (string, []domain.Upstream) {
+	var policy string
+	var upstreams []domain.Upstream
+	weighted := false
+	for _, child := range d.block {
+		switch child.name {
+		case "least_conn":
+			policy = domain.PolicyLeastConn
+		case "random":
+			policy = domain.PolicyRandom
+		case "ip_hash":
+			policy = domain.PolicyIPHash
+		case "server":
+			if len(child.args) == 0 {
+				continue
+			}
+			host, portStr, err := net.SplitHostPort(child.args[0])
+			if err != nil {
+				continue
+			}
+			port, _ := strconv.Atoi(portStr)
+			u := domain.Upstream{Host: host, Port: port}
+			for _, arg := range child.args[1:] {
+				if value, ok := strings.CutPrefix(arg, "weight="); ok {
+					u.Weight, _ = strconv.Atoi(value)
+					weighted = true
+				}
+				if arg == "backup" {
+					policy = domain.PolicyFirst
+				}
+			}
+			upstreams = append(upstreams, u)
+		}
+	}
+	if policy == "" && weighted {
+		policy = domain.PolicyWeightedRoundRobin
+	}
+	return policy, upstreams
+}
+
+// parseLocation lê a rota (nome do upstream no proxy_pass), o prefixo e a remoção do
+// prefixo de uma location gerada por renderServer
+func parseLocation(d directive) (id string, path string, strip bool, ok bool) {
+	switch {
+	case len(d.args) == 1 && d.args[0] == "/":
+	case len(d.args) == 2 && d.args[0] == "=":
+		path = d.args[1]
+	case len(d.args) == 2 && d.args[0] == "^~":
+		path = strings.TrimSuffix(d.args[1], "/")
+	default:
+		return "", "", false, false
+	}
+
+	for _, pass := range d.find("proxy_pass") {
+		if len(pass.args) == 0 {
+			continue
+		}
+		target := strings.TrimPrefix(strings.TrimPrefix(pass.args[0], "http://"), "https://")
+		strip = strings.HasSuffix(target, "/")
+		id = strings.TrimSuffix(target, "/")
+		return id, path, strip, id != ""
+	}
+	return "", "", false, false
+}
@@ -0,0 +1,427 @@
+package nginx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/crom-tech/oi/internal/atomicfile"
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// Valores padrão da integração com o nginx
+const (
+	DefaultDir     = "/etc/nginx/oi.d"
+	DefaultBinary  = "nginx"
+	DefaultPidFile = "/run/nginx.pid"
+)
+
+// fileExt é a extensão dos arquivos gerados (incluídos com "include <dir>/*.conf;")
+const fileExt = ".conf"
+
+// Manager implementa port.ProxyManager gerando um server block por host
+// Cada rota vira um arquivo <id>.conf no diretório gerenciado, incluído no bloco http
+// do nginx.conf (ver render para hosts com várias rotas). Toda alteração é validada
+// (nginx -t) e aplicada com reload; se a validação falhar, os arquivos anteriores são
+// restaurados
+type Manager struct {
+	dir     string
+	binary  string
+	pidFile string
+
+	// lookPath, run e signal isolam o acesso ao sistema (substituídos nos testes)
+	lookPath func(file string) (string, error)
+	run      func(ctx context.Context, name string, args ...string) ([]byte, error)
+	signal   func(pid int, sig syscall.Signal) error
+}
+
+// NewManager cria uma nova instância do Nginx Manager
+// Valores vazios usam DefaultDir, DefaultBinary e DefaultPidFile
+func NewManager(dir string, binary string, pidFile string) *Manager {
+	if dir == "" {
+		dir = DefaultDir
+	}
+	if binary == "" {
+		binary = DefaultBinary
+	}
+	if pidFile == "" {
+		pidFile = DefaultPidFile
+	}
+	return &Manager{
+		dir:      dir,
+		binary:   binary,
+		pidFile:  pidFile,
+		lookPath: exec.LookPath,
+		run:      runCommand,
+		signal:   signalProcess,
+	}
+}
+
+// Dir retorna o diretório dos server blocks gerenciados
+func (m *Manager) Dir() string {
+	return m.dir
+}
+
+// Binary retorna o caminho do executável do nginx, se estiver disponível
+func (m *Manager) Binary() (string, bool) {
+	path, err := m.lookPath(m.binary)
+	return path, err == nil
+}
+
+// PidFile retorna o pid file do processo master do nginx
+func (m *Manager) PidFile() string {
+	return m.pidFile
+}
+
+// AddRoute grava (ou substitui) a rota, valida e recarrega o nginx
+// Outras rotas do OI para os mesmos endereços são removidas na mesma operação; rotas que
+// dividem um host com ela (outros prefixos) têm o server block regravado junto
+func (m *Manager) AddRoute(ctx context.Context, r domain.Route) error {
+	if r.ID == "" {
+		return fmt.Errorf("rota para %s sem ID", r.Domain)
+	}
+	if len(r.Upstreams) == 0 {
+		return fmt.Errorf("rota %s sem upstreams", r.ID)
+	}
+
+	files, err := m.listRoutes()
+	if err != nil {
+		return err
+	}
+	routes := []domain.Route{r}
+	removed := make(map[string]bool)
+	for _, other := range parseRoutes(configs(files)) {
+		switch {
+		case other.ID == r.ID:
+		case other.Overlaps(r):
+			removed[other.ID] = true
+		default:
+			routes = append(routes, other)
+		}
+	}
+
+	writes, removes, err := m.changes(files, routes, removed)
+	if err != nil {
+		return err
+	}
+	// A rota informada é sempre regravada e recarregada, mesmo sem mudanças
+	if _, ok := writes[m.routePath(r.ID)]; !ok {
+		writes[m.routePath(r.ID)] = []byte(render(routes)[r.ID])
+	}
+
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return fmt.Errorf("falha ao criar diretório %s: %w", m.dir, err)
+	}
+	return m.apply(ctx, writes, removes)
+}
+
+// RemoveRoute remove a rota e outras do OI para os mesmos endereços
+func (m *Manager) RemoveRoute(ctx context.Context, r domain.Route) error {
+	files, err := m.listRoutes()
+	if err != nil {
+		return err
+	}
+	var routes []domain.Route
+	removed := map[string]bool{r.ID: r.ID != ""}
+	for _, other := range parseRoutes(configs(files)) {
+		if other.ID == r.ID || other.Overlaps(r) {
+			removed[other.ID] = true
+			continue
+		}
+		routes = append(routes, other)
+	}
+
+	writes, removes, err := m.changes(files, routes, removed)
+	if err != nil {
+		return err
+	}
+	if len(writes) == 0 && len(removes) == 0 {
+		return nil
+	}
+	return m.apply(ctx, writes, removes)
+}
+
+// changes calcula os arquivos a gravar (só os que mudam) e a remover para que o
+// diretório passe a conter as rotas informadas
+func (m *Manager) changes(files []routeFile, routes []domain.Route, removed map[string]bool) (map[string][]byte, []string, error) {
+	current := make(map[string]string, len(files))
+	var removes []string
+	for _, f := range files {
+		current[f.id] = f.content
+		if removed[f.id] {
+			removes = append(removes, f.path)
+		}
+	}
+
+	writes := make(map[string][]byte)
+	for id, content := range render(routes) {
+		if _, err := parse(content); err != nil {
+			return nil, nil, fmt.Errorf("configuração gerada inválida para a rota %s: %w", id, err)
+		}
+		if existing, ok := current[id]; !ok || existing != content {
+			writes[m.routePath(id)] = []byte(content)
+		}
+	}
+	return writes, removes, nil
+}
+
+// apply grava e remove os arquivos, valida a configuração e recarrega o nginx
+// Se a validação ou o reload falharem, restaura o conteúdo anterior e o nginx segue com a
+// configuração em uso (e o diretório continua refletindo ela)
+func (m *Manager) apply(ctx context.Context, writes map[string][]byte, removes []string) error {
+	// Guarda o estado anterior de tudo que será alterado (nil = não existia)
+	previous := make(map[string][]byte)
+	for _, path := range append(sortedKeys(writes), removes...) {
+		data, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("falha ao ler %s: %w", path, err)
+		}
+		previous[path] = data
+	}
+
+	restore := func() {
+		for path, data := range previous {
+			if data == nil {
+				os.Remove(path)
+			} else {
+				atomicfile.Write(path, data, 0644)
+			}
+		}
+	}
+
+	for _, path := range sortedKeys(writes) {
+		if err := atomicfile.Write(path, writes[path], 0644); err != nil {
+			restore()
+			return fmt.Errorf("falha ao gravar %s: %w", path, err)
+		}
+	}
+	for _, path := range removes {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			restore()
+			return fmt.Errorf("falha ao remover %s: %w", path, err)
+		}
+	}
+
+	if err := m.validate(ctx); err != nil {
+		restore()
+		return err
+	}
+	if err := m.Reload(ctx); err != nil {
+		restore()
+		return err
+	}
+	return nil
+}
+
+// validate roda "nginx -t" ou, sem o executável, a checagem sintática dos arquivos do diretório
+func (m *Manager) validate(ctx context.Context) error {
+	if bin, ok := m.Binary(); ok {
+		out, err := m.run(ctx, bin, "-t")
+		if err != nil {
+			return fmt.Errorf("configuração do nginx inválida (nginx -t): %s", commandOutput(out, err))
+		}
+		return nil
+	}
+
+	entries, err := os.ReadDir(m.dir)
+	if err != nil {
+		return fmt.Errorf("falha ao listar %s: %w", m.dir, err)
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), fileExt) {
+			continue
+		}
+		path := filepath.Join(m.dir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("falha ao ler %s: %w", path, err)
+		}
+		if _, err := parse(string(data)); err != nil {
+			return fmt.Errorf("configuração do nginx inválida em %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// HasRoute verifica se uma rota existe
+func (m *Manager) HasRoute(ctx context.Context, site string) (bool, error) {
+	upstreams, err := m.GetUpstreams(ctx, site)
+	if err != nil {
+		return false, err
+	}
+	return len(upstreams) > 0, nil
+}
+
+// GetUpstreams retorna os upstreams (host:porta) da rota do endereço ("host[/prefixo]")
+// Lê os arquivos gerenciados, que são a fonte da verdade da configuração do nginx
+func (m *Manager) GetUpstreams(ctx context.Context, site string) ([]string, error) {
+	target, err := domain.ParseSite(site)
+	if err != nil {
+		return nil, err
+	}
+	files, err := m.listRoutes()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range parseRoutes(configs(files)) {
+		if r.Serves(target) {
+			return r.Addresses(), nil
+		}
+	}
+	return nil, nil
+}
+
+// routeFile é um arquivo de rota do OI no diretório gerenciado
+type routeFile struct {
+	id      string
+	path    string
+	content string
+	config  managedFile
+}
+
+// configs retorna o conteúdo interpretado dos arquivos
+func configs(files []routeFile) []managedFile {
+	result := make([]managedFile, 0, len(files))
+	for _, f := range files {
+		result = append(result, f.config)
+	}
+	return result
+}
+
+// listRoutes lê os arquivos de rota do OI (oi-*.conf), em ordem de nome
+// Arquivos de outras origens no mesmo diretório são ignorados
+func (m *Manager) listRoutes() ([]routeFile, error) {
+	entries, err := os.ReadDir(m.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar %s: %w", m.dir, err)
+	}
+
+	var files []routeFile
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, "oi-") || !strings.HasSuffix(name, fileExt) {
+			continue
+		}
+		path := filepath.Join(m.dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler %s: %w", path, err)
+		}
+		config, err := parse(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("falha ao parsear %s: %w", path, err)
+		}
+		files = append(files, routeFile{
+			id:      strings.TrimSuffix(name, fileExt),
+			path:    path,
+			content: string(data),
+			config:  config,
+		})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].id < files[j].id })
+	return files, nil
+}
+
+func (m *Manager) routePath(id string) string {
+	return filepath.Join(m.dir, id+fileExt)
+}
+
+// Reload recarrega o nginx ("nginx -s reload" ou SIGHUP no processo master)
+// O nginx só troca os workers depois de carregar a nova configuração: requisições em
+// andamento terminam nos workers antigos
+func (m *Manager) Reload(ctx context.Context) error {
+	if bin, ok := m.Binary(); ok {
+		out, err := m.run(ctx, bin, "-s", "reload")
+		if err != nil {
+			return fmt.Errorf("falha ao recarregar o nginx: %s", commandOutput(out, err))
+		}
+		return nil
+	}
+
+	pid, err := m.masterPID()
+	if err != nil {
+		return fmt.Errorf("falha ao recarregar o nginx: %w", err)
+	}
+	if err := m.signal(pid, syscall.SIGHUP); err != nil {
+		return fmt.Errorf("falha ao recarregar o nginx (pid %d): %w", pid, err)
+	}
+	return nil
+}
+
+// Health verifica se o processo master do nginx está rodando e se o diretório gerenciado é acessível
+func (m *Manager) Health(ctx context.Context) error {
+	pid, err := m.masterPID()
+	if err != nil {
+		return fmt.Errorf("nginx não está rodando: %w", err)
+	}
+	// Sinal 0 só verifica a existência; sem permissão, o processo existe (ex: master do root)
+	if err := m.signal(pid, syscall.Signal(0)); err != nil && !errors.Is(err, os.ErrPermission) {
+		return fmt.Errorf("nginx não está rodando (pid %d em %s): %w", pid, m.pidFile, err)
+	}
+	// Só consulta, sem criar nada: antes do primeiro deploy basta existir o diretório pai
+	dir := m.dir
+	info, err := os.Stat(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		dir = filepath.Dir(m.dir)
+		info, err = os.Stat(dir)
+	}
+	if err != nil {
+		return fmt.Errorf("diretório %s inacessível: %w", m.dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("diretório %s inacessível: %s não é um diretório", m.dir, dir)
+	}
+	return nil
+}
+
+// masterPID lê o pid do processo master no pid file
+func (m *Manager) masterPID() (int, error) {
+	data, err := os.ReadFile(m.pidFile)
+	if err != nil {
+		return 0, fmt.Errorf("falha ao ler pid file: %w", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, fmt.Errorf("pid file %s inválido", m.pidFile)
+	}
+	return pid, nil
+}
+
+func sortedKeys(m map[string][]byte) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// commandOutput resume a saída de um comando que falhou (ou o erro, sem saída)
+func commandOutput(out []byte, err error) string {
+	if msg := strings.TrimSpace(string(out)); msg != "" {
+		return msg
+	}
+	return err.Error()
+}
+
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, name, args...).CombinedOutput()
+}
+
+func signalProcess(pid int, sig syscall.Signal) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Signal(sig)
+}
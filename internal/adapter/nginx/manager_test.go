@@ -0,0 +1,444 @@
+package nginx
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// system registra as chamadas ao nginx feitas pelo Manager nos testes
+type system struct {
+	// binary vazio simula o nginx fora do PATH
+	binary   string
+	commands []string
+	signals  []syscall.Signal
+	// testErr faz "nginx -t" falhar com a saída informada
+	testErr string
+	// signalErr faz o envio de sinais falhar
+	signalErr error
+}
+
+func newTestManager(t *testing.T, sys *system) *Manager {
+	t.Helper()
+	tmp := t.TempDir()
+	pidFile := filepath.Join(tmp, "nginx.pid")
+	if err := os.WriteFile(pidFile, []byte("4242\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager(filepath.Join(tmp, "oi.d"), "", pidFile)
+	m.lookPath = func(file string) (string, error) {
+		if sys.binary == "" {
+			return "", errors.New("executable file not found in $PATH")
+		}
+		return sys.binary, nil
+	}
+	m.run = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		cmd := strings.Join(append([]string{name}, args...), " ")
+		sys.commands = append(sys.commands, cmd)
+		if sys.testErr != "" && cmd == sys.binary+" -t" {
+			return []byte(sys.testErr), errors.New("exit status 1")
+		}
+		return nil, nil
+	}
+	m.signal = func(pid int, sig syscall.Signal) error {
+		if pid != 4242 {
+			t.Errorf("sinal enviado ao pid %d, esperado 4242", pid)
+		}
+		sys.signals = append(sys.signals, sig)
+		return sys.signalErr
+	}
+	return m
+}
+
+func route(id, domainName string, upstreams ...string) domain.Route {
+	r := domain.Route{ID: id, Domain: domainName}
+	for _, u := range upstreams {
+		host, port, _ := strings.Cut(u, ":")
+		p, _ := strconv.Atoi(port)
+		r.Upstreams = append(r.Upstreams, domain.Upstream{Host: host, Port: p})
+	}
+	return r
+}
+
+func readFile(t *testing.T, m *Manager, id string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(m.Dir(), id+fileExt))
+	if err != nil {
+		t.Fatalf("arquivo da rota %s não encontrado: %v", id, err)
+	}
+	return string(data)
+}
+
+func TestAddRouteWritesServerBlockAndReloads(t *testing.T) {
+	sys := &system{binary: "/usr/sbin/nginx"}
+	m := newTestManager(t, sys)
+	ctx := context.Background()
+
+	r := route("oi-loja", "loja.com", "oi-loja-1:80", "oi-loja-2:80")
+	r.Policy = domain.PolicyLeastConn
+	if err := m.AddRoute(ctx, r); err != nil {
+		t.Fatalf("AddRoute: %v", err)
+	}
+
+	content := readFile(t, m, "oi-loja")
+	for _, want := range []string{
+		"upstream oi-loja {",
+		"least_conn;",
+		"server oi-loja-1:80;",
+		"server_name loja.com;",
+		"proxy_pass http://oi-loja;",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("arquivo sem %q:\n%s", want, content)
+		}
+	}
+	if want := []string{"/usr/sbin/nginx -t", "/usr/sbin/nginx -s reload"}; !reflect.DeepEqual(sys.commands, want) {
+		t.Errorf("comandos = %v; esperado %v", sys.commands, want)
+	}
+
+	upstreams, err := m.GetUpstreams(ctx, "loja.com")
+	if err != nil {
+		t.Fatalf("GetUpstreams: %v", err)
+	}
+	if !reflect.DeepEqual(upstreams, []string{"oi-loja-1:80", "oi-loja-2:80"}) {
+		t.Errorf("upstreams inesperados: %v", upstreams)
+	}
+}
+
+func TestAddRoutePolicies(t *testing.T) {
+	tests := []struct {
+		policy string
+		want   []string
+	}{
+		{policy: domain.PolicyIPHash, want: []string{"ip_hash;", "server a:80;", "server b:80;"}},
+		{policy: domain.PolicyRandom, want: []string{"random;"}},
+		{policy: domain.PolicyFirst, want: []string{"server a:80;", "server b:80 backup;"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			m := newTestManager(t, &system{})
+			r := route("oi-loja", "loja.com", "a:80", "b:80")
+			r.Policy = tt.policy
+			if err := m.AddRoute(context.Background(), r); err != nil {
+				t.Fatalf("AddRoute: %v", err)
+			}
+			content := readFile(t, m, "oi-loja")
+			for _, want := range tt.want {
+				if !strings.Contains(content, want) {
+					t.Errorf("arquivo sem %q:\n%s", want, content)
+				}
+			}
+		})
+	}
+}
+
+func TestAddRouteCanaryWeights(t *testing.T) {
+	m := newTestManager(t, &system{})
+	stable := []domain.Container{{Name: "oi-loja-v1", Project: "loja", Port: 80}}
+	canary := []domain.Container{{Name: "oi-loja-v2", Project: "loja", Port: 80}}
+	r := domain.CanaryRoutes([]domain.Site{{Host: "loja.com"}}, false, stable, canary, 10)[0]
+
+	if err := m.AddRoute(context.Background(), r); err != nil {
+		t.Fatalf("AddRoute: %v", err)
+	}
+	content := readFile(t, m, "oi-loja")
+	if !strings.Contains(content, "server oi-loja-v1:80 weight=90;") || !strings.Contains(content, "server oi-loja-v2:80 weight=10;") {
+		t.Errorf("pesos do canário ausentes:\n%s", content)
+	}
+}
+
+func TestAddRouteWithoutBinaryUsesSyntaxCheckAndSignal(t *testing.T) {
+	sys := &system{}
+	m := newTestManager(t, sys)
+
+	if err := m.AddRoute(context.Background(), route("oi-loja", "loja.com", "a:80")); err != nil {
+		t.Fatalf("AddRoute: %v", err)
+	}
+	if len(sys.commands) != 0 {
+		t.Errorf("nenhum comando esperado sem o executável, obtido %v", sys.commands)
+	}
+	if !reflect.DeepEqual(sys.signals, []syscall.Signal{syscall.SIGHUP}) {
+		t.Errorf("sinais = %v; esperado SIGHUP", sys.signals)
+	}
+}
+
+func TestAddRouteRestoresOnValidationFailure(t *testing.T) {
+	sys := &system{binary: "/usr/sbin/nginx"}
+	m := newTestManager(t, sys)
+	ctx := context.Background()
+
+	if err := m.AddRoute(ctx, route("oi-antigo", "loja.com", "antigo:80")); err != nil {
+		t.Fatalf("AddRoute: %v", err)
+	}
+	before := readFile(t, m, "oi-antigo")
+
+	sys.commands = nil
+	sys.testErr = "nginx: [emerg] host not found in upstream \"novo:80\""
+	err := m.AddRoute(ctx, route("oi-loja", "loja.com", "novo:80"))
+	if err == nil || !strings.Contains(err.Error(), "host not found") {
+		t.Fatalf("esperado erro do nginx -t, obtido %v", err)
+	}
+
+	// A rota antiga (removida na mesma operação) volta e a nova não fica no diretório
+	if got := readFile(t, m, "oi-antigo"); got != before {
+		t.Errorf("rota antiga não restaurada:\n%s", got)
+	}
+	if _, err := os.Stat(filepath.Join(m.Dir(), "oi-loja"+fileExt)); !os.IsNotExist(err) {
+		t.Error("rota nova deveria ter sido descartada")
+	}
+	for _, cmd := range sys.commands {
+		if strings.Contains(cmd, "reload") {
+			t.Error("nginx não deveria ser recarregado com a configuração inválida")
+		}
+	}
+}
+
+func TestAddRouteRestoresOnReloadFailure(t *testing.T) {
+	sys := &system{}
+	m := newTestManager(t, sys)
+	ctx := context.Background()
+
+	if err := m.AddRoute(ctx, route("oi-loja", "loja.com", "antigo:80")); err != nil {
+		t.Fatalf("AddRoute: %v", err)
+	}
+	before := readFile(t, m, "oi-loja")
+
+	sys.signalErr = syscall.ESRCH
+	if err := m.AddRoute(ctx, route("oi-loja", "loja.com", "novo:80")); err == nil || !strings.Contains(err.Error(), "recarregar") {
+		t.Fatalf("esperado erro do reload, obtido %v", err)
+	}
+	if got := readFile(t, m, "oi-loja"); got != before {
+		t.Errorf("arquivo deveria refletir a configuração em uso:\n%s", got)
+	}
+}
+
+func TestAddRouteReplacesConflictingFiles(t *testing.T) {
+	m := newTestManager(t, &system{})
+	ctx := context.Background()
+
+	for _, r := range []domain.Route{
+		route("oi-antigo", "loja.com", "antigo:80"),
+		route("oi-blog", "blog.com", "blog:80"),
+	} {
+		if err := m.AddRoute(ctx, r); err != nil {
+			t.Fatalf("AddRoute: %v", err)
+		}
+	}
+	manual := filepath.Join(m.Dir(), "manual.conf")
+	if err := os.WriteFile(manual, []byte("server { server_name loja.com; }\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.AddRoute(ctx, route("oi-loja", "loja.com", "novo:80")); err != nil {
+		t.Fatalf("AddRoute: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(m.Dir(), "oi-antigo"+fileExt)); !os.IsNotExist(err) {
+		t.Error("rota antiga para o mesmo domínio deveria ter sido removida")
+	}
+	if _, err := os.Stat(manual); err != nil {
+		t.Errorf("arquivo de outra origem removido: %v", err)
+	}
+	if upstreams, _ := m.GetUpstreams(ctx, "blog.com"); !reflect.DeepEqual(upstreams, []string{"blog:80"}) {
+		t.Errorf("rota de outro domínio alterada: %v", upstreams)
+	}
+	if upstreams, _ := m.GetUpstreams(ctx, "loja.com"); !reflect.DeepEqual(upstreams, []string{"novo:80"}) {
+		t.Errorf("upstreams inesperados: %v", upstreams)
+	}
+}
+
+func TestAddRouteSharedHostAndPathPrefix(t *testing.T) {
+	m := newTestManager(t, &system{})
+	ctx := context.Background()
+
+	site := route("oi-loja", "loja.com", "site:80")
+	site.Aliases = []string{"www.loja.com"}
+	api := route("oi-api", "loja.com", "api:80")
+	api.PathPrefix = "/v2"
+	api.StripPrefix = true
+	tenants := route("oi-loja~curinga", "*.loja.com", "site:80")
+	for _, r := range []domain.Route{site, api, tenants} {
+		if err := m.AddRoute(ctx, r); err != nil {
+			t.Fatalf("AddRoute: %v", err)
+		}
+	}
+
+	// loja.com fica num só server block (no arquivo de oi-api, primeiro ID) com as duas
+	// locations; www.loja.com continua no arquivo de oi-loja
+	content := readFile(t, m, "oi-api")
+	for _, want := range []string{
+		"server_name loja.com;",
+		"location / {\n        proxy_pass http://oi-loja;",
+		"location = /v2 {\n        proxy_pass http://oi-api/;",
+		"location ^~ /v2/ {\n        proxy_pass http://oi-api/;",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("arquivo sem %q:\n%s", want, content)
+		}
+	}
+	if content := readFile(t, m, "oi-loja"); !strings.Contains(content, "server_name www.loja.com;") || strings.Contains(content, "server_name loja.com") {
+		t.Errorf("server blocks inesperados em oi-loja:\n%s", content)
+	}
+
+	// O curinga vale para um só nível: vira regex, já que "*.loja.com" no nginx também
+	// casaria a.b.loja.com
+	content = readFile(t, m, "oi-loja~curinga")
+	const wildcard = `server_name ~^[^.]+\.loja\.com$;`
+	if !strings.Contains(content, wildcard) {
+		t.Fatalf("arquivo sem %q:\n%s", wildcard, content)
+	}
+	pattern := regexp.MustCompile(strings.TrimSuffix(strings.TrimPrefix(wildcard, "server_name ~"), ";"))
+	for host, want := range map[string]bool{"cliente.loja.com": true, "a.b.loja.com": false, "loja.com": false, "cliente.lojaxcom": false} {
+		if got := pattern.MatchString(host); got != want {
+			t.Errorf("server_name casa %s = %v; esperado %v", host, got, want)
+		}
+	}
+
+	for site, want := range map[string][]string{
+		"loja.com":     {"site:80"},
+		"www.loja.com": {"site:80"},
+		"loja.com/v2":  {"api:80"},
+		"*.loja.com":   {"site:80"},
+	} {
+		if upstreams, _ := m.GetUpstreams(ctx, site); !reflect.DeepEqual(upstreams, want) {
+			t.Errorf("GetUpstreams(%s) = %v; esperado %v", site, upstreams, want)
+		}
+	}
+
+	// Sem a rota do prefixo, o server block de loja.com volta para o arquivo de oi-loja
+	if err := m.RemoveRoute(ctx, api); err != nil {
+		t.Fatalf("RemoveRoute: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(m.Dir(), "oi-api"+fileExt)); !os.IsNotExist(err) {
+		t.Error("arquivo de oi-api deveria ter sido removido")
+	}
+	if content := readFile(t, m, "oi-loja"); !strings.Contains(content, "server_name loja.com www.loja.com;") {
+		t.Errorf("server block de loja.com ausente:\n%s", content)
+	}
+	if upstreams, _ := m.GetUpstreams(ctx, "loja.com"); !reflect.DeepEqual(upstreams, []string{"site:80"}) {
+		t.Errorf("upstreams inesperados: %v", upstreams)
+	}
+}
+
+func TestAddRouteInvalidRoute(t *testing.T) {
+	m := newTestManager(t, &system{})
+	if err := m.AddRoute(context.Background(), route("", "loja.com", "a:80")); err == nil {
+		t.Error("esperado erro para rota sem ID")
+	}
+	if err := m.AddRoute(context.Background(), route("oi-loja", "loja.com")); err == nil {
+		t.Error("esperado erro para rota sem upstreams")
+	}
+}
+
+func TestRemoveRoute(t *testing.T) {
+	sys := &system{}
+	m := newTestManager(t, sys)
+	ctx := context.Background()
+
+	if err := m.AddRoute(ctx, route("oi-loja", "loja.com", "a:80")); err != nil {
+		t.Fatalf("AddRoute: %v", err)
+	}
+	if ok, err := m.HasRoute(ctx, "loja.com"); err != nil || !ok {
+		t.Fatalf("HasRoute = %v, %v; esperado true", ok, err)
+	}
+
+	if err := m.RemoveRoute(ctx, route("oi-loja", "loja.com")); err != nil {
+		t.Fatalf("RemoveRoute: %v", err)
+	}
+	if ok, err := m.HasRoute(ctx, "loja.com"); err != nil || ok {
+		t.Errorf("HasRoute = %v, %v; esperado false", ok, err)
+	}
+
+	// Sem nada a remover, não há reload
+	sys.signals = nil
+	if err := m.RemoveRoute(ctx, route("oi-loja", "loja.com")); err != nil {
+		t.Errorf("RemoveRoute repetido: %v", err)
+	}
+	if len(sys.signals) != 0 {
+		t.Errorf("reload desnecessário: %v", sys.signals)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	ctx := context.Background()
+
+	m := newTestManager(t, &system{})
+	if err := m.Health(ctx); err != nil {
+		t.Errorf("Health: %v", err)
+	}
+
+	// Master de outro usuário (sem permissão para sinalizar) conta como rodando
+	m = newTestManager(t, &system{signalErr: syscall.EPERM})
+	if err := m.Health(ctx); err != nil {
+		t.Errorf("Health com EPERM: %v", err)
+	}
+
+	m = newTestManager(t, &system{signalErr: syscall.ESRCH})
+	if err := m.Health(ctx); err == nil {
+		t.Error("esperado erro com o processo inexistente")
+	}
+
+	m = newTestManager(t, &system{})
+	os.Remove(m.PidFile())
+	if err := m.Health(ctx); err == nil {
+		t.Error("esperado erro sem pid file")
+	}
+
+	// Health não cria o diretório gerenciado
+	m = newTestManager(t, &system{})
+	if err := m.Health(ctx); err != nil {
+		t.Errorf("Health antes do primeiro deploy: %v", err)
+	}
+	if _, err := os.Stat(m.Dir()); !os.IsNotExist(err) {
+		t.Error("Health não deveria criar o diretório")
+	}
+	if err := os.WriteFile(m.Dir(), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Health(ctx); err == nil {
+		t.Error("esperado erro com um arquivo no lugar do diretório")
+	}
+}
+
+func TestParse(t *testing.T) {
+	valid := `
+# comentário
+http {
+    log_format main '$remote_addr "$request"';  # aspas e comentário no fim
+    server {
+        listen 80;
+        location / { return 200 "ok;}"; }
+    }
+}
+`
+	config, err := parse(valid)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(config) != 1 || config[0].name != "http" || len(config[0].find("server")) != 1 {
+		t.Errorf("estrutura inesperada: %+v", config)
+	}
+
+	invalid := map[string]string{
+		"sem ponto e vírgula": "server { listen 80 }",
+		"chave não fechada":   "server { listen 80;",
+		"chave sobrando":      "server { listen 80; } }",
+		"aspas não fechadas":  `server { return 200 "ok; }`,
+		"diretiva no fim":     "worker_processes 4",
+		"bloco sem nome":      "{ listen 80; }",
+	}
+	for name, text := range invalid {
+		if _, err := parse(text); err == nil {
+			t.Errorf("%s: esperado erro de sintaxe", name)
+		}
+	}
+}
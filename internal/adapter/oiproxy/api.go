@@ -0,0 +1,81 @@
+package oiproxy
+
+import "github.com/crom-tech/oi/internal/core/domain"
+
+// Valores padrão do proxy embutido
+const (
+	DefaultSocket = "/run/oi/proxy.sock"
+	DefaultListen = ":80"
+	DefaultState  = "/var/lib/oi/proxy-routes.json"
+)
+
+// Endpoints da admin API (servida só no socket unix)
+//
+//	GET    /health                       status e número de rotas
+//	GET    /routes                       todas as rotas
+//	PUT    /routes                       adiciona ou substitui a rota (corpo: routeJSON)
+//	DELETE /routes?id=<id>&domain=<d>    remove pelo ID e/ou endereço (host[/prefixo])
+const (
+	pathHealth = "/health"
+	pathRoutes = "/routes"
+)
+
+// routeJSON é a rota no formato da admin API e do arquivo de estado
+type routeJSON struct {
+	ID          string         `json:"id"`
+	Domain      string         `json:"domain"`
+	Aliases     []string       `json:"aliases,omitempty"`
+	PathPrefix  string         `json:"path_prefix,omitempty"`
+	StripPrefix bool           `json:"strip_prefix,omitempty"`
+	Policy      string         `json:"policy,omitempty"`
+	Upstreams   []upstreamJSON `json:"upstreams"`
+}
+
+type upstreamJSON struct {
+	Host   string `json:"host"`
+	Port   int    `json:"port"`
+	Weight int    `json:"weight,omitempty"`
+}
+
+// healthJSON é a resposta de GET /health
+type healthJSON struct {
+	Status string `json:"status"`
+	Routes int    `json:"routes"`
+	Listen string `json:"listen"`
+}
+
+// errorJSON é o corpo das respostas de erro da admin API
+type errorJSON struct {
+	Error string `json:"error"`
+}
+
+func toJSON(r domain.Route) routeJSON {
+	out := routeJSON{
+		ID:          r.ID,
+		Domain:      r.Domain,
+		Aliases:     r.Aliases,
+		PathPrefix:  r.PathPrefix,
+		StripPrefix: r.StripPrefix,
+		Policy:      r.Policy,
+		Upstreams:   []upstreamJSON{},
+	}
+	for _, u := range r.Upstreams {
+		out.Upstreams = append(out.Upstreams, upstreamJSON{Host: u.Host, Port: u.Port, Weight: u.Weight})
+	}
+	return out
+}
+
+func (r routeJSON) toRoute() domain.Route {
+	out := domain.Route{
+		ID:          r.ID,
+		Domain:      r.Domain,
+		Aliases:     r.Aliases,
+		PathPrefix:  r.PathPrefix,
+		StripPrefix: r.StripPrefix,
+		Policy:      r.Policy,
+	}
+	for _, u := range r.Upstreams {
+		out.Upstreams = append(out.Upstreams, domain.Upstream{Host: u.Host, Port: u.Port, Weight: u.Weight})
+	}
+	return out
+}
@@ -0,0 +1,178 @@
+package oiproxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// adminBaseURL é a URL base das requisições à admin API (o host é ignorado no socket unix)
+const adminBaseURL = "http://oi-proxy"
+
+// Manager implementa port.ProxyManager falando com o "oi proxy serve" pela admin API
+type Manager struct {
+	socket     string
+	httpClient *http.Client
+}
+
+// NewManager cria uma nova instância do Manager do proxy embutido
+// socket vazio usa DefaultSocket
+func NewManager(socket string) *Manager {
+	if socket == "" {
+		socket = DefaultSocket
+	}
+	return &Manager{
+		socket: socket,
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, "unix", socket)
+				},
+			},
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Socket retorna o caminho do socket da admin API
+func (m *Manager) Socket() string {
+	return m.socket
+}
+
+// AddRoute adiciona ou substitui a rota; o proxy troca os upstreams de uma vez
+func (m *Manager) AddRoute(ctx context.Context, r domain.Route) error {
+	body, err := json.Marshal(toJSON(r))
+	if err != nil {
+		return fmt.Errorf("falha ao serializar rota: %w", err)
+	}
+	if err := m.do(ctx, http.MethodPut, pathRoutes, body, nil); err != nil {
+		return fmt.Errorf("falha ao adicionar rota: %w", err)
+	}
+	return nil
+}
+
+// RemoveRoute remove a rota pelo ID e qualquer rota para o endereço principal dela
+func (m *Manager) RemoveRoute(ctx context.Context, r domain.Route) error {
+	q := url.Values{}
+	if r.ID != "" {
+		q.Set("id", r.ID)
+	}
+	if r.Domain != "" {
+		q.Set("domain", r.Site())
+	}
+	if len(q) == 0 {
+		return nil
+	}
+	if err := m.do(ctx, http.MethodDelete, pathRoutes+"?"+q.Encode(), nil, nil); err != nil {
+		return fmt.Errorf("falha ao remover rota: %w", err)
+	}
+	return nil
+}
+
+// Routes retorna as rotas carregadas no proxy
+func (m *Manager) Routes(ctx context.Context) ([]domain.Route, error) {
+	var routes []routeJSON
+	if err := m.do(ctx, http.MethodGet, pathRoutes, nil, &routes); err != nil {
+		return nil, fmt.Errorf("falha ao listar rotas: %w", err)
+	}
+	result := make([]domain.Route, 0, len(routes))
+	for _, r := range routes {
+		result = append(result, r.toRoute())
+	}
+	return result, nil
+}
+
+// HasRoute verifica se uma rota existe
+func (m *Manager) HasRoute(ctx context.Context, site string) (bool, error) {
+	upstreams, err := m.GetUpstreams(ctx, site)
+	if err != nil {
+		return false, err
+	}
+	return len(upstreams) > 0, nil
+}
+
+// GetUpstreams retorna os upstreams (host:porta) da rota do endereço ("host[/prefixo]")
+func (m *Manager) GetUpstreams(ctx context.Context, site string) ([]string, error) {
+	target, err := domain.ParseSite(site)
+	if err != nil {
+		return nil, err
+	}
+	routes, err := m.Routes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range routes {
+		if r.Serves(target) {
+			return r.Addresses(), nil
+		}
+	}
+	return nil, nil
+}
+
+// Reload não faz nada: as rotas valem assim que a admin API responde
+func (m *Manager) Reload(ctx context.Context) error {
+	return nil
+}
+
+// Health verifica se o "oi proxy serve" está rodando
+func (m *Manager) Health(ctx context.Context) error {
+	var health healthJSON
+	if err := m.do(ctx, http.MethodGet, pathHealth, nil, &health); err != nil {
+		return fmt.Errorf("proxy embutido não acessível em %s (rode 'oi proxy serve'): %w", m.socket, err)
+	}
+	return nil
+}
+
+// Status retorna o endereço de escuta e o número de rotas do proxy
+func (m *Manager) Status(ctx context.Context) (listen string, routes int, err error) {
+	var health healthJSON
+	if err := m.do(ctx, http.MethodGet, pathHealth, nil, &health); err != nil {
+		return "", 0, err
+	}
+	return health.Listen, health.Routes, nil
+}
+
+// do executa a requisição na admin API e decodifica a resposta em out (se não for nil)
+func (m *Manager) do(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, adminBaseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("falha ao criar request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var apiErr errorJSON
+		data, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error != "" {
+			return fmt.Errorf("%s (status %d)", apiErr.Error, resp.StatusCode)
+		}
+		return fmt.Errorf("status %d: %s", resp.StatusCode, bytes.TrimSpace(data))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("resposta inválida: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,361 @@
+// Package oiproxy é o proxy reverso embutido no OI ("oi proxy serve")
+// O daemon roteia por domínio (Host) com net/http/httputil.ReverseProxy e recebe a
+// tabela de rotas por uma admin API local, num socket unix; Manager é o lado cliente,
+// usado pelo orquestrador como port.ProxyManager
+package oiproxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/crom-tech/oi/internal/atomicfile"
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// Resolver traduz o endereço host:porta de um upstream (ex: "oi-loja-abc123-1:80") para
+// um endereço alcançável pelo proxy, como o IP do container ou a porta publicada no host;
+// erro ou endereço vazio fazem o proxy usar o endereço como está
+type Resolver func(ctx context.Context, addr string) (string, error)
+
+// backendKey guarda na requisição o backend escolhido para ela
+type backendKey struct{}
+
+// entryKey guarda na requisição a rota que a atendeu
+type entryKey struct{}
+
+// Server é o daemon do proxy embutido
+type Server struct {
+	table     *table
+	statePath string
+	resolver  Resolver
+	proxy     *httputil.ReverseProxy
+	dialer    net.Dialer
+	listen    string
+
+	// saveMu serializa as alterações da tabela com a gravação do estado
+	saveMu sync.Mutex
+}
+
+// NewServer cria o daemon com a tabela vazia
+// statePath é o arquivo onde a tabela é persistida entre reinícios (vazio: só em memória);
+// resolver é opcional
+func NewServer(statePath string, resolver Resolver) *Server {
+	s := &Server{
+		table:     newTable(),
+		statePath: statePath,
+		resolver:  resolver,
+		dialer:    net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second},
+	}
+	s.proxy = &httputil.ReverseProxy{
+		Rewrite: s.rewrite,
+		Transport: &http.Transport{
+			DialContext:           s.dial,
+			MaxIdleConns:          100,
+			MaxIdleConnsPerHost:   32,
+			IdleConnTimeout:       90 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			if b, ok := r.Context().Value(backendKey{}).(*backend); ok {
+				fmt.Printf("⚠️  [%s] %s %s → %s: %v\n", time.Now().Format("15:04:05"), r.Host, r.URL.Path, b.addr, err)
+			}
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	}
+	return s
+}
+
+// Routes retorna as rotas carregadas, ordenadas por ID
+func (s *Server) Routes() []domain.Route {
+	return s.table.routes()
+}
+
+// ServeHTTP encaminha a requisição para um upstream da rota do Host e caminho
+// Upgrades (websockets) são repassados pelo ReverseProxy, que mantém a conexão aberta
+// nos dois sentidos; a troca da rota não derruba conexões já estabelecidas
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e := s.table.lookup(r.Host, r.URL.Path)
+	if e == nil {
+		http.Error(w, fmt.Sprintf("nenhuma rota para %s%s", normalizeHost(r.Host), r.URL.Path), http.StatusNotFound)
+		return
+	}
+	b := e.pick(r)
+	if b == nil {
+		http.Error(w, "rota sem upstreams", http.StatusBadGateway)
+		return
+	}
+
+	b.active.Add(1)
+	defer b.active.Add(-1)
+	ctx := context.WithValue(r.Context(), backendKey{}, b)
+	s.proxy.ServeHTTP(w, r.WithContext(context.WithValue(ctx, entryKey{}, e)))
+}
+
+// rewrite aponta a requisição para o backend escolhido, preservando o Host original
+// Rotas com StripPrefix encaminham o caminho sem o prefixo ("/v2/pedidos" -> "/pedidos")
+func (s *Server) rewrite(pr *httputil.ProxyRequest) {
+	b := pr.In.Context().Value(backendKey{}).(*backend)
+	if e, ok := pr.In.Context().Value(entryKey{}).(*entry); ok && e.route.StripPrefix {
+		prefix := e.route.PathPrefix
+		pr.Out.URL.Path = domain.StripPath(prefix, pr.Out.URL.Path)
+		if pr.Out.URL.RawPath != "" {
+			pr.Out.URL.RawPath = domain.StripPath(prefix, pr.Out.URL.RawPath)
+		}
+	}
+	pr.SetURL(&url.URL{Scheme: "http", Host: b.addr})
+	pr.Out.Host = pr.In.Host
+	pr.SetXForwarded()
+}
+
+// dial conecta no upstream, traduzindo o nome pelo resolver quando houver
+// A tradução fica guardada no backend da requisição, então o runtime só é consultado na
+// primeira conexão de cada upstream da rota; se a conexão no endereço guardado falhar
+// (ex: container recriado com outro IP), ele é traduzido de novo
+func (s *Server) dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	if s.resolver == nil {
+		return s.dialer.DialContext(ctx, network, addr)
+	}
+	if host, _, err := net.SplitHostPort(addr); err != nil || net.ParseIP(host) != nil {
+		return s.dialer.DialContext(ctx, network, addr)
+	}
+
+	b, _ := ctx.Value(backendKey{}).(*backend)
+	if b != nil && b.addr != addr {
+		b = nil
+	}
+	if b != nil {
+		if cached := b.dialAddr.Load(); cached != nil {
+			conn, err := s.dialer.DialContext(ctx, network, *cached)
+			if err == nil {
+				return conn, nil
+			}
+			b.dialAddr.CompareAndSwap(cached, nil)
+		}
+	}
+
+	if resolved, err := s.resolver(ctx, addr); err == nil && resolved != "" {
+		if b != nil {
+			b.dialAddr.Store(&resolved)
+		}
+		addr = resolved
+	}
+	return s.dialer.DialContext(ctx, network, addr)
+}
+
+// AdminHandler retorna a admin API (ver os endpoints em api.go)
+func (s *Server) AdminHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(pathHealth, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "método não permitido")
+			return
+		}
+		writeJSON(w, http.StatusOK, healthJSON{Status: "ok", Routes: len(s.table.routes()), Listen: s.listen})
+	})
+	mux.HandleFunc(pathRoutes, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			routes := []routeJSON{}
+			for _, route := range s.table.routes() {
+				routes = append(routes, toJSON(route))
+			}
+			writeJSON(w, http.StatusOK, routes)
+		case http.MethodPut:
+			var body routeJSON
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("corpo inválido: %v", err))
+				return
+			}
+			if err := s.PutRoute(body.toRoute()); err != nil {
+				status := http.StatusInternalServerError
+				if errors.Is(err, errInvalidRoute) {
+					status = http.StatusBadRequest
+				}
+				writeError(w, status, err.Error())
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			q := r.URL.Query()
+			if q.Get("id") == "" && q.Get("domain") == "" {
+				writeError(w, http.StatusBadRequest, "informe id ou domain")
+				return
+			}
+			if err := s.DeleteRoute(q.Get("id"), q.Get("domain")); err != nil {
+				writeError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "método não permitido")
+		}
+	})
+	return mux
+}
+
+// errInvalidRoute marca erros de validação da rota recebida
+var errInvalidRoute = errors.New("rota inválida")
+
+// PutRoute adiciona ou substitui a rota e persiste a tabela
+// A troca dos upstreams é atômica: cada requisição vê a rota antiga ou a nova inteira
+// Se o estado não puder ser gravado, a rota anterior é restaurada
+func (s *Server) PutRoute(r domain.Route) error {
+	if err := validateRoute(r); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidRoute, err)
+	}
+
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+	previous := s.table.routes()
+	s.table.put(r)
+	if err := s.save(); err != nil {
+		s.table.reset(previous)
+		return err
+	}
+	fmt.Printf("🔀 [%s] %s → %v\n", time.Now().Format("15:04:05"), r.Label(), r.Addresses())
+	return nil
+}
+
+// DeleteRoute remove a rota pelo ID e qualquer rota para o endereço, e persiste a tabela
+func (s *Server) DeleteRoute(id string, site string) error {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+	previous := s.table.routes()
+	if !s.table.remove(id, site) {
+		return nil
+	}
+	if err := s.save(); err != nil {
+		s.table.reset(previous)
+		return err
+	}
+	fmt.Printf("🗑️  [%s] rota removida: %s %s\n", time.Now().Format("15:04:05"), id, site)
+	return nil
+}
+
+// Load carrega a tabela persistida (sem arquivo, começa vazia)
+func (s *Server) Load() error {
+	if s.statePath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("falha ao ler %s: %w", s.statePath, err)
+	}
+	var routes []routeJSON
+	if err := json.Unmarshal(data, &routes); err != nil {
+		return fmt.Errorf("falha ao parsear %s: %w", s.statePath, err)
+	}
+
+	loaded := make([]domain.Route, 0, len(routes))
+	for _, r := range routes {
+		route := r.toRoute()
+		if err := validateRoute(route); err != nil {
+			return fmt.Errorf("rota inválida em %s: %w", s.statePath, err)
+		}
+		loaded = append(loaded, route)
+	}
+	s.table.reset(loaded)
+	return nil
+}
+
+// save grava a tabela no arquivo de estado (de forma atômica); s.saveMu deve estar travado
+func (s *Server) save() error {
+	if s.statePath == "" {
+		return nil
+	}
+	routes := []routeJSON{}
+	for _, r := range s.table.routes() {
+		routes = append(routes, toJSON(r))
+	}
+	data, err := json.MarshalIndent(routes, "", "  ")
+	if err != nil {
+		return fmt.Errorf("falha ao serializar rotas: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.statePath), 0755); err != nil {
+		return fmt.Errorf("falha ao criar diretório do estado: %w", err)
+	}
+	if err := atomicfile.Write(s.statePath, data, 0600); err != nil {
+		return fmt.Errorf("falha ao gravar estado: %w", err)
+	}
+	return nil
+}
+
+// Serve escuta o tráfego em listen (ex: ":80") e a admin API no socket unix,
+// até ctx ser cancelado
+// O socket é criado com permissão 0660: quem pode escrever nele controla as rotas
+func (s *Server) Serve(ctx context.Context, listen string, socket string) error {
+	s.listen = listen
+
+	if err := os.MkdirAll(filepath.Dir(socket), 0755); err != nil {
+		return fmt.Errorf("falha ao criar diretório do socket: %w", err)
+	}
+	if conn, err := net.Dial("unix", socket); err == nil {
+		conn.Close()
+		return fmt.Errorf("já existe um proxy rodando em %s", socket)
+	}
+	// Socket de uma execução anterior que não terminou limpa
+	os.Remove(socket)
+
+	adminLn, err := net.Listen("unix", socket)
+	if err != nil {
+		return fmt.Errorf("falha ao abrir socket %s: %w", socket, err)
+	}
+	defer os.Remove(socket)
+	if err := os.Chmod(socket, 0660); err != nil {
+		adminLn.Close()
+		return fmt.Errorf("falha ao ajustar permissão do socket: %w", err)
+	}
+
+	proxyLn, err := net.Listen("tcp", listen)
+	if err != nil {
+		adminLn.Close()
+		return fmt.Errorf("falha ao escutar em %s: %w", listen, err)
+	}
+
+	proxySrv := &http.Server{Handler: s, ReadHeaderTimeout: 10 * time.Second}
+	adminSrv := &http.Server{Handler: s.AdminHandler(), ReadHeaderTimeout: 10 * time.Second}
+
+	errc := make(chan error, 2)
+	go func() { errc <- proxySrv.Serve(proxyLn) }()
+	go func() { errc <- adminSrv.Serve(adminLn) }()
+
+	var serveErr error
+	select {
+	case <-ctx.Done():
+	case serveErr = <-errc:
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	adminSrv.Shutdown(shutdownCtx)
+	proxySrv.Shutdown(shutdownCtx)
+
+	if serveErr != nil && !errors.Is(serveErr, http.ErrServerClosed) {
+		return fmt.Errorf("proxy encerrado com erro: %w", serveErr)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errorJSON{Error: msg})
+}
@@ -0,0 +1,380 @@
+package oiproxy
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// backendServer sobe um upstream que responde com o próprio nome
+func backendServer(t *testing.T, name string) domain.Upstream {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s %s", name, r.Host, r.Header.Get("X-Forwarded-Host"))
+	}))
+	t.Cleanup(srv.Close)
+	return upstreamOf(t, srv.Listener.Addr().String())
+}
+
+func upstreamOf(t *testing.T, addr string) domain.Upstream {
+	t.Helper()
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := strconv.Atoi(port)
+	return domain.Upstream{Host: host, Port: p}
+}
+
+// get faz uma requisição ao proxy com o Host informado e retorna status e corpo
+func get(t *testing.T, proxyURL string, host string) (int, string) {
+	t.Helper()
+	return getPath(t, proxyURL, host, "/")
+}
+
+// getPath é o get para um caminho específico
+func getPath(t *testing.T, proxyURL string, host string, path string) (int, string) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, proxyURL+path, nil)
+	req.Host = host
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("requisição ao proxy: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+// startDaemon roda Serve num socket temporário e retorna o Manager conectado a ele
+func startDaemon(t *testing.T, s *Server) *Manager {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "proxy.sock")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ctx, "127.0.0.1:0", socket) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Serve: %v", err)
+		}
+	})
+
+	m := NewManager(socket)
+	deadline := time.Now().Add(5 * time.Second)
+	for m.Health(context.Background()) != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("proxy não ficou pronto")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return m
+}
+
+func TestBlueGreenSwap(t *testing.T) {
+	s := NewServer(filepath.Join(t.TempDir(), "routes.json"), nil)
+	m := startDaemon(t, s)
+	proxy := httptest.NewServer(s)
+	defer proxy.Close()
+	ctx := context.Background()
+
+	blue := backendServer(t, "blue")
+	green := backendServer(t, "green")
+
+	if err := m.AddRoute(ctx, domain.Route{ID: "oi-loja", Domain: "loja.com", Upstreams: []domain.Upstream{blue}}); err != nil {
+		t.Fatalf("AddRoute: %v", err)
+	}
+	if status, body := get(t, proxy.URL, "loja.com"); status != http.StatusOK || !strings.HasPrefix(body, "blue loja.com loja.com") {
+		t.Fatalf("resposta inesperada: %d %q", status, body)
+	}
+
+	if err := m.AddRoute(ctx, domain.Route{ID: "oi-loja", Domain: "loja.com", Upstreams: []domain.Upstream{green}}); err != nil {
+		t.Fatalf("AddRoute: %v", err)
+	}
+	if _, body := get(t, proxy.URL, "LOJA.com:8080"); !strings.HasPrefix(body, "green") {
+		t.Errorf("tráfego não foi para a nova versão: %q", body)
+	}
+
+	upstreams, err := m.GetUpstreams(ctx, "loja.com")
+	if err != nil {
+		t.Fatalf("GetUpstreams: %v", err)
+	}
+	if !reflect.DeepEqual(upstreams, []string{green.Address()}) {
+		t.Errorf("upstreams = %v; esperado %v", upstreams, []string{green.Address()})
+	}
+
+	if status, _ := get(t, proxy.URL, "outro.com"); status != http.StatusNotFound {
+		t.Errorf("domínio sem rota: status %d, esperado 404", status)
+	}
+
+	if err := m.RemoveRoute(ctx, domain.Route{ID: "oi-loja", Domain: "loja.com"}); err != nil {
+		t.Fatalf("RemoveRoute: %v", err)
+	}
+	if ok, err := m.HasRoute(ctx, "loja.com"); err != nil || ok {
+		t.Errorf("HasRoute = %v, %v; esperado false", ok, err)
+	}
+	if status, _ := get(t, proxy.URL, "loja.com"); status != http.StatusNotFound {
+		t.Errorf("rota removida: status %d, esperado 404", status)
+	}
+}
+
+func TestAddRouteReplacesOtherIDForDomain(t *testing.T) {
+	s := NewServer("", nil)
+	a, b := backendServer(t, "a"), backendServer(t, "b")
+
+	if err := s.PutRoute(domain.Route{ID: "oi-antigo", Domain: "loja.com", Upstreams: []domain.Upstream{a}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.PutRoute(domain.Route{ID: "oi-loja", Domain: "loja.com", Upstreams: []domain.Upstream{b}}); err != nil {
+		t.Fatal(err)
+	}
+	routes := s.Routes()
+	if len(routes) != 1 || routes[0].ID != "oi-loja" {
+		t.Errorf("rotas = %+v; esperada só oi-loja", routes)
+	}
+}
+
+func TestPathPrefixAndWildcard(t *testing.T) {
+	s := NewServer("", nil)
+	proxy := httptest.NewServer(s)
+	defer proxy.Close()
+
+	// pathServer responde com o nome e o caminho recebido pelo upstream
+	pathServer := func(name string) domain.Upstream {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "%s %s", name, r.URL.Path)
+		}))
+		t.Cleanup(srv.Close)
+		return upstreamOf(t, srv.Listener.Addr().String())
+	}
+	site, api, tenants := pathServer("site"), pathServer("api"), pathServer("tenants")
+
+	for _, r := range []domain.Route{
+		{ID: "oi-loja", Domain: "loja.com", Aliases: []string{"www.loja.com"}, Upstreams: []domain.Upstream{site}},
+		{ID: "oi-api", Domain: "loja.com", PathPrefix: "/v2", StripPrefix: true, Upstreams: []domain.Upstream{api}},
+		{ID: "oi-tenants", Domain: "*.loja.com", Upstreams: []domain.Upstream{tenants}},
+	} {
+		if err := s.PutRoute(r); err != nil {
+			t.Fatalf("PutRoute %s: %v", r.ID, err)
+		}
+	}
+
+	tests := []struct {
+		host, path string
+		want       string
+	}{
+		{host: "loja.com", path: "/pedidos", want: "site /pedidos"},
+		{host: "loja.com", path: "/v2beta", want: "site /v2beta"},
+		{host: "loja.com", path: "/v2", want: "api /"},
+		{host: "loja.com", path: "/v2/pedidos", want: "api /pedidos"},
+		{host: "www.loja.com", path: "/", want: "site /"},
+		{host: "cliente.loja.com", path: "/v2", want: "tenants /v2"},
+	}
+	for _, tt := range tests {
+		if status, body := getPath(t, proxy.URL, tt.host, tt.path); status != http.StatusOK || body != tt.want {
+			t.Errorf("%s%s: %d %q; esperado %q", tt.host, tt.path, status, body, tt.want)
+		}
+	}
+	if status, _ := getPath(t, proxy.URL, "a.b.loja.com", "/"); status != http.StatusNotFound {
+		t.Errorf("curinga de um nível só: status %d, esperado 404", status)
+	}
+
+	// Remover pelo endereço do prefixo não afeta a rota do host inteiro
+	if err := s.DeleteRoute("", "loja.com/v2"); err != nil {
+		t.Fatalf("DeleteRoute: %v", err)
+	}
+	if _, body := getPath(t, proxy.URL, "loja.com", "/v2/pedidos"); body != "site /v2/pedidos" {
+		t.Errorf("resposta após remover o prefixo: %q", body)
+	}
+}
+
+func TestBalancing(t *testing.T) {
+	a, b := backendServer(t, "a"), backendServer(t, "b")
+
+	tests := []struct {
+		name   string
+		route  domain.Route
+		wantA  int
+		wantB  int
+		sticky bool
+	}{
+		{name: "round robin", route: domain.Route{Upstreams: []domain.Upstream{a, b}}, wantA: 10, wantB: 10},
+		{name: "first", route: domain.Route{Policy: domain.PolicyFirst, Upstreams: []domain.Upstream{a, b}}, wantA: 20},
+		{name: "weighted", route: domain.Route{Policy: domain.PolicyWeightedRoundRobin, Upstreams: []domain.Upstream{
+			{Host: a.Host, Port: a.Port, Weight: 90}, {Host: b.Host, Port: b.Port, Weight: 10},
+		}}, wantA: 18, wantB: 2},
+		{name: "ip hash", route: domain.Route{Policy: domain.PolicyIPHash, Upstreams: []domain.Upstream{a, b}}, sticky: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer("", nil)
+			tt.route.ID, tt.route.Domain = "oi-loja", "loja.com"
+			if err := s.PutRoute(tt.route); err != nil {
+				t.Fatal(err)
+			}
+			proxy := httptest.NewServer(s)
+			defer proxy.Close()
+
+			counts := map[string]int{}
+			for i := 0; i < 20; i++ {
+				_, body := get(t, proxy.URL, "loja.com")
+				counts[strings.Fields(body)[0]]++
+			}
+			if tt.sticky {
+				if len(counts) != 1 {
+					t.Errorf("ip_hash deveria manter o cliente no mesmo upstream: %v", counts)
+				}
+				return
+			}
+			if counts["a"] != tt.wantA || counts["b"] != tt.wantB {
+				t.Errorf("distribuição = %v; esperado a=%d b=%d", counts, tt.wantA, tt.wantB)
+			}
+		})
+	}
+}
+
+func TestWebsocketUpgrade(t *testing.T) {
+	// Upstream que aceita o upgrade e ecoa o que receber
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "websocket" {
+			http.Error(w, "upgrade esperado", http.StatusBadRequest)
+			return
+		}
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+		rw.Flush()
+		io.Copy(conn, rw)
+	}))
+	defer backend.Close()
+
+	s := NewServer("", nil)
+	if err := s.PutRoute(domain.Route{ID: "oi-chat", Domain: "chat.com", Upstreams: []domain.Upstream{upstreamOf(t, backend.Listener.Addr().String())}}); err != nil {
+		t.Fatal(err)
+	}
+	proxy := httptest.NewServer(s)
+	defer proxy.Close()
+
+	conn, err := net.Dial("tcp", proxy.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	fmt.Fprintf(conn, "GET /ws HTTP/1.1\r\nHost: chat.com\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("resposta do upgrade: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("status = %d; esperado 101", resp.StatusCode)
+	}
+
+	fmt.Fprint(conn, "ping\n")
+	line, err := reader.ReadString('\n')
+	if err != nil || line != "ping\n" {
+		t.Errorf("eco = %q, %v; esperado \"ping\"", line, err)
+	}
+}
+
+func TestStatePersistence(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state", "routes.json")
+	route := domain.Route{ID: "oi-loja", Domain: "loja.com", Policy: domain.PolicyLeastConn, Upstreams: []domain.Upstream{{Host: "oi-loja-1", Port: 80}}}
+
+	s := NewServer(statePath, nil)
+	if err := s.PutRoute(route); err != nil {
+		t.Fatalf("PutRoute: %v", err)
+	}
+
+	restarted := NewServer(statePath, nil)
+	if err := restarted.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if routes := restarted.Routes(); !reflect.DeepEqual(routes, []domain.Route{route}) {
+		t.Errorf("rotas após reinício = %+v; esperado %+v", routes, route)
+	}
+
+	if err := restarted.DeleteRoute("oi-loja", ""); err != nil {
+		t.Fatalf("DeleteRoute: %v", err)
+	}
+	empty := NewServer(statePath, nil)
+	if err := empty.Load(); err != nil || len(empty.Routes()) != 0 {
+		t.Errorf("rotas após remoção = %+v, %v", empty.Routes(), err)
+	}
+}
+
+func TestResolver(t *testing.T) {
+	backend := backendServer(t, "app")
+	var asked []string
+	s := NewServer("", func(ctx context.Context, addr string) (string, error) {
+		asked = append(asked, addr)
+		return backend.Address(), nil
+	})
+	if err := s.PutRoute(domain.Route{ID: "oi-app", Domain: "app.com", Upstreams: []domain.Upstream{{Host: "oi-app-abc123-1", Port: 80}}}); err != nil {
+		t.Fatal(err)
+	}
+	proxy := httptest.NewServer(s)
+	defer proxy.Close()
+
+	if status, body := get(t, proxy.URL, "app.com"); status != http.StatusOK || !strings.HasPrefix(body, "app") {
+		t.Errorf("resposta = %d %q", status, body)
+	}
+	if len(asked) == 0 || asked[0] != "oi-app-abc123-1:80" {
+		t.Errorf("resolver consultado com %v", asked)
+	}
+
+	// Novas conexões na mesma rota usam o endereço já traduzido
+	transport := s.proxy.Transport.(*http.Transport)
+	transport.CloseIdleConnections()
+	if status, _ := get(t, proxy.URL, "app.com"); status != http.StatusOK || len(asked) != 1 {
+		t.Errorf("resposta = %d, resolver consultado %d vezes; esperado 1", status, len(asked))
+	}
+
+	// PutRoute descarta a tradução guardada
+	if err := s.PutRoute(domain.Route{ID: "oi-app", Domain: "app.com", Upstreams: []domain.Upstream{{Host: "oi-app-abc123-1", Port: 80}}}); err != nil {
+		t.Fatal(err)
+	}
+	transport.CloseIdleConnections()
+	if status, _ := get(t, proxy.URL, "app.com"); status != http.StatusOK || len(asked) != 2 {
+		t.Errorf("resposta = %d, resolver consultado %d vezes após PutRoute; esperado 2", status, len(asked))
+	}
+}
+
+func TestAdminAPIErrors(t *testing.T) {
+	m := startDaemon(t, NewServer("", nil))
+	ctx := context.Background()
+
+	err := m.AddRoute(ctx, domain.Route{ID: "oi-loja", Domain: "loja.com"})
+	if err == nil || !strings.Contains(err.Error(), "sem upstreams") || !strings.Contains(err.Error(), "400") {
+		t.Errorf("AddRoute sem upstreams = %v; esperado erro 400", err)
+	}
+
+	if err := NewManager(filepath.Join(t.TempDir(), "nada.sock")).Health(ctx); err == nil {
+		t.Error("esperado erro sem o daemon rodando")
+	}
+}
+
+func TestServeRefusesSecondDaemon(t *testing.T) {
+	m := startDaemon(t, NewServer("", nil))
+	err := NewServer("", nil).Serve(context.Background(), "127.0.0.1:0", m.Socket())
+	if err == nil || !strings.Contains(err.Error(), "já existe") {
+		t.Errorf("Serve no mesmo socket = %v; esperado erro", err)
+	}
+}
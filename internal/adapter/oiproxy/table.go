@@ -0,0 +1,297 @@
+package oiproxy
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// backend é um upstream de uma rota, com o número de requisições em andamento
+type backend struct {
+	addr   string
+	weight int
+	active atomic.Int64
+	// dialAddr é addr traduzido pelo Resolver na primeira conexão; vale enquanto a entry
+	// existir (PutRoute cria backends novos) ou até uma conexão nele falhar
+	dialAddr atomic.Pointer[string]
+}
+
+// entry é uma rota carregada na tabela
+// A rota não muda depois de criada: a troca de upstreams (Blue-Green) cria uma nova entry,
+// e as requisições em andamento terminam nos backends da entry antiga
+type entry struct {
+	route    domain.Route
+	backends []*backend
+	// schedule é a sequência de índices em backends da política weighted_round_robin
+	schedule []int
+	next     atomic.Uint64
+}
+
+func newEntry(r domain.Route) *entry {
+	r.Upstreams = append([]domain.Upstream(nil), r.Upstreams...)
+	e := &entry{route: r}
+	for _, u := range r.Upstreams {
+		e.backends = append(e.backends, &backend{addr: u.Address(), weight: u.Weight})
+	}
+	if r.PolicyOrDefault() == domain.PolicyWeightedRoundRobin && len(e.backends) > 0 {
+		e.schedule = weightedSchedule(e.backends)
+	}
+	return e
+}
+
+// pick escolhe o backend da requisição conforme a política da rota
+func (e *entry) pick(req *http.Request) *backend {
+	switch len(e.backends) {
+	case 0:
+		return nil
+	case 1:
+		return e.backends[0]
+	}
+
+	switch e.route.PolicyOrDefault() {
+	case domain.PolicyFirst:
+		return e.backends[0]
+	case domain.PolicyRandom:
+		return e.backends[rand.Intn(len(e.backends))]
+	case domain.PolicyIPHash:
+		h := fnv.New32a()
+		h.Write([]byte(clientIP(req)))
+		return e.backends[int(h.Sum32()%uint32(len(e.backends)))]
+	case domain.PolicyLeastConn:
+		best := e.backends[0]
+		for _, b := range e.backends[1:] {
+			if b.active.Load() < best.active.Load() {
+				best = b
+			}
+		}
+		return best
+	case domain.PolicyWeightedRoundRobin:
+		return e.pickWeighted()
+	default:
+		// round_robin (e políticas desconhecidas)
+		return e.backends[int((e.next.Add(1)-1)%uint64(len(e.backends)))]
+	}
+}
+
+// pickWeighted distribui as requisições em proporção aos pesos (ex: 90/10 no canário)
+// Segue a sequência pré-calculada em rodízio, sem sortear: com pesos 9 e 1, a cada 10
+// requisições exatamente 1 vai para o segundo backend
+func (e *entry) pickWeighted() *backend {
+	return e.backends[e.schedule[int((e.next.Add(1)-1)%uint64(len(e.schedule)))]]
+}
+
+// weightedSchedule monta a sequência de backends do weighted round robin suave (como o
+// do nginx): os backends se intercalam em vez de receber os pesos em blocos seguidos
+func weightedSchedule(backends []*backend) []int {
+	divisor := 0
+	for _, b := range backends {
+		divisor = gcd(divisor, weightOf(b))
+	}
+
+	total := 0
+	weights := make([]int, len(backends))
+	for i, b := range backends {
+		weights[i] = weightOf(b) / divisor
+		total += weights[i]
+	}
+
+	current := make([]int, len(backends))
+	schedule := make([]int, 0, total)
+	for len(schedule) < total {
+		best := 0
+		for i := range backends {
+			current[i] += weights[i]
+			if current[i] > current[best] {
+				best = i
+			}
+		}
+		current[best] -= total
+		schedule = append(schedule, best)
+	}
+	return schedule
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// weightOf trata upstreams sem peso como peso 1
+func weightOf(b *backend) int {
+	if b.weight <= 0 {
+		return 1
+	}
+	return b.weight
+}
+
+// clientIP retorna o IP de origem da conexão (sem a porta)
+func clientIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// table é a tabela de roteamento por endereço (host e prefixo de caminho)
+type table struct {
+	mu   sync.RWMutex
+	byID map[string]*entry
+	// byHost indexa as rotas por host (exato ou curinga); um host pode ter várias rotas,
+	// uma por prefixo de caminho. É reconstruído a cada mudança em byID
+	byHost map[string][]*entry
+}
+
+func newTable() *table {
+	return &table{byID: make(map[string]*entry), byHost: make(map[string][]*entry)}
+}
+
+// normalizeHost remove a porta e o ponto final do Host e passa para minúsculas
+func normalizeHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
+
+// validateRoute verifica se a rota pode entrar na tabela
+func validateRoute(r domain.Route) error {
+	if r.ID == "" {
+		return fmt.Errorf("rota para %s sem ID", r.Domain)
+	}
+	if normalizeHost(r.Domain) == "" {
+		return fmt.Errorf("rota %s sem domínio", r.ID)
+	}
+	for _, s := range r.Sites() {
+		if _, err := domain.ParseSite(s.String()); err != nil {
+			return fmt.Errorf("rota %s: %v", r.ID, err)
+		}
+	}
+	if len(r.Upstreams) == 0 {
+		return fmt.Errorf("rota %s sem upstreams", r.ID)
+	}
+	for _, u := range r.Upstreams {
+		if u.Host == "" || u.Port <= 0 || u.Port > 65535 {
+			return fmt.Errorf("upstream inválido na rota %s: %s", r.ID, u.Address())
+		}
+	}
+	return nil
+}
+
+// lookup retorna a rota da requisição pelo Host e caminho
+// Entre as rotas que atendem, vence a de prefixo mais longo e, no mesmo prefixo, a de
+// host exato sobre a curinga (mesma precedência dos outros proxies)
+func (t *table) lookup(host, path string) *entry {
+	host = normalizeHost(host)
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var best *entry
+	bestPrecedence := -1
+	consider := func(entries []*entry, exact bool) {
+		for _, e := range entries {
+			if !domain.MatchPath(e.route.PathPrefix, path) {
+				continue
+			}
+			precedence := 2 * len(e.route.PathPrefix)
+			if exact {
+				precedence++
+			}
+			if precedence > bestPrecedence {
+				best, bestPrecedence = e, precedence
+			}
+		}
+	}
+	consider(t.byHost[host], true)
+	if _, parent, ok := strings.Cut(host, "."); ok && parent != "" {
+		consider(t.byHost["*."+parent], false)
+	}
+	return best
+}
+
+// put adiciona ou substitui a rota com o mesmo ID numa única troca
+// Outras rotas para os mesmos endereços são removidas (cada endereço só tem um destino)
+func (t *table) put(r domain.Route) {
+	e := newEntry(r)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id, old := range t.byID {
+		if id == r.ID || old.route.Overlaps(r) {
+			delete(t.byID, id)
+		}
+	}
+	t.byID[r.ID] = e
+	t.reindex()
+}
+
+// remove remove a rota pelo ID e qualquer rota para o endereço ("host[/prefixo]")
+// Retorna se algo foi removido
+func (t *table) remove(id string, site string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	removed := false
+	if _, ok := t.byID[id]; ok {
+		delete(t.byID, id)
+		removed = true
+	}
+	if target, err := domain.ParseSite(site); err == nil {
+		for other, e := range t.byID {
+			if e.route.Serves(target) {
+				delete(t.byID, other)
+				removed = true
+			}
+		}
+	}
+	if removed {
+		t.reindex()
+	}
+	return removed
+}
+
+// reset substitui a tabela inteira pelas rotas informadas
+func (t *table) reset(routes []domain.Route) {
+	byID := make(map[string]*entry, len(routes))
+	for _, r := range routes {
+		byID[r.ID] = newEntry(r)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.byID = byID
+	t.reindex()
+}
+
+// reindex reconstrói byHost a partir de byID; deve ser chamado com t.mu travado
+func (t *table) reindex() {
+	byHost := make(map[string][]*entry, len(t.byID))
+	for _, e := range t.byID {
+		for _, h := range e.route.Hosts() {
+			host := normalizeHost(h)
+			byHost[host] = append(byHost[host], e)
+		}
+	}
+	t.byHost = byHost
+}
+
+// routes retorna as rotas ordenadas por ID
+func (t *table) routes() []domain.Route {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	routes := make([]domain.Route, 0, len(t.byID))
+	for _, e := range t.byID {
+		routes = append(routes, e.route)
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].ID < routes[j].ID })
+	return routes
+}
@@ -0,0 +1,215 @@
+package podman
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crom-tech/oi/internal/adapter/docker"
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// EnvContainerHost é a variável usada pelo próprio Podman para indicar o socket
+const EnvContainerHost = "CONTAINER_HOST"
+
+// Client implementa port.ContainerRuntime sobre a API REST do Podman
+// As operações usam a API compatível com Docker servida pelo mesmo socket (containers,
+// imagens, networks, volumes e logs); a API libpod cobre o que o Podman faz diferente
+type Client struct {
+	*docker.Client
+	// socket é o caminho do socket unix (sem o esquema unix://)
+	socket string
+	libpod *http.Client
+
+	// rootless é o modo do Podman, consultado uma vez (ver Rootless)
+	rootlessMu sync.Mutex
+	rootless   *bool
+}
+
+// healthPollInterval é o intervalo entre as verificações de WaitHealthy
+var healthPollInterval = 2 * time.Second
+
+// NewClient conecta no socket do Podman
+// Se host for vazio, usa o primeiro socket encontrado (ver DetectSocket)
+func NewClient(host string) (*Client, error) {
+	if host == "" {
+		detected, ok := DetectSocket()
+		if !ok {
+			return nil, fmt.Errorf("socket do Podman não encontrado (tentados: %s); ative com 'systemctl --user enable --now podman.socket'",
+				strings.Join(SocketCandidates(), ", "))
+		}
+		host = detected
+	}
+
+	socket, ok := strings.CutPrefix(host, "unix://")
+	if !ok {
+		return nil, fmt.Errorf("endereço do Podman não suportado: %s (use unix://)", host)
+	}
+
+	compat, err := docker.NewClientWithHost(host)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao criar Podman client: %w", err)
+	}
+
+	return &Client{
+		Client: compat,
+		socket: socket,
+		libpod: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, "unix", socket)
+				},
+			},
+			Timeout: 2 * time.Minute,
+		},
+	}, nil
+}
+
+// SocketCandidates retorna os sockets do Podman na ordem de busca:
+// $CONTAINER_HOST, o socket rootless do usuário e o socket do sistema
+func SocketCandidates() []string {
+	var candidates []string
+	if v := os.Getenv(EnvContainerHost); v != "" {
+		candidates = append(candidates, v)
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		candidates = append(candidates, "unix://"+filepath.Join(dir, "podman", "podman.sock"))
+	} else if uid := os.Getuid(); uid > 0 {
+		candidates = append(candidates, fmt.Sprintf("unix:///run/user/%d/podman/podman.sock", uid))
+	}
+	return append(candidates, "unix:///run/podman/podman.sock")
+}
+
+// DetectSocket retorna o primeiro socket do Podman que existe
+func DetectSocket() (string, bool) {
+	for _, candidate := range SocketCandidates() {
+		socket, ok := strings.CutPrefix(candidate, "unix://")
+		if !ok {
+			continue
+		}
+		if info, err := os.Stat(socket); err == nil && info.Mode()&os.ModeSocket != 0 {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// Ping verifica se o Podman está acessível
+// Usa o endpoint da libpod, que só existe no Podman: um socket do Docker falha aqui
+func (c *Client) Ping(ctx context.Context) error {
+	return c.get(ctx, "/_ping", nil)
+}
+
+// Version retorna a versão do Podman
+func (c *Client) Version(ctx context.Context) (string, error) {
+	var v struct {
+		Version string `json:"Version"`
+	}
+	if err := c.get(ctx, "/version", &v); err != nil {
+		return "", fmt.Errorf("falha ao consultar versão do Podman: %w", err)
+	}
+	return v.Version, nil
+}
+
+// Rootless informa se o Podman roda sem root (campo host.security.rootless de /info)
+// No modo rootless a network dos containers fica no namespace do usuário e os IPs não
+// são alcançáveis pelo host: os containers passam a publicar a porta em 127.0.0.1 e os
+// health checks HTTP e o proxy embutido usam essa porta (ver docker.SetLoopbackPublish)
+func (c *Client) Rootless(ctx context.Context) (bool, error) {
+	c.rootlessMu.Lock()
+	defer c.rootlessMu.Unlock()
+
+	if c.rootless != nil {
+		return *c.rootless, nil
+	}
+
+	var info struct {
+		Host struct {
+			Security struct {
+				Rootless bool `json:"rootless"`
+			} `json:"security"`
+		} `json:"host"`
+	}
+	if err := c.get(ctx, "/info", &info); err != nil {
+		return false, fmt.Errorf("falha ao consultar modo do Podman: %w", err)
+	}
+
+	rootless := info.Host.Security.Rootless
+	c.rootless = &rootless
+	c.Client.SetLoopbackPublish(rootless)
+	return rootless, nil
+}
+
+// Create cria o container; no modo rootless a porta é sempre publicada em 127.0.0.1
+func (c *Client) Create(ctx context.Context, intent domain.Intent, version string, replica int, publishPort bool, live bool) (string, error) {
+	if _, err := c.Rootless(ctx); err != nil {
+		return "", err
+	}
+	return c.Client.Create(ctx, intent, version, replica, publishPort, live)
+}
+
+// Inspect retorna informações detalhadas de um container; no modo rootless o IP fica
+// vazio e o container é alcançado pela porta publicada (PublicPort)
+func (c *Client) Inspect(ctx context.Context, containerID string) (*domain.Container, error) {
+	if _, err := c.Rootless(ctx); err != nil {
+		return nil, err
+	}
+	return c.Client.Inspect(ctx, containerID)
+}
+
+// WaitHealthy aguarda o container ficar healthy ou timeout
+// O Podman agenda os health checks com timers do systemd; sem eles (ex: rootless sem
+// sessão systemd) o status ficaria em "starting" para sempre, então o OI executa o
+// health check sob demanda enquanto espera
+func (c *Client) WaitHealthy(ctx context.Context, containerID string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	ticker := time.NewTicker(healthPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if time.Now().After(deadline) {
+				return domain.ErrHealthCheckFailed{
+					ContainerID: containerID,
+					Reason:      "timeout esperando container ficar healthy",
+				}
+			}
+
+			ctr, err := c.Inspect(ctx, containerID)
+			if err != nil {
+				return err
+			}
+			if ctr.Status != domain.StatusRunning {
+				continue
+			}
+
+			health := ctr.Health
+			if health == domain.HealthStarting {
+				// Falhas aqui (ex: processo ainda subindo) são tratadas no próximo tick
+				if status, err := c.runHealthcheck(ctx, containerID); err == nil {
+					health = status
+				}
+			}
+
+			switch health {
+			case domain.HealthHealthy:
+				return nil
+			case domain.HealthUnhealthy:
+				return domain.ErrHealthCheckFailed{
+					ContainerID: containerID,
+					Reason:      "container reportou unhealthy",
+				}
+			}
+		}
+	}
+}
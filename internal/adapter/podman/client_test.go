@@ -0,0 +1,248 @@
+package podman
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// stubAPIVersion é a versão da API compatível anunciada pelo stub em /_ping
+const stubAPIVersion = "1.41"
+
+// libpodStub simula o socket do Podman: a API libpod e a compatível com Docker
+type libpodStub struct {
+	rootless bool
+	// health é o status do container "abc" informado por Inspect
+	health string
+	// healthcheck é o resultado de /libpod/containers/abc/healthcheck
+	healthcheck string
+
+	infoCalls        atomic.Int32
+	healthcheckCalls atomic.Int32
+	// created guarda o corpo do último POST /containers/create
+	created chan map[string]interface{}
+}
+
+func (s *libpodStub) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/_ping", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Api-Version", stubAPIVersion)
+		fmt.Fprint(w, "OK")
+	})
+	mux.HandleFunc("/"+libpodAPIVersion+"/libpod/_ping", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "OK")
+	})
+	mux.HandleFunc("/"+libpodAPIVersion+"/libpod/info", func(w http.ResponseWriter, r *http.Request) {
+		s.infoCalls.Add(1)
+		fmt.Fprintf(w, `{"host":{"security":{"rootless":%t}}}`, s.rootless)
+	})
+	mux.HandleFunc("/"+libpodAPIVersion+"/libpod/containers/abc/healthcheck", func(w http.ResponseWriter, r *http.Request) {
+		s.healthcheckCalls.Add(1)
+		fmt.Fprintf(w, `{"Status":%q}`, s.healthcheck)
+	})
+	mux.HandleFunc("/v"+stubAPIVersion+"/containers/abc/json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{
+			"Id": "abc",
+			"Name": "/oi-loja-v1-1",
+			"Image": "sha256:123",
+			"State": {"Running": true, "Status": "running", "Health": {"Status": %q}},
+			"Config": {"Image": "nginx", "Labels": {"io.oi.project": "loja", "io.oi.port": "80"}},
+			"HostConfig": {},
+			"NetworkSettings": {
+				"Networks": {"oi-loja-net": {"IPAddress": "10.89.0.5"}},
+				"Ports": {"80/tcp": [{"HostIp": "127.0.0.1", "HostPort": "40123"}]}
+			},
+			"Mounts": []
+		}`, s.health)
+	})
+	mux.HandleFunc("/v"+stubAPIVersion+"/containers/create", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		s.created <- body
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"Id": "abc", "Warnings": []}`)
+	})
+	return mux
+}
+
+// startStub serve o stub num socket unix temporário e retorna o client conectado nele
+func startStub(t *testing.T, stub *libpodStub) *Client {
+	t.Helper()
+	stub.created = make(chan map[string]interface{}, 1)
+
+	dir, err := os.MkdirTemp("", "oi-podman")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	socket := filepath.Join(dir, "podman.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &http.Server{Handler: stub.handler()}
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	c, err := NewClient("unix://" + socket)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestWaitHealthyRunsHealthcheck(t *testing.T) {
+	defer func(interval time.Duration) { healthPollInterval = interval }(healthPollInterval)
+	healthPollInterval = 10 * time.Millisecond
+
+	tests := []struct {
+		name        string
+		healthcheck string
+		wantErr     bool
+	}{
+		{name: "healthy", healthcheck: "healthy"},
+		{name: "unhealthy", healthcheck: "unhealthy", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &libpodStub{health: "starting", healthcheck: tt.healthcheck}
+			c := startStub(t, stub)
+
+			err := c.WaitHealthy(context.Background(), "abc", 5*time.Second)
+			if tt.wantErr {
+				var healthErr domain.ErrHealthCheckFailed
+				if err == nil || !errors.As(err, &healthErr) {
+					t.Errorf("WaitHealthy = %v; esperado ErrHealthCheckFailed", err)
+				}
+			} else if err != nil {
+				t.Errorf("WaitHealthy = %v; esperado nil", err)
+			}
+			if stub.healthcheckCalls.Load() == 0 {
+				t.Error("health check não foi executado sob demanda com o status em starting")
+			}
+		})
+	}
+}
+
+func TestWaitHealthySkipsHealthcheckWhenScheduled(t *testing.T) {
+	defer func(interval time.Duration) { healthPollInterval = interval }(healthPollInterval)
+	healthPollInterval = 10 * time.Millisecond
+
+	stub := &libpodStub{health: "healthy"}
+	c := startStub(t, stub)
+
+	if err := c.WaitHealthy(context.Background(), "abc", 5*time.Second); err != nil {
+		t.Fatalf("WaitHealthy: %v", err)
+	}
+	if n := stub.healthcheckCalls.Load(); n != 0 {
+		t.Errorf("health check executado %d vezes; esperado 0", n)
+	}
+}
+
+func TestRootlessPublishesOnLoopback(t *testing.T) {
+	ctx := context.Background()
+	stub := &libpodStub{rootless: true, health: "healthy"}
+	c := startStub(t, stub)
+
+	rootless, err := c.Rootless(ctx)
+	if err != nil || !rootless {
+		t.Fatalf("Rootless = %v, %v; esperado true", rootless, err)
+	}
+
+	if _, err := c.Create(ctx, domain.Intent{Nome: "loja", Origem: "nginx", Porta: 80}, "abc12345", 1, false, false); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	body := <-stub.created
+	hostConfig, _ := body["HostConfig"].(map[string]interface{})
+	bindings, _ := hostConfig["PortBindings"].(map[string]interface{})
+	binding, _ := bindings["80/tcp"].([]interface{})
+	if len(binding) != 1 {
+		t.Fatalf("PortBindings = %v; esperado a porta 80 publicada", hostConfig["PortBindings"])
+	}
+	if b := binding[0].(map[string]interface{}); b["HostIp"] != "127.0.0.1" || b["HostPort"] != "0" {
+		t.Errorf("binding = %v; esperado 127.0.0.1 com porta aleatória", b)
+	}
+
+	ctr, err := c.Inspect(ctx, "abc")
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if ctr.IP != "" || ctr.PublicPort != 40123 {
+		t.Errorf("IP = %q, PublicPort = %d; esperado IP vazio e porta 40123", ctr.IP, ctr.PublicPort)
+	}
+
+	if n := stub.infoCalls.Load(); n != 1 {
+		t.Errorf("/info consultado %d vezes; esperado 1", n)
+	}
+}
+
+func TestRootfulKeepsNetworkIP(t *testing.T) {
+	ctx := context.Background()
+	c := startStub(t, &libpodStub{health: "healthy"})
+
+	if _, err := c.Create(ctx, domain.Intent{Nome: "loja", Origem: "nginx", Porta: 80}, "abc12345", 1, false, false); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	ctr, err := c.Inspect(ctx, "abc")
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if ctr.IP != "10.89.0.5" {
+		t.Errorf("IP = %q; esperado o IP da network do projeto", ctr.IP)
+	}
+}
+
+func TestDetectSocket(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvContainerHost, "")
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+
+	if host, ok := DetectSocket(); ok && host != "unix:///run/podman/podman.sock" {
+		t.Errorf("DetectSocket = %q sem socket do usuário", host)
+	}
+
+	socketDir, err := os.MkdirTemp("", "oi-podman")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(socketDir)
+	if err := os.MkdirAll(filepath.Join(dir, "podman"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Um arquivo comum no caminho do socket não conta
+	if err := os.WriteFile(filepath.Join(dir, "podman", "podman.sock"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if host, ok := DetectSocket(); ok && host == "unix://"+filepath.Join(dir, "podman", "podman.sock") {
+		t.Errorf("DetectSocket aceitou um arquivo comum: %q", host)
+	}
+
+	socket := filepath.Join(socketDir, "podman.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	t.Setenv(EnvContainerHost, "unix://"+socket)
+	want := []string{"unix://" + socket, "unix://" + filepath.Join(dir, "podman", "podman.sock"), "unix:///run/podman/podman.sock"}
+	if got := SocketCandidates(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("SocketCandidates = %v; esperado %v", got, want)
+	}
+	if host, ok := DetectSocket(); !ok || host != "unix://"+socket {
+		t.Errorf("DetectSocket = %q, %v; esperado o socket de %s", host, ok, EnvContainerHost)
+	}
+}
@@ -0,0 +1,72 @@
+package podman
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// libpodAPIVersion é a versão da API libpod usada nas rotas (aceita pelo Podman 4 e 5)
+const libpodAPIVersion = "v4.0.0"
+
+// get faz GET em uma rota da API libpod e decodifica a resposta em v (se não for nil)
+func (c *Client) get(ctx context.Context, path string, v interface{}) error {
+	// O host é ignorado: a conexão sempre vai para o socket
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://podman/"+libpodAPIVersion+"/libpod"+path, nil)
+	if err != nil {
+		return fmt.Errorf("falha ao criar request: %w", err)
+	}
+
+	resp, err := c.libpod.Do(req)
+	if err != nil {
+		return fmt.Errorf("falha ao acessar %s: %w", c.socket, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		// Erros da libpod vêm como {"cause": ..., "message": ..., "response": ...}
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("podman retornou %d: %s", resp.StatusCode, apiErr.Message)
+		}
+		return fmt.Errorf("podman retornou %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("resposta inválida do Podman: %w", err)
+	}
+	return nil
+}
+
+// runHealthcheck executa o health check do container e retorna o status resultante
+func (c *Client) runHealthcheck(ctx context.Context, containerID string) (domain.HealthStatus, error) {
+	var result struct {
+		Status string `json:"Status"`
+	}
+	if err := c.get(ctx, "/containers/"+url.PathEscape(containerID)+"/healthcheck", &result); err != nil {
+		return domain.HealthUnknown, fmt.Errorf("falha ao executar health check: %w", err)
+	}
+
+	switch result.Status {
+	case "healthy":
+		return domain.HealthHealthy, nil
+	case "unhealthy":
+		return domain.HealthUnhealthy, nil
+	case "starting":
+		return domain.HealthStarting, nil
+	default:
+		return domain.HealthUnknown, nil
+	}
+}
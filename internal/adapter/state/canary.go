@@ -0,0 +1,69 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/crom-tech/oi/internal/atomicfile"
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+const canaryFileName = "canary"
+
+// Canary retorna o canário em andamento do serviço (nil se não houver)
+func (s *FileStore) Canary(ctx context.Context, project string, service string) (*domain.Canary, error) {
+	path, err := s.canaryPath(project, service)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler canário de %s: %w", project, err)
+	}
+
+	var canary domain.Canary
+	if err := json.Unmarshal(data, &canary); err != nil {
+		return nil, fmt.Errorf("falha ao parsear canário de %s: %w", project, err)
+	}
+	return &canary, nil
+}
+
+// SetCanary registra o canário em andamento do serviço (nil remove o registro)
+func (s *FileStore) SetCanary(ctx context.Context, project string, service string, canary *domain.Canary) error {
+	path, err := s.canaryPath(project, service)
+	if err != nil {
+		return err
+	}
+
+	if canary == nil {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("falha ao limpar canário de %s: %w", project, err)
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(canary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("falha ao serializar canário: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("falha ao criar diretório de estado: %w", err)
+	}
+	if err := atomicfile.Write(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("falha ao gravar %s: %w", path, err)
+	}
+	return nil
+}
+
+// canaryPath retorna o arquivo do canário ("canary" ou "canary.<serviço>")
+func (s *FileStore) canaryPath(project, service string) (string, error) {
+	return s.projectFile(project, service, canaryFileName)
+}
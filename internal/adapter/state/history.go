@@ -0,0 +1,109 @@
+package state
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+const historyFileName = "history.jsonl"
+
+// AppendHistory adiciona uma entrada ao journal do projeto (uma linha JSON por deploy)
+func (s *FileStore) AppendHistory(ctx context.Context, record domain.DeployRecord) error {
+	path, err := s.projectFile(record.Project, "", historyFileName)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("falha ao criar diretório de estado: %w", err)
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("falha ao serializar histórico: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("falha ao abrir histórico: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("falha ao gravar histórico: %w", err)
+	}
+	return nil
+}
+
+// History retorna o histórico de um projeto (ou de todos, se project for vazio)
+func (s *FileStore) History(ctx context.Context, project string) ([]domain.DeployRecord, error) {
+	projects := []string{project}
+	if project == "" {
+		entries, err := os.ReadDir(s.dir)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("falha ao listar estado: %w", err)
+		}
+		projects = projects[:0]
+		for _, e := range entries {
+			if e.IsDir() {
+				projects = append(projects, e.Name())
+			}
+		}
+	}
+
+	var records []domain.DeployRecord
+	for _, p := range projects {
+		r, err := s.readHistory(p)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r...)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Timestamp.Before(records[j].Timestamp)
+	})
+	return records, nil
+}
+
+// readHistory lê o journal de um projeto, ignorando linhas corrompidas
+func (s *FileStore) readHistory(project string) ([]domain.DeployRecord, error) {
+	path, err := s.projectFile(project, "", historyFileName)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler histórico de %s: %w", project, err)
+	}
+	defer file.Close()
+
+	var records []domain.DeployRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r domain.DeployRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			// Uma escrita interrompida não deve impedir a leitura do restante
+			continue
+		}
+		records = append(records, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("falha ao ler histórico de %s: %w", project, err)
+	}
+	return records, nil
+}
@@ -0,0 +1,107 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/crom-tech/oi/internal/atomicfile"
+)
+
+const activeFileName = "active"
+
+// FileStore implementa port.StateStore usando arquivos em ~/.oi/state/<projeto>/
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore cria uma nova instância do FileStore
+// Se dir for vazio, usa ~/.oi/state
+func NewFileStore(dir string) (*FileStore, error) {
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("falha ao localizar diretório home: %w", err)
+		}
+		dir = filepath.Join(home, ".oi", "state")
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// ActiveVersion retorna a versão ativa registrada para o serviço do projeto
+func (s *FileStore) ActiveVersion(ctx context.Context, project string, service string) (string, error) {
+	path, err := s.activePath(project, service)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("falha ao ler estado de %s: %w", project, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// SetActiveVersion registra a versão ativa do serviço (vazio remove o registro)
+func (s *FileStore) SetActiveVersion(ctx context.Context, project string, service string, version string) error {
+	path, err := s.activePath(project, service)
+	if err != nil {
+		return err
+	}
+
+	if version == "" {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("falha ao limpar estado de %s: %w", project, err)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("falha ao criar diretório de estado: %w", err)
+	}
+	if err := atomicfile.Write(path, []byte(version+"\n"), 0600); err != nil {
+		return fmt.Errorf("falha ao gravar %s: %w", path, err)
+	}
+	return nil
+}
+
+// projectDir retorna o diretório de estado de um projeto
+// O nome vira um componente do caminho: vazio, "." e "..", ou com separadores, são
+// recusados para nunca sair de s.dir
+func (s *FileStore) projectDir(project string) (string, error) {
+	if !validPathName(project) {
+		return "", fmt.Errorf("nome de projeto inválido para o estado: %q", project)
+	}
+	return filepath.Join(s.dir, project), nil
+}
+
+// projectFile retorna um arquivo do diretório de estado do projeto: name, seguido de
+// ".<serviço>" quando service não for vazio
+func (s *FileStore) projectFile(project, service, name string) (string, error) {
+	dir, err := s.projectDir(project)
+	if err != nil {
+		return "", err
+	}
+	if service != "" {
+		if !validPathName(service) {
+			return "", fmt.Errorf("nome de serviço inválido para o estado: %q", service)
+		}
+		name += "." + service
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// activePath retorna o arquivo da versão ativa ("active" ou "active.<serviço>")
+func (s *FileStore) activePath(project, service string) (string, error) {
+	return s.projectFile(project, service, activeFileName)
+}
+
+// validPathName verifica se o nome pode ser usado como um único componente de caminho
+func validPathName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
@@ -0,0 +1,53 @@
+package state
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+func TestFileStoreRejectsPathNames(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	s, err := NewFileStore(filepath.Join(root, "state"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, project := range []string{"", ".", "..", "../fora", "a/b", `a\b`} {
+		if err := s.SetActiveVersion(ctx, project, "", "v1"); err == nil {
+			t.Errorf("SetActiveVersion(%q) deveria falhar", project)
+		}
+		if _, err := s.ActiveVersion(ctx, project, ""); err == nil {
+			t.Errorf("ActiveVersion(%q) deveria falhar", project)
+		}
+		if err := s.AppendHistory(ctx, domain.DeployRecord{Project: project}); err == nil {
+			t.Errorf("AppendHistory(%q) deveria falhar", project)
+		}
+		if err := s.SetCanary(ctx, project, "", &domain.Canary{}); err == nil {
+			t.Errorf("SetCanary(%q) deveria falhar", project)
+		}
+	}
+	if err := s.SetActiveVersion(ctx, "loja", "../api", "v1"); err == nil {
+		t.Error("serviço com separador deveria falhar")
+	}
+
+	// Nada foi gravado fora do diretório de estado
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("arquivos criados fora do estado: %v", entries)
+	}
+
+	if err := s.SetActiveVersion(ctx, "loja.com_v2", "api", "v1"); err != nil {
+		t.Fatalf("SetActiveVersion: %v", err)
+	}
+	if v, err := s.ActiveVersion(ctx, "loja.com_v2", "api"); err != nil || v != "v1" {
+		t.Errorf("ActiveVersion = %q, %v; esperado v1", v, err)
+	}
+}
@@ -0,0 +1,459 @@
+package traefik
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/crom-tech/oi/internal/atomicfile"
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// Valores padrão da conexão com o Traefik
+const (
+	DefaultAPIURL = "http://localhost:8080"
+	DefaultDir    = "/etc/traefik/dynamic"
+)
+
+// basePriority coloca os routers do OI com prioridade explícita acima dos que usam a
+// prioridade padrão do Traefik (o tamanho da regra)
+const basePriority = 1000
+
+// fileExt é a extensão dos arquivos gerados
+// O conteúdo é JSON, que também é YAML válido: o file provider só lê .yml, .yaml e .toml
+const fileExt = ".yml"
+
+// Manager implementa port.ProxyManager usando o file provider do Traefik
+// Cada rota vira um arquivo de configuração dinâmica (<id>.yml) no diretório observado
+// pelo Traefik; a API é usada apenas para verificar a saúde
+type Manager struct {
+	apiURL string
+	dir    string
+	// entryPoints e certResolver são aplicados a todos os routers (vazios: padrão do Traefik)
+	entryPoints  []string
+	certResolver string
+	httpClient   *http.Client
+}
+
+// NewManager cria uma nova instância do Traefik Manager
+// apiURL e dir vazios usam DefaultAPIURL e DefaultDir
+func NewManager(apiURL string, dir string, entryPoints []string, certResolver string) *Manager {
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	if dir == "" {
+		dir = DefaultDir
+	}
+	return &Manager{
+		apiURL:       strings.TrimSuffix(apiURL, "/"),
+		dir:          dir,
+		entryPoints:  entryPoints,
+		certResolver: certResolver,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// APIURL retorna o endereço da API do Traefik usada
+func (m *Manager) APIURL() string {
+	return m.apiURL
+}
+
+// Dir retorna o diretório de configuração dinâmica gerenciado
+func (m *Manager) Dir() string {
+	return m.dir
+}
+
+// dynamicConfig é o conteúdo de um arquivo do file provider
+type dynamicConfig struct {
+	HTTP httpConfig `json:"http"`
+}
+
+type httpConfig struct {
+	Routers     map[string]routerConfig     `json:"routers"`
+	Services    map[string]serviceConfig    `json:"services"`
+	Middlewares map[string]middlewareConfig `json:"middlewares,omitempty"`
+}
+
+type routerConfig struct {
+	Rule        string     `json:"rule"`
+	Service     string     `json:"service"`
+	Priority    int        `json:"priority,omitempty"`
+	EntryPoints []string   `json:"entryPoints,omitempty"`
+	Middlewares []string   `json:"middlewares,omitempty"`
+	TLS         *tlsConfig `json:"tls,omitempty"`
+}
+
+// middlewareConfig é usado apenas para remover o prefixo de caminho (remover_prefixo)
+type middlewareConfig struct {
+	StripPrefix *stripPrefix `json:"stripPrefix,omitempty"`
+}
+
+type stripPrefix struct {
+	Prefixes []string `json:"prefixes"`
+}
+
+type tlsConfig struct {
+	CertResolver string `json:"certResolver,omitempty"`
+}
+
+// serviceConfig tem um loadBalancer (réplicas) ou weighted (canário, com um serviço por upstream)
+type serviceConfig struct {
+	LoadBalancer *loadBalancer `json:"loadBalancer,omitempty"`
+	Weighted     *weighted     `json:"weighted,omitempty"`
+}
+
+type loadBalancer struct {
+	Servers []server `json:"servers"`
+	Sticky  *sticky  `json:"sticky,omitempty"`
+}
+
+type server struct {
+	URL string `json:"url"`
+}
+
+type sticky struct {
+	Cookie map[string]string `json:"cookie"`
+}
+
+type weighted struct {
+	Services []weightedService `json:"services"`
+}
+
+type weightedService struct {
+	Name   string `json:"name"`
+	Weight int    `json:"weight"`
+}
+
+// hostRule é a regra de roteamento por domínio
+// Curingas usam HostRegexp no formato do Traefik v3 (um nível de subdomínio)
+func hostRule(domain string) string {
+	if strings.HasPrefix(domain, "*.") {
+		return "HostRegexp(`^[^.]+" + regexp.QuoteMeta(domain[1:]) + "$`)"
+	}
+	return "Host(`" + domain + "`)"
+}
+
+// routeRule é a regra da rota: qualquer um dos hosts e, se houver, o prefixo de caminho
+// (o próprio prefixo ou um subcaminho)
+func routeRule(r domain.Route) string {
+	hosts := r.Hosts()
+	rules := make([]string, 0, len(hosts))
+	for _, h := range hosts {
+		rules = append(rules, hostRule(h))
+	}
+	rule := strings.Join(rules, " || ")
+	if r.PathPrefix == "" {
+		return rule
+	}
+	if len(rules) > 1 {
+		rule = "(" + rule + ")"
+	}
+	return rule + " && (Path(`" + r.PathPrefix + "`) || PathPrefix(`" + r.PathPrefix + "/`))"
+}
+
+// rulePattern extrai hosts e prefixo das regras geradas por routeRule
+var rulePattern = regexp.MustCompile("(Host|HostRegexp|PathPrefix)\\(`([^`]*)`\\)")
+
+// ruleSites retorna os endereços atendidos por uma regra gerada por routeRule
+func ruleSites(rule string) []domain.Site {
+	var hosts []string
+	path := ""
+	for _, m := range rulePattern.FindAllStringSubmatch(rule, -1) {
+		switch m[1] {
+		case "Host":
+			hosts = append(hosts, m[2])
+		case "HostRegexp":
+			// ^[^.]+\.loja\.com$ -> *.loja.com
+			suffix := strings.TrimSuffix(strings.TrimPrefix(m[2], "^[^.]+"), "$")
+			hosts = append(hosts, "*"+strings.ReplaceAll(suffix, `\.`, "."))
+		case "PathPrefix":
+			path = strings.TrimSuffix(m[2], "/")
+		}
+	}
+	sites := make([]domain.Site, 0, len(hosts))
+	for _, h := range hosts {
+		sites = append(sites, domain.Site{Host: h, Path: path})
+	}
+	return sites
+}
+
+// AddRoute grava (ou substitui) o arquivo da rota de forma atômica
+// O Traefik recarrega o arquivo sozinho; a troca do conjunto de upstreams é atômica para o tráfego
+// A prioridade do router segue domain.Route.Precedence (prefixos mais longos e hosts
+// exatos primeiro), e remover_prefixo vira um middleware stripPrefix
+// O Traefik só balanceia em round robin: ip_hash vira afinidade por cookie e as demais
+// políticas usam round robin
+func (m *Manager) AddRoute(ctx context.Context, r domain.Route) error {
+	if r.ID == "" {
+		return fmt.Errorf("rota para %s sem ID", r.Domain)
+	}
+
+	router := routerConfig{
+		Rule:        routeRule(r),
+		Service:     r.ID,
+		EntryPoints: m.entryPoints,
+	}
+	// Sem prioridade explícita o Traefik usa o tamanho da regra, que cresce com os aliases
+	// e colocaria curingas acima de hosts exatos
+	router.Priority = basePriority + r.Precedence()
+	if m.certResolver != "" {
+		router.TLS = &tlsConfig{CertResolver: m.certResolver}
+	}
+
+	cfg := dynamicConfig{HTTP: httpConfig{
+		Routers:  map[string]routerConfig{r.ID: router},
+		Services: make(map[string]serviceConfig),
+	}}
+	if r.StripPrefix && r.PathPrefix != "" {
+		name := r.ID + "-strip"
+		router.Middlewares = []string{name}
+		cfg.HTTP.Routers[r.ID] = router
+		cfg.HTTP.Middlewares = map[string]middlewareConfig{
+			name: {StripPrefix: &stripPrefix{Prefixes: []string{r.PathPrefix}}},
+		}
+	}
+
+	weightedRoute := false
+	for _, u := range r.Upstreams {
+		weightedRoute = weightedRoute || u.Weight > 0
+	}
+
+	if weightedRoute {
+		// Canário: um serviço por upstream, combinados por peso (ex: 90/10)
+		split := &weighted{}
+		for i, u := range r.Upstreams {
+			name := fmt.Sprintf("%s-%d", r.ID, i+1)
+			cfg.HTTP.Services[name] = serviceConfig{LoadBalancer: &loadBalancer{
+				Servers: []server{{URL: upstreamURL(u)}},
+			}}
+			split.Services = append(split.Services, weightedService{Name: name, Weight: u.Weight})
+		}
+		cfg.HTTP.Services[r.ID] = serviceConfig{Weighted: split}
+	} else {
+		lb := &loadBalancer{}
+		for _, u := range r.Upstreams {
+			lb.Servers = append(lb.Servers, server{URL: upstreamURL(u)})
+		}
+		if r.PolicyOrDefault() == domain.PolicyIPHash && len(lb.Servers) > 1 {
+			lb.Sticky = &sticky{Cookie: map[string]string{"name": r.ID}}
+		}
+		cfg.HTTP.Services[r.ID] = serviceConfig{LoadBalancer: lb}
+	}
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("falha ao serializar rota: %w", err)
+	}
+
+	// Outra rota do OI para os mesmos endereços teria conflito de regra
+	if err := m.removeConflicting(r.Sites(), r.ID); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return fmt.Errorf("falha ao criar diretório %s: %w", m.dir, err)
+	}
+	if err := atomicfile.Write(m.routePath(r.ID), data, 0644); err != nil {
+		return fmt.Errorf("falha ao gravar rota: %w", err)
+	}
+	return nil
+}
+
+// upstreamURL é o endereço do upstream no formato do loadBalancer do Traefik
+func upstreamURL(u domain.Upstream) string {
+	return "http://" + u.Address()
+}
+
+// RemoveRoute remove o arquivo da rota e outros arquivos do OI para os mesmos endereços
+// Rotas de outros prefixos no mesmo host continuam
+func (m *Manager) RemoveRoute(ctx context.Context, r domain.Route) error {
+	if r.ID != "" {
+		if err := os.Remove(m.routePath(r.ID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("falha ao remover rota: %w", err)
+		}
+	}
+	if sites := r.Sites(); len(sites) > 0 {
+		return m.removeConflicting(sites, r.ID)
+	}
+	return nil
+}
+
+// removeConflicting remove os arquivos de rota do OI com router para algum dos endereços,
+// exceto o de keepID (ex: rota gravada com outro ID para o mesmo domínio), que disputariam
+// a mesma regra
+func (m *Manager) removeConflicting(sites []domain.Site, keepID string) error {
+	files, err := m.listRoutes()
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if f.id == keepID || !f.config.servesAny(sites) {
+			continue
+		}
+		if err := os.Remove(f.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("falha ao remover rota: %w", err)
+		}
+	}
+	return nil
+}
+
+// HasRoute verifica se existe rota para o endereço
+func (m *Manager) HasRoute(ctx context.Context, site string) (bool, error) {
+	upstreams, err := m.GetUpstreams(ctx, site)
+	if err != nil {
+		return false, err
+	}
+	return len(upstreams) > 0, nil
+}
+
+// GetUpstreams retorna os upstreams (host:porta) gravados para o endereço "host[/prefixo]"
+// Lê os arquivos do diretório, que são a fonte da verdade do file provider
+func (m *Manager) GetUpstreams(ctx context.Context, site string) ([]string, error) {
+	target, err := domain.ParseSite(site)
+	if err != nil {
+		return nil, err
+	}
+	files, err := m.listRoutes()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		if router, ok := f.config.router(target); ok {
+			return f.config.upstreams(router.Service), nil
+		}
+	}
+	return nil, nil
+}
+
+// router retorna o router do arquivo que atende o endereço
+func (c dynamicConfig) router(site domain.Site) (routerConfig, bool) {
+	for _, router := range c.HTTP.Routers {
+		for _, s := range ruleSites(router.Rule) {
+			if s == site {
+				return router, true
+			}
+		}
+	}
+	return routerConfig{}, false
+}
+
+// servesAny verifica se algum router do arquivo atende algum dos endereços
+func (c dynamicConfig) servesAny(sites []domain.Site) bool {
+	for _, site := range sites {
+		if _, ok := c.router(site); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// upstreams resolve os servidores de um serviço (inclusive os de um serviço weighted)
+func (c dynamicConfig) upstreams(name string) []string {
+	svc, ok := c.HTTP.Services[name]
+	if !ok {
+		return nil
+	}
+	var result []string
+	if svc.LoadBalancer != nil {
+		for _, s := range svc.LoadBalancer.Servers {
+			result = append(result, strings.TrimPrefix(strings.TrimPrefix(s.URL, "http://"), "https://"))
+		}
+	}
+	if svc.Weighted != nil {
+		for _, child := range svc.Weighted.Services {
+			result = append(result, c.upstreams(child.Name)...)
+		}
+	}
+	return result
+}
+
+// routeFile é um arquivo de rota do OI no diretório do file provider
+type routeFile struct {
+	id     string
+	path   string
+	config dynamicConfig
+}
+
+// listRoutes lê os arquivos de rota do OI (oi-*.yml), em ordem de nome
+// Arquivos de outras origens no mesmo diretório são ignorados
+func (m *Manager) listRoutes() ([]routeFile, error) {
+	entries, err := os.ReadDir(m.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar %s: %w", m.dir, err)
+	}
+
+	var files []routeFile
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, "oi-") || !strings.HasSuffix(name, fileExt) {
+			continue
+		}
+		path := filepath.Join(m.dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler %s: %w", path, err)
+		}
+		var cfg dynamicConfig
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("falha ao parsear %s: %w", path, err)
+		}
+		files = append(files, routeFile{id: strings.TrimSuffix(name, fileExt), path: path, config: cfg})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].id < files[j].id })
+	return files, nil
+}
+
+func (m *Manager) routePath(id string) string {
+	return filepath.Join(m.dir, id+fileExt)
+}
+
+// Reload força recarregamento da configuração
+func (m *Manager) Reload(ctx context.Context) error {
+	// O file provider observa o diretório, as mudanças são aplicadas sozinhas
+	return nil
+}
+
+// Health verifica se a API do Traefik responde e se o file provider está habilitado
+func (m *Manager) Health(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.apiURL+"/api/overview", nil)
+	if err != nil {
+		return fmt.Errorf("falha ao criar request: %w", err)
+	}
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Traefik não acessível: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("Traefik retornou status %d (a API está habilitada?)", resp.StatusCode)
+	}
+
+	var overview struct {
+		Providers []string `json:"providers"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&overview); err != nil {
+		return fmt.Errorf("resposta inválida da API do Traefik: %w", err)
+	}
+	for _, p := range overview.Providers {
+		if strings.EqualFold(p, "file") {
+			return nil
+		}
+	}
+	return fmt.Errorf("file provider não habilitado no Traefik (use --providers.file.directory=%s --providers.file.watch=true)", m.dir)
+}
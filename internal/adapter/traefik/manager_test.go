@@ -0,0 +1,289 @@
+package traefik
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+func route(id, domainName string, upstreams ...string) domain.Route {
+	r := domain.Route{ID: id, Domain: domainName}
+	for _, u := range upstreams {
+		host, port, _ := strings.Cut(u, ":")
+		p, _ := strconv.Atoi(port)
+		r.Upstreams = append(r.Upstreams, domain.Upstream{Host: host, Port: p})
+	}
+	return r
+}
+
+// readRoute lê o arquivo gravado para a rota
+func readRoute(t *testing.T, m *Manager, id string) dynamicConfig {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(m.Dir(), id+fileExt))
+	if err != nil {
+		t.Fatalf("arquivo da rota %s não encontrado: %v", id, err)
+	}
+	var cfg dynamicConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("arquivo da rota %s inválido: %v", id, err)
+	}
+	return cfg
+}
+
+func TestAddRouteWritesRouterAndService(t *testing.T) {
+	m := NewManager("", t.TempDir(), []string{"websecure"}, "le")
+	ctx := context.Background()
+
+	if err := m.AddRoute(ctx, route("oi-loja", "loja.com", "oi-loja-1:80", "oi-loja-2:80")); err != nil {
+		t.Fatalf("AddRoute: %v", err)
+	}
+
+	cfg := readRoute(t, m, "oi-loja")
+	router := cfg.HTTP.Routers["oi-loja"]
+	if router.Rule != "Host(`loja.com`)" || router.Service != "oi-loja" {
+		t.Errorf("router inesperado: %+v", router)
+	}
+	if !reflect.DeepEqual(router.EntryPoints, []string{"websecure"}) || router.TLS == nil || router.TLS.CertResolver != "le" {
+		t.Errorf("entrypoints/TLS inesperados: %+v", router)
+	}
+	lb := cfg.HTTP.Services["oi-loja"].LoadBalancer
+	if lb == nil || len(lb.Servers) != 2 || lb.Servers[0].URL != "http://oi-loja-1:80" || lb.Sticky != nil {
+		t.Errorf("loadBalancer inesperado: %+v", lb)
+	}
+
+	// Não sobra arquivo temporário no diretório observado
+	entries, _ := os.ReadDir(m.Dir())
+	if len(entries) != 1 {
+		t.Errorf("esperado só o arquivo da rota, encontrados %d arquivos", len(entries))
+	}
+}
+
+func TestAddRouteIPHashUsesStickyCookie(t *testing.T) {
+	m := NewManager("", t.TempDir(), nil, "")
+	r := route("oi-loja", "loja.com", "a:80", "b:80")
+	r.Policy = domain.PolicyIPHash
+
+	if err := m.AddRoute(context.Background(), r); err != nil {
+		t.Fatalf("AddRoute: %v", err)
+	}
+	cfg := readRoute(t, m, "oi-loja")
+	if lb := cfg.HTTP.Services["oi-loja"].LoadBalancer; lb == nil || lb.Sticky == nil || lb.Sticky.Cookie["name"] != "oi-loja" {
+		t.Errorf("esperada afinidade por cookie, obtido %+v", lb)
+	}
+	if cfg.HTTP.Routers["oi-loja"].TLS != nil {
+		t.Error("TLS não deveria ser configurado sem certResolver")
+	}
+}
+
+func TestAddRouteWeightedCreatesServicePerUpstream(t *testing.T) {
+	m := NewManager("", t.TempDir(), nil, "")
+	r := route("oi-loja", "loja.com", "estavel:80", "canario:80")
+	r.Upstreams[0].Weight = 90
+	r.Upstreams[1].Weight = 10
+
+	if err := m.AddRoute(context.Background(), r); err != nil {
+		t.Fatalf("AddRoute: %v", err)
+	}
+	cfg := readRoute(t, m, "oi-loja")
+	split := cfg.HTTP.Services["oi-loja"].Weighted
+	if split == nil || len(split.Services) != 2 {
+		t.Fatalf("esperado serviço weighted com 2 filhos, obtido %+v", cfg.HTTP.Services["oi-loja"])
+	}
+	if split.Services[0] != (weightedService{Name: "oi-loja-1", Weight: 90}) || split.Services[1] != (weightedService{Name: "oi-loja-2", Weight: 10}) {
+		t.Errorf("pesos inesperados: %+v", split.Services)
+	}
+
+	upstreams, err := m.GetUpstreams(context.Background(), "loja.com")
+	if err != nil {
+		t.Fatalf("GetUpstreams: %v", err)
+	}
+	if !reflect.DeepEqual(upstreams, []string{"estavel:80", "canario:80"}) {
+		t.Errorf("upstreams inesperados: %v", upstreams)
+	}
+}
+
+func TestAddRouteAliasesPathPrefixAndWildcard(t *testing.T) {
+	m := NewManager("", t.TempDir(), nil, "")
+	ctx := context.Background()
+
+	site := route("oi-loja", "loja.com", "site:80")
+	site.Aliases = []string{"www.loja.com"}
+	api := route("oi-api", "loja.com", "api:80")
+	api.PathPrefix = "/v2"
+	api.StripPrefix = true
+	tenants := route("oi-tenants", "*.loja.com", "tenants:80")
+	for _, r := range []domain.Route{site, api, tenants} {
+		if err := m.AddRoute(ctx, r); err != nil {
+			t.Fatalf("AddRoute %s: %v", r.ID, err)
+		}
+	}
+
+	siteRouter := readRoute(t, m, "oi-loja").HTTP.Routers["oi-loja"]
+	if siteRouter.Rule != "Host(`loja.com`) || Host(`www.loja.com`)" || len(siteRouter.Middlewares) != 0 {
+		t.Errorf("router com aliases inesperado: %+v", siteRouter)
+	}
+
+	cfg := readRoute(t, m, "oi-api")
+	router := cfg.HTTP.Routers["oi-api"]
+	if router.Rule != "Host(`loja.com`) && (Path(`/v2`) || PathPrefix(`/v2/`))" {
+		t.Errorf("regra com prefixo inesperada: %s", router.Rule)
+	}
+	if !reflect.DeepEqual(router.Middlewares, []string{"oi-api-strip"}) {
+		t.Errorf("middlewares = %v", router.Middlewares)
+	}
+	if strip := cfg.HTTP.Middlewares["oi-api-strip"].StripPrefix; strip == nil || !reflect.DeepEqual(strip.Prefixes, []string{"/v2"}) {
+		t.Errorf("middleware stripPrefix inesperado: %+v", cfg.HTTP.Middlewares)
+	}
+
+	wildcard := readRoute(t, m, "oi-tenants").HTTP.Routers["oi-tenants"]
+	if wildcard.Rule != "HostRegexp(`^[^.]+\\.loja\\.com$`)" {
+		t.Errorf("regra curinga inesperada: %s", wildcard.Rule)
+	}
+
+	// Prefixo mais longo vence, e no mesmo prefixo o host exato vence o curinga
+	if !(router.Priority > siteRouter.Priority && siteRouter.Priority > wildcard.Priority) {
+		t.Errorf("prioridades fora de ordem: api=%d loja=%d curinga=%d", router.Priority, siteRouter.Priority, wildcard.Priority)
+	}
+
+	for site, want := range map[string][]string{
+		"www.loja.com": {"site:80"},
+		"loja.com/v2":  {"api:80"},
+		"*.loja.com":   {"tenants:80"},
+	} {
+		if upstreams, err := m.GetUpstreams(ctx, site); err != nil || !reflect.DeepEqual(upstreams, want) {
+			t.Errorf("GetUpstreams(%s) = %v, %v; esperado %v", site, upstreams, err, want)
+		}
+	}
+}
+
+func TestAddRouteRequiresID(t *testing.T) {
+	m := NewManager("", t.TempDir(), nil, "")
+	if err := m.AddRoute(context.Background(), route("", "loja.com", "a:80")); err == nil {
+		t.Fatal("esperado erro para rota sem ID")
+	}
+}
+
+func TestAddRouteReplacesConflictingFiles(t *testing.T) {
+	m := NewManager("", t.TempDir(), nil, "")
+	ctx := context.Background()
+
+	if err := m.AddRoute(ctx, route("oi-antigo", "loja.com", "antigo:80")); err != nil {
+		t.Fatalf("AddRoute: %v", err)
+	}
+	if err := m.AddRoute(ctx, route("oi-blog", "blog.com", "blog:80")); err != nil {
+		t.Fatalf("AddRoute: %v", err)
+	}
+	// Arquivo de outra origem no mesmo diretório não é tocado
+	other := filepath.Join(m.Dir(), "manual.yml")
+	if err := os.WriteFile(other, []byte("http: {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.AddRoute(ctx, route("oi-loja", "loja.com", "novo:80")); err != nil {
+		t.Fatalf("AddRoute: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(m.Dir(), "oi-antigo"+fileExt)); !os.IsNotExist(err) {
+		t.Error("rota antiga para o mesmo domínio deveria ter sido removida")
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("arquivo de outra origem removido: %v", err)
+	}
+	if upstreams, _ := m.GetUpstreams(ctx, "blog.com"); !reflect.DeepEqual(upstreams, []string{"blog:80"}) {
+		t.Errorf("rota de outro domínio alterada: %v", upstreams)
+	}
+	if upstreams, _ := m.GetUpstreams(ctx, "loja.com"); !reflect.DeepEqual(upstreams, []string{"novo:80"}) {
+		t.Errorf("upstreams inesperados: %v", upstreams)
+	}
+}
+
+func TestRemoveRoute(t *testing.T) {
+	m := NewManager("", t.TempDir(), nil, "")
+	ctx := context.Background()
+
+	if err := m.AddRoute(ctx, route("oi-loja", "loja.com", "a:80")); err != nil {
+		t.Fatalf("AddRoute: %v", err)
+	}
+	if ok, err := m.HasRoute(ctx, "loja.com"); err != nil || !ok {
+		t.Fatalf("HasRoute = %v, %v; esperado true", ok, err)
+	}
+
+	if err := m.RemoveRoute(ctx, route("oi-loja", "loja.com")); err != nil {
+		t.Fatalf("RemoveRoute: %v", err)
+	}
+	if ok, err := m.HasRoute(ctx, "loja.com"); err != nil || ok {
+		t.Errorf("HasRoute = %v, %v; esperado false", ok, err)
+	}
+
+	// Remover de novo (ou com o diretório inexistente) não é erro
+	if err := m.RemoveRoute(ctx, route("oi-loja", "loja.com")); err != nil {
+		t.Errorf("RemoveRoute repetido: %v", err)
+	}
+	missing := NewManager("", filepath.Join(t.TempDir(), "nao-existe"), nil, "")
+	if err := missing.RemoveRoute(ctx, route("oi-loja", "loja.com")); err != nil {
+		t.Errorf("RemoveRoute sem diretório: %v", err)
+	}
+}
+
+func TestGetUpstreamsInvalidFile(t *testing.T) {
+	m := NewManager("", t.TempDir(), nil, "")
+	if err := os.WriteFile(filepath.Join(m.Dir(), "oi-quebrado"+fileExt), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.GetUpstreams(context.Background(), "loja.com"); err == nil {
+		t.Error("esperado erro para arquivo de rota inválido")
+	}
+}
+
+func TestHealth(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{name: "file provider ativo", status: http.StatusOK, body: `{"providers":["Docker","File"]}`},
+		{name: "sem file provider", status: http.StatusOK, body: `{"providers":["Docker"]}`, wantErr: "file provider"},
+		{name: "API desabilitada", status: http.StatusNotFound, body: `404 page not found`, wantErr: "status 404"},
+		{name: "resposta inválida", status: http.StatusOK, body: `<html>`, wantErr: "resposta inválida"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/overview" {
+					http.NotFound(w, r)
+					return
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			err := NewManager(srv.URL+"/", t.TempDir(), nil, "").Health(context.Background())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Health: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Health = %v; esperado erro contendo %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	if err := NewManager(srv.URL, t.TempDir(), nil, "").Health(context.Background()); err == nil {
+		t.Error("esperado erro com o Traefik fora do ar")
+	}
+}
@@ -0,0 +1,37 @@
+// Package atomicfile grava arquivos sem nunca expor um conteúdo pela metade
+// Usado para o estado do OI, o oi.json, o config.json e os arquivos lidos pelos proxies
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Write grava data em path via arquivo temporário no mesmo diretório + rename
+// Leitores concorrentes (um proxy recarregando, outro processo do OI) veem o conteúdo
+// antigo ou o novo, nunca parte dele. O temporário começa com "." e termina em ".tmp",
+// fora dos padrões incluídos pelo nginx e pelo file provider do Traefik
+func Write(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
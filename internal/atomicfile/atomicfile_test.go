@@ -0,0 +1,46 @@
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "oi-loja.conf")
+
+	if err := os.WriteFile(path, []byte("antigo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Write(path, []byte("novo"), 0600); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "novo" {
+		t.Errorf("conteúdo = %q, %v; esperado \"novo\"", data, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("permissão = %v; esperado 0600", perm)
+	}
+
+	// O temporário não sobra no diretório
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("arquivos no diretório = %d; esperado só o gravado", len(entries))
+	}
+}
+
+func TestWriteMissingDir(t *testing.T) {
+	if err := Write(filepath.Join(t.TempDir(), "nao-existe", "active"), []byte("v1"), 0600); err == nil {
+		t.Error("esperado erro sem o diretório")
+	}
+}
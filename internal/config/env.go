@@ -0,0 +1,110 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// resolveEnvFiles carrega os arquivos env_file da intenção e os mescla no ambiente
+// Caminhos relativos são resolvidos a partir do diretório do oi.json
+// Variáveis declaradas diretamente na intenção têm prioridade sobre as dos arquivos
+func resolveEnvFiles(intent *domain.Intent, baseDir string) error {
+	// Cada serviço tem seus próprios env_file
+	for name, svc := range intent.Servicos {
+		if err := resolveEnvFiles(&svc, baseDir); err != nil {
+			return err
+		}
+		intent.Servicos[name] = svc
+	}
+
+	if len(intent.EnvFile) == 0 {
+		return nil
+	}
+
+	merged := make(map[string]string)
+	for _, file := range intent.EnvFile {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(baseDir, file)
+		}
+
+		vars, err := LoadEnvFile(file)
+		if err != nil {
+			return err
+		}
+		// Arquivos posteriores sobrescrevem os anteriores
+		for k, v := range vars {
+			merged[k] = v
+		}
+	}
+
+	for k, v := range intent.Ambiente {
+		merged[k] = v
+	}
+	intent.Ambiente = merged
+
+	return nil
+}
+
+// LoadEnvFile lê um arquivo no formato .env (KEY=VALUE por linha)
+// Suporta comentários (#), linhas em branco, prefixo "export" e valores entre aspas
+func LoadEnvFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler env_file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	vars := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("erro em %s:%d: esperado KEY=VALUE", path, lineNum)
+		}
+
+		vars[key] = parseEnvValue(strings.TrimSpace(value))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler env_file %s: %w", path, err)
+	}
+
+	return vars, nil
+}
+
+// parseEnvValue remove aspas e comentários inline de um valor .env
+func parseEnvValue(value string) string {
+	if len(value) >= 2 {
+		switch value[0] {
+		case '"':
+			if end := strings.LastIndex(value, `"`); end > 0 {
+				inner := value[1:end]
+				inner = strings.ReplaceAll(inner, `\n`, "\n")
+				return strings.ReplaceAll(inner, `\"`, `"`)
+			}
+		case '\'':
+			if end := strings.LastIndex(value, "'"); end > 0 {
+				return value[1:end]
+			}
+		}
+	}
+
+	// Comentário inline só é válido se precedido de espaço (ex: VALUE # comentário)
+	if idx := strings.Index(value, " #"); idx >= 0 {
+		value = strings.TrimSpace(value[:idx])
+	}
+	return value
+}
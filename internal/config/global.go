@@ -0,0 +1,211 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// GlobalFileName é o nome do arquivo de configuração global do OI (em ~/.oi)
+const GlobalFileName = "config.json"
+
+// Variáveis de ambiente que sobrescrevem a configuração global
+const (
+	EnvCaddyAdmin     = "OI_CADDY_ADMIN"
+	EnvCaddyServer    = "OI_CADDY_SERVER"
+	EnvCaddyAccessLog = "OI_CADDY_ACCESS_LOG"
+	EnvRuntime        = "OI_RUNTIME"
+	EnvRuntimeHost    = "OI_RUNTIME_HOST"
+	EnvProxy          = "OI_PROXY"
+	EnvTraefikAPI     = "OI_TRAEFIK_API"
+	EnvTraefikDir     = "OI_TRAEFIK_DIR"
+	EnvNginxDir       = "OI_NGINX_DIR"
+	EnvNginxBinary    = "OI_NGINX_BIN"
+	EnvProxySocket    = "OI_PROXY_SOCKET"
+)
+
+// Runtimes de containers suportados
+const (
+	RuntimeDocker = "docker"
+	RuntimePodman = "podman"
+)
+
+// Proxies reversos suportados
+const (
+	ProxyCaddy   = "caddy"
+	ProxyTraefik = "traefik"
+	ProxyNginx   = "nginx"
+	ProxyBuiltin = "builtin"
+)
+
+// GlobalConfig é a configuração da máquina, compartilhada por todos os projetos
+// Diferente do oi.json, descreve o ambiente do servidor e não a intenção de um projeto
+type GlobalConfig struct {
+	Runtime RuntimeConfig `json:"runtime,omitempty"`
+	// Proxy é o proxy reverso usado: "caddy" (padrão), "traefik", "nginx" ou "builtin"
+	Proxy   string             `json:"proxy,omitempty"`
+	Caddy   CaddyConfig        `json:"caddy,omitempty"`
+	Traefik TraefikConfig      `json:"traefik,omitempty"`
+	Nginx   NginxConfig        `json:"nginx,omitempty"`
+	Builtin BuiltinProxyConfig `json:"builtin,omitempty"`
+	// Registry guarda credenciais por registry (ex: "ghcr.io"), com prioridade
+	// sobre as do ~/.docker/config.json
+	Registry map[string]RegistryAuth `json:"registry,omitempty"`
+}
+
+// RegistryAuth são as credenciais de um registry privado
+type RegistryAuth struct {
+	Username string `json:"username"`
+	// Password aceita senha ou token de acesso (ex: PAT do GHCR)
+	Password string `json:"password,omitempty"`
+	// PasswordEnv lê a senha de uma variável de ambiente, para não gravá-la no arquivo
+	PasswordEnv string `json:"password_env,omitempty"`
+}
+
+// Secret retorna a senha declarada ou lida de PasswordEnv
+func (r RegistryAuth) Secret() string {
+	if r.PasswordEnv != "" {
+		if v := os.Getenv(r.PasswordEnv); v != "" {
+			return v
+		}
+	}
+	return r.Password
+}
+
+// RuntimeConfig define o runtime de containers usado pelo OI
+type RuntimeConfig struct {
+	// Engine é "docker" (padrão) ou "podman"
+	Engine string `json:"engine,omitempty"`
+	// Host é o socket do runtime (ex: "unix:///run/user/1000/podman/podman.sock")
+	// Vazio: DOCKER_HOST para o Docker; CONTAINER_HOST ou o socket padrão para o Podman
+	Host string `json:"host,omitempty"`
+}
+
+// EngineOrDefault retorna o runtime configurado (padrão docker)
+func (r RuntimeConfig) EngineOrDefault() string {
+	if r.Engine == "" {
+		return RuntimeDocker
+	}
+	return r.Engine
+}
+
+// CaddyConfig define como o OI acessa o Caddy
+type CaddyConfig struct {
+	// AdminURL é o endereço da Admin API (padrão http://localhost:2019)
+	AdminURL string `json:"admin_url,omitempty"`
+	// Server é o nome do servidor HTTP gerenciado pelo OI (padrão srv0)
+	Server string `json:"server,omitempty"`
+	// AccessLog é o arquivo de access log lido na análise de canários
+	// (padrão /var/log/caddy/oi-access.log; o Caddy precisa rodar na mesma máquina)
+	AccessLog string `json:"access_log,omitempty"`
+}
+
+// ProxyOrDefault retorna o proxy configurado (padrão caddy)
+func (c *GlobalConfig) ProxyOrDefault() string {
+	if c.Proxy == "" {
+		return ProxyCaddy
+	}
+	return c.Proxy
+}
+
+// TraefikConfig define como o OI acessa o Traefik
+type TraefikConfig struct {
+	// APIURL é o endereço da API do Traefik, usada no health check (padrão http://localhost:8080)
+	APIURL string `json:"api_url,omitempty"`
+	// Dir é o diretório observado pelo file provider (padrão /etc/traefik/dynamic)
+	Dir string `json:"dir,omitempty"`
+	// EntryPoints restringe os routers criados a esses entrypoints (ex: ["websecure"])
+	EntryPoints []string `json:"entrypoints,omitempty"`
+	// CertResolver habilita TLS nos routers com o resolver ACME informado (ex: "le")
+	CertResolver string `json:"cert_resolver,omitempty"`
+}
+
+// NginxConfig define onde o OI grava os server blocks e como recarrega o nginx
+type NginxConfig struct {
+	// Dir é o diretório incluído no bloco http do nginx.conf (padrão /etc/nginx/oi.d)
+	Dir string `json:"dir,omitempty"`
+	// Binary é o executável usado em "nginx -t" e "nginx -s reload" (padrão nginx, no PATH)
+	Binary string `json:"binary,omitempty"`
+	// PidFile é o pid file do processo master, usado sem o executável (padrão /run/nginx.pid)
+	PidFile string `json:"pid_file,omitempty"`
+}
+
+// BuiltinProxyConfig define o proxy embutido ("oi proxy serve")
+type BuiltinProxyConfig struct {
+	// Socket é o socket unix da admin API (padrão /run/oi/proxy.sock)
+	Socket string `json:"socket,omitempty"`
+	// Listen é o endereço do tráfego HTTP (padrão :80)
+	Listen string `json:"listen,omitempty"`
+	// State é o arquivo onde as rotas sobrevivem a reinícios (padrão /var/lib/oi/proxy-routes.json)
+	State string `json:"state,omitempty"`
+}
+
+// GlobalConfigPath retorna o caminho do arquivo de configuração global (~/.oi/config.json)
+func GlobalConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("falha ao localizar diretório home: %w", err)
+	}
+	return filepath.Join(home, ".oi", GlobalFileName), nil
+}
+
+// LoadGlobalConfig carrega ~/.oi/config.json e aplica as variáveis de ambiente
+// O arquivo é opcional: sem ele, apenas o ambiente (e os padrões dos adapters) valem
+func LoadGlobalConfig() (*GlobalConfig, error) {
+	cfg := &GlobalConfig{}
+
+	path, err := GlobalConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		// Sem arquivo, segue com os padrões
+	case err != nil:
+		return nil, fmt.Errorf("erro ao ler %s: %w", path, err)
+	default:
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("erro ao parsear %s: %w", path, err)
+		}
+	}
+
+	if v := os.Getenv(EnvRuntime); v != "" {
+		cfg.Runtime.Engine = v
+	}
+	if v := os.Getenv(EnvRuntimeHost); v != "" {
+		cfg.Runtime.Host = v
+	}
+	if v := os.Getenv(EnvProxy); v != "" {
+		cfg.Proxy = v
+	}
+	if v := os.Getenv(EnvTraefikAPI); v != "" {
+		cfg.Traefik.APIURL = v
+	}
+	if v := os.Getenv(EnvTraefikDir); v != "" {
+		cfg.Traefik.Dir = v
+	}
+	if v := os.Getenv(EnvNginxDir); v != "" {
+		cfg.Nginx.Dir = v
+	}
+	if v := os.Getenv(EnvNginxBinary); v != "" {
+		cfg.Nginx.Binary = v
+	}
+	if v := os.Getenv(EnvProxySocket); v != "" {
+		cfg.Builtin.Socket = v
+	}
+	if v := os.Getenv(EnvCaddyAdmin); v != "" {
+		cfg.Caddy.AdminURL = v
+	}
+	if v := os.Getenv(EnvCaddyServer); v != "" {
+		cfg.Caddy.Server = v
+	}
+	if v := os.Getenv(EnvCaddyAccessLog); v != "" {
+		cfg.Caddy.AccessLog = v
+	}
+
+	return cfg, nil
+}
@@ -40,6 +40,19 @@ func LoadIntent(path string) (*domain.Intent, error) {
 
 	// Normaliza campos (Inglês -> Português)
 	intent.Normalize()
+	if abs, err := filepath.Abs(path); err == nil {
+		intent.Source = abs
+	} else {
+		intent.Source = path
+	}
+
+	// Carrega arquivos .env relativos ao oi.json
+	if err := resolveEnvFiles(&intent, filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+
+	// Contextos de build relativos ao oi.json
+	resolveBuildContext(&intent, filepath.Dir(path))
 
 	// Valida campos obrigatórios
 	if err := intent.Validate(); err != nil {
@@ -49,6 +62,25 @@ func LoadIntent(path string) (*domain.Intent, error) {
 	return &intent, nil
 }
 
+// resolveBuildContext converte o contexto de build em caminho absoluto
+// Caminhos relativos são resolvidos a partir do diretório do oi.json
+func resolveBuildContext(intent *domain.Intent, baseDir string) {
+	for name, svc := range intent.Servicos {
+		resolveBuildContext(&svc, baseDir)
+		intent.Servicos[name] = svc
+	}
+
+	if intent.Build == nil {
+		return
+	}
+	if !filepath.IsAbs(intent.Build.Context) {
+		intent.Build.Context = filepath.Join(baseDir, intent.Build.Context)
+	}
+	if abs, err := filepath.Abs(intent.Build.Context); err == nil {
+		intent.Build.Context = abs
+	}
+}
+
 // SaveIntent salva uma intenção em um arquivo oi.json
 func SaveIntent(path string, intent *domain.Intent) error {
 	data, err := json.MarshalIndent(intent, "", "  ")
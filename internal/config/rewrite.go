@@ -0,0 +1,43 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/crom-tech/oi/internal/atomicfile"
+)
+
+// RewriteImage troca a imagem 'from' por 'to' no oi.json, preservando a formatação
+// Usado pelo auto_update (patch/minor) para que o arquivo continue sendo a fonte da
+// verdade: um 'oi up' manual depois da atualização não volta para a tag antiga
+// A imagem precisa aparecer exatamente uma vez no arquivo
+func RewriteImage(path, from, to string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("erro ao ler %s: %w", path, err)
+	}
+
+	oldValue, _ := json.Marshal(from)
+	newValue, _ := json.Marshal(to)
+	switch n := bytes.Count(data, oldValue); n {
+	case 1:
+	case 0:
+		return fmt.Errorf("imagem %s não encontrada em %s", from, path)
+	default:
+		return fmt.Errorf("imagem %s aparece %d vezes em %s, atualize manualmente", from, n, path)
+	}
+	updated := bytes.Replace(data, oldValue, newValue, 1)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("erro ao acessar %s: %w", path, err)
+	}
+
+	// Grava de forma atômica para nunca deixar o oi.json pela metade
+	if err := atomicfile.Write(path, updated, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("erro ao salvar %s: %w", path, err)
+	}
+	return nil
+}
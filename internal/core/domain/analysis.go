@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Valores padrão da análise de canário
+const (
+	defaultAnalysisWindow   = 5 * time.Minute
+	defaultAnalysisInterval = 30 * time.Second
+	defaultMinRequests      = 20
+)
+
+// CanaryAnalysis define a análise automática de um canário declarada no oi.json
+// Durante a janela, o orquestrador compara o tráfego do canário com os limites:
+// se algum for ultrapassado, o canário é abortado; ao fim da janela, é promovido
+type CanaryAnalysis struct {
+	// Window é o tempo de observação antes de promover (ex: "5m")
+	Window string `json:"window,omitempty"`
+	// Interval é o intervalo entre as verificações (ex: "30s")
+	Interval string `json:"interval,omitempty"`
+	// MaxErrorRate é a fração máxima de respostas 5xx do canário (ex: 0.05 = 5%)
+	MaxErrorRate *float64 `json:"max_error_rate,omitempty"`
+	// MaxP95 é a latência p95 máxima do canário (ex: "500ms")
+	MaxP95 string `json:"max_p95,omitempty"`
+	// MinRequests é o mínimo de requisições ao canário para uma decisão (padrão 20)
+	MinRequests int `json:"min_requests,omitempty"`
+}
+
+// Validate verifica se os campos da análise são válidos
+func (a *CanaryAnalysis) Validate(prefix string) error {
+	if a.MaxErrorRate != nil && (*a.MaxErrorRate < 0 || *a.MaxErrorRate > 1) {
+		return ErrInvalidField(prefix+"canario.max_error_rate", "deve estar entre 0 e 1 (ex: 0.05)")
+	}
+	if a.MinRequests < 0 {
+		return ErrInvalidField(prefix+"canario.min_requests", "não pode ser negativo")
+	}
+	durations := map[string]string{
+		prefix + "canario.window":   a.Window,
+		prefix + "canario.interval": a.Interval,
+		prefix + "canario.max_p95":  a.MaxP95,
+	}
+	for field, value := range durations {
+		if value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+			return ErrInvalidField(field, "duração inválida (ex: \"5m\")")
+		}
+	}
+	if a.MaxErrorRate == nil && a.MaxP95 == "" {
+		return ErrInvalidField(prefix+"canario", "declare max_error_rate e/ou max_p95")
+	}
+	return nil
+}
+
+// WindowDuration retorna o tempo de observação
+func (a *CanaryAnalysis) WindowDuration() time.Duration {
+	return parseDurationOr(a.Window, defaultAnalysisWindow)
+}
+
+// IntervalDuration retorna o intervalo entre verificações
+func (a *CanaryAnalysis) IntervalDuration() time.Duration {
+	return parseDurationOr(a.Interval, defaultAnalysisInterval)
+}
+
+// MaxP95Duration retorna o limite de latência p95 (0 se não declarado)
+func (a *CanaryAnalysis) MaxP95Duration() time.Duration {
+	return parseDurationOr(a.MaxP95, 0)
+}
+
+// MinRequestsOrDefault retorna o mínimo de requisições para uma decisão
+func (a *CanaryAnalysis) MinRequestsOrDefault() int {
+	if a.MinRequests == 0 {
+		return defaultMinRequests
+	}
+	return a.MinRequests
+}
+
+// Check compara as métricas do canário com os limites
+// Retorna o motivo da reprovação ou vazio se estiver dentro dos limites
+func (a *CanaryAnalysis) Check(stats TrafficStats) string {
+	if a.MaxErrorRate != nil && stats.ErrorRate() > *a.MaxErrorRate {
+		return fmt.Sprintf("taxa de 5xx %.1f%% acima do limite de %.1f%%", stats.ErrorRate()*100, *a.MaxErrorRate*100)
+	}
+	if limit := a.MaxP95Duration(); limit > 0 && stats.P95 > limit {
+		return fmt.Sprintf("latência p95 %s acima do limite de %s", stats.P95, limit)
+	}
+	return ""
+}
+
+// TrafficStats resume as requisições atendidas por um conjunto de upstreams
+type TrafficStats struct {
+	Requests int
+	// Errors é o número de respostas 5xx
+	Errors int
+	P95    time.Duration
+}
+
+// ErrorRate retorna a fração de respostas 5xx (0 sem requisições)
+func (s TrafficStats) ErrorRate() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Requests)
+}
+
+// String formata as métricas para a saída do deploy
+func (s TrafficStats) String() string {
+	return fmt.Sprintf("%d req, 5xx %.1f%%, p95 %s", s.Requests, s.ErrorRate()*100, s.P95)
+}
+
+// NewTrafficStats calcula as métricas a partir dos status e latências de cada requisição
+func NewTrafficStats(statuses []int, latencies []time.Duration) TrafficStats {
+	stats := TrafficStats{Requests: len(statuses)}
+	for _, status := range statuses {
+		if status >= 500 {
+			stats.Errors++
+		}
+	}
+	if len(latencies) > 0 {
+		sorted := append([]time.Duration(nil), latencies...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		// Percentil pelo método nearest-rank
+		rank := (len(sorted)*95 + 99) / 100
+		stats.P95 = sorted[rank-1].Round(time.Microsecond)
+	}
+	return stats
+}
@@ -0,0 +1,204 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Políticas de atualização automática de imagem (auto_update)
+const (
+	// AutoUpdateTag reimplanta quando a mesma tag passa a apontar para outro digest
+	AutoUpdateTag = "tag"
+	// AutoUpdatePatch também avança para a maior versão de patch (1.25.3 -> 1.25.4)
+	AutoUpdatePatch = "patch"
+	// AutoUpdateMinor também avança para a maior versão minor (1.25.3 -> 1.27.0)
+	AutoUpdateMinor = "minor"
+)
+
+// AutoUpdate define se e como 'oi watch-images' atualiza a imagem do serviço
+// Aceita uma política ("tag", "patch", "minor"), um intervalo ("6h", "@daily",
+// "@every 30m", que implica a política "tag") ou o objeto completo:
+//
+//	"auto_update": {"policy": "patch", "interval": "1h"}
+type AutoUpdate struct {
+	Policy string `json:"policy"`
+	// Interval é o intervalo mínimo entre verificações (padrão: o do watcher)
+	Interval string `json:"interval,omitempty"`
+}
+
+// UnmarshalJSON aceita a forma curta (string) ou o objeto completo
+func (a *AutoUpdate) UnmarshalJSON(data []byte) error {
+	var short string
+	if err := json.Unmarshal(data, &short); err == nil {
+		switch short {
+		case AutoUpdateTag, AutoUpdatePatch, AutoUpdateMinor:
+			*a = AutoUpdate{Policy: short}
+		default:
+			if _, ok := parseInterval(short); ok {
+				// Intervalo: a política é "tag"
+				*a = AutoUpdate{Policy: AutoUpdateTag, Interval: short}
+			} else {
+				// Valor desconhecido: falha na validação
+				*a = AutoUpdate{Policy: short}
+			}
+		}
+		return nil
+	}
+
+	type plain AutoUpdate
+	var full plain
+	if err := json.Unmarshal(data, &full); err != nil {
+		return fmt.Errorf("auto_update deve ser uma política, um intervalo ou um objeto: %w", err)
+	}
+	*a = AutoUpdate(full)
+	return nil
+}
+
+// Validate verifica a política e o intervalo
+// image é a 'origem' do serviço: patch e minor exigem uma tag de versão (ex: 1.25.3)
+func (a *AutoUpdate) Validate(prefix string, image string) error {
+	switch a.Policy {
+	case AutoUpdateTag:
+	case AutoUpdatePatch, AutoUpdateMinor:
+		_, tag := SplitImageTag(image)
+		if _, ok := parseTagVersion(tag); !ok {
+			return ErrInvalidField(prefix+"auto_update", fmt.Sprintf("política %s exige uma tag de versão em 'origem' (ex: nginx:1.25.3)", a.Policy))
+		}
+	default:
+		return ErrInvalidField(prefix+"auto_update", "use tag, patch, minor ou um intervalo (ex: \"6h\", \"@daily\")")
+	}
+	if a.Interval != "" {
+		if _, ok := parseInterval(a.Interval); !ok {
+			return ErrInvalidField(prefix+"auto_update.interval", "intervalo inválido (ex: \"30m\", \"@hourly\", \"@every 6h\")")
+		}
+	}
+	if strings.Contains(image, "@") {
+		return ErrInvalidField(prefix+"auto_update", "'origem' fixada por digest não pode ser atualizada")
+	}
+	return nil
+}
+
+// IntervalOr retorna o intervalo entre verificações (fallback se não declarado)
+func (a *AutoUpdate) IntervalOr(fallback time.Duration) time.Duration {
+	if d, ok := parseInterval(a.Interval); ok {
+		return d
+	}
+	return fallback
+}
+
+// parseInterval interpreta durações Go e os atalhos estilo cron
+func parseInterval(value string) (time.Duration, bool) {
+	switch value {
+	case "@hourly":
+		return time.Hour, true
+	case "@daily":
+		return 24 * time.Hour, true
+	case "@weekly":
+		return 7 * 24 * time.Hour, true
+	}
+	value = strings.TrimPrefix(value, "@every ")
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, false
+	}
+	return d, true
+}
+
+// ImageUpdate é uma atualização de imagem encontrada para um serviço
+type ImageUpdate struct {
+	Project string
+	Service string
+	Policy  string
+	// From é a imagem em uso; To é a imagem a implantar (igual a From se só o digest mudou)
+	From string
+	To   string
+	// Running e Latest são os digests implantado e disponível
+	Running string
+	Latest  string
+}
+
+// TagChanged indica que a atualização troca a tag (patch/minor), não só o digest
+func (u ImageUpdate) TagChanged() bool {
+	return u.From != u.To
+}
+
+// SplitImageTag separa repositório e tag ("nginx:1.25" -> "nginx", "1.25")
+// Sem tag, retorna "latest"; referências por digest retornam tag vazia
+func SplitImageTag(image string) (string, string) {
+	if strings.Contains(image, "@") {
+		return image, ""
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], image[i+1:]
+	}
+	return image, "latest"
+}
+
+// tagVersion é uma tag no formato [v]MAJOR.MINOR[.PATCH][sufixo] (ex: "1.25.3-alpine")
+type tagVersion struct {
+	prefix              string
+	major, minor, patch int
+	// hasPatch diferencia "1.25" de "1.25.0": só tags do mesmo formato são comparadas
+	hasPatch bool
+	suffix   string
+}
+
+var tagVersionPattern = regexp.MustCompile(`^(v?)(\d+)\.(\d+)(?:\.(\d+))?(-[0-9A-Za-z.-]+)?$`)
+
+func parseTagVersion(tag string) (tagVersion, bool) {
+	m := tagVersionPattern.FindStringSubmatch(tag)
+	if m == nil {
+		return tagVersion{}, false
+	}
+	v := tagVersion{prefix: m[1], suffix: m[5], hasPatch: m[4] != ""}
+	v.major, _ = strconv.Atoi(m[2])
+	v.minor, _ = strconv.Atoi(m[3])
+	if v.hasPatch {
+		v.patch, _ = strconv.Atoi(m[4])
+	}
+	return v, true
+}
+
+// sameFamily indica tags comparáveis: mesmo prefixo, sufixo (ex: -alpine) e formato
+func (v tagVersion) sameFamily(o tagVersion) bool {
+	return v.prefix == o.prefix && v.suffix == o.suffix && v.hasPatch == o.hasPatch
+}
+
+func (v tagVersion) less(o tagVersion) bool {
+	if v.major != o.major {
+		return v.major < o.major
+	}
+	if v.minor != o.minor {
+		return v.minor < o.minor
+	}
+	return v.patch < o.patch
+}
+
+// NextTag escolhe a maior tag permitida pela política a partir da tag atual
+// patch mantém major.minor; minor mantém major. Retorna vazio se não houver tag maior
+// Pré-releases (sufixos como -rc1) só são considerados se a tag atual tiver o mesmo sufixo
+func NextTag(current string, tags []string, policy string) string {
+	base, ok := parseTagVersion(current)
+	if !ok || (policy != AutoUpdatePatch && policy != AutoUpdateMinor) {
+		return ""
+	}
+
+	best, bestTag := base, ""
+	for _, tag := range tags {
+		v, ok := parseTagVersion(tag)
+		if !ok || !v.sameFamily(base) || v.major != base.major {
+			continue
+		}
+		if policy == AutoUpdatePatch && (v.minor != base.minor || !base.hasPatch) {
+			continue
+		}
+		if best.less(v) {
+			best, bestTag = v, tag
+		}
+	}
+	return bestTag
+}
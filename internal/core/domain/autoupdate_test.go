@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{value: "@hourly", want: time.Hour, ok: true},
+		{value: "@daily", want: 24 * time.Hour, ok: true},
+		{value: "@weekly", want: 7 * 24 * time.Hour, ok: true},
+		{value: "@every 30m", want: 30 * time.Minute, ok: true},
+		{value: "@every 1h30m", want: 90 * time.Minute, ok: true},
+		{value: "6h", want: 6 * time.Hour, ok: true},
+		{value: "@every"},
+		{value: "@every 0s"},
+		{value: "@every -5m"},
+		{value: "-1h"},
+		{value: "0"},
+		{value: "@monthly"},
+		{value: "daily"},
+		{value: "@every dia"},
+		{value: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := parseInterval(tt.value)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("parseInterval(%q) = %v, %v; esperado %v, %v", tt.value, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestNextTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		tags    []string
+		policy  string
+		want    string
+	}{
+		{
+			name:    "patch compara números, não texto",
+			current: "1.25.3",
+			tags:    []string{"1.25.4", "1.25.10", "1.25.9", "1.26.0", "2.0.0", "latest"},
+			policy:  AutoUpdatePatch,
+			want:    "1.25.10",
+		},
+		{
+			name:    "minor mantém o major",
+			current: "1.25.3",
+			tags:    []string{"1.25.4", "1.26.0", "1.27.1", "1.27.0", "2.0.0"},
+			policy:  AutoUpdateMinor,
+			want:    "1.27.1",
+		},
+		{
+			name:    "sem tag maior",
+			current: "1.25.3",
+			tags:    []string{"1.25.3", "1.25.2", "1.24.9", "2.0.0"},
+			policy:  AutoUpdatePatch,
+		},
+		{
+			name:    "major nunca muda",
+			current: "1.25.3",
+			tags:    []string{"2.0.0", "3.1.4"},
+			policy:  AutoUpdateMinor,
+		},
+		{
+			name:    "política tag não troca a tag",
+			current: "1.25.3",
+			tags:    []string{"1.25.4"},
+			policy:  AutoUpdateTag,
+		},
+		{
+			name:    "pré-release ignorado a partir de tag estável",
+			current: "1.25.3",
+			tags:    []string{"1.25.5-rc1", "1.25.4"},
+			policy:  AutoUpdatePatch,
+			want:    "1.25.4",
+		},
+		{
+			name:    "só pré-releases disponíveis",
+			current: "1.25.3",
+			tags:    []string{"1.25.4-rc1", "1.26.0-beta.2"},
+			policy:  AutoUpdateMinor,
+		},
+		{
+			name:    "sufixo da tag atual é mantido",
+			current: "1.25.3-alpine",
+			tags:    []string{"1.25.5", "1.25.4-alpine", "1.25.6-bookworm"},
+			policy:  AutoUpdatePatch,
+			want:    "1.25.4-alpine",
+		},
+		{
+			name:    "pré-release só avança no mesmo sufixo",
+			current: "2.0.0-rc1",
+			tags:    []string{"2.0.0", "2.0.0-rc2", "2.0.1-rc1"},
+			policy:  AutoUpdatePatch,
+			want:    "2.0.1-rc1",
+		},
+		{
+			name:    "prefixo v",
+			current: "v1.2.3",
+			tags:    []string{"1.2.9", "v1.2.4", "v1.3.0"},
+			policy:  AutoUpdatePatch,
+			want:    "v1.2.4",
+		},
+		{
+			name:    "sem prefixo v não casa com v",
+			current: "1.2.3",
+			tags:    []string{"v1.2.9"},
+			policy:  AutoUpdatePatch,
+		},
+		{
+			name:    "tag sem patch só compara com o mesmo formato",
+			current: "1.25",
+			tags:    []string{"1.26.1", "1.26", "1.25.9"},
+			policy:  AutoUpdateMinor,
+			want:    "1.26",
+		},
+		{
+			name:    "patch exige tag com patch",
+			current: "1.25",
+			tags:    []string{"1.25.1", "1.26"},
+			policy:  AutoUpdatePatch,
+		},
+		{
+			name:    "tag atual não interpretável",
+			current: "latest",
+			tags:    []string{"1.25.4"},
+			policy:  AutoUpdateMinor,
+		},
+		{
+			name:    "tags não interpretáveis são ignoradas",
+			current: "1.25.3",
+			tags:    []string{"1.25.x", "1.25.4.1", "stable", "1.25.4_build", ""},
+			policy:  AutoUpdatePatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NextTag(tt.current, tt.tags, tt.policy); got != tt.want {
+				t.Errorf("NextTag(%q, %v, %s) = %q; esperado %q", tt.current, tt.tags, tt.policy, got, tt.want)
+			}
+		})
+	}
+}
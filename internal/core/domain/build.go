@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Build descreve como construir a imagem a partir do código-fonte (Dockerfile local)
+// Quando presente, 'oi up' faz o build em vez de baixar a imagem de 'origem'
+type Build struct {
+	// Context é o diretório enviado ao build (relativo ao oi.json, padrão ".")
+	Context string `json:"context,omitempty"`
+	// Dockerfile é o caminho do Dockerfile relativo ao contexto (padrão "Dockerfile")
+	Dockerfile string `json:"dockerfile,omitempty"`
+	// Args são os build args (ARG) passados ao Dockerfile
+	Args map[string]string `json:"args,omitempty"`
+	// Target é o estágio alvo em builds multi-stage
+	Target string `json:"target,omitempty"`
+}
+
+// DockerfileOrDefault retorna o caminho do Dockerfile relativo ao contexto
+func (b *Build) DockerfileOrDefault() string {
+	if b.Dockerfile == "" {
+		return "Dockerfile"
+	}
+	return b.Dockerfile
+}
+
+// Validate verifica se os campos do build são válidos
+func (b *Build) Validate(prefix string) error {
+	dockerfile := filepath.Clean(b.DockerfileOrDefault())
+	if filepath.IsAbs(dockerfile) || dockerfile == ".." || strings.HasPrefix(dockerfile, ".."+string(filepath.Separator)) {
+		return ErrInvalidField(prefix+"build.dockerfile", "deve ser um caminho dentro do contexto")
+	}
+	return nil
+}
+
+// ImageTag retorna a tag da imagem construída
+// Usa 'origem' quando declarada; senão, gera "oi-<projeto>[-<serviço>]:latest"
+func (i *Intent) ImageTag() string {
+	if i.Origem != "" {
+		return i.Origem
+	}
+	if i.Servico != "" {
+		return fmt.Sprintf("oi-%s-%s:latest", i.Nome, i.Servico)
+	}
+	return fmt.Sprintf("oi-%s:latest", i.Nome)
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// Canary é um deploy canário em andamento: a nova versão recebe uma fração do
+// tráfego ao lado da versão estável até ser promovida ou abortada
+type Canary struct {
+	Project string `json:"project"`
+	Service string `json:"service,omitempty"`
+	// Version é a versão canário (nova)
+	Version string `json:"version"`
+	// Stable é a versão estável que continua recebendo o restante do tráfego
+	Stable string `json:"stable"`
+	// Weight é a porcentagem do tráfego enviada ao canário (1-99)
+	Weight int `json:"weight"`
+	// Retention é a retenção da intenção, aplicada ao promover
+	Retention int       `json:"retention"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// PolicyWeightedRoundRobin distribui o tráfego conforme o peso de cada upstream
+// Usada apenas em rotas canário (não é declarável no oi.json)
+const PolicyWeightedRoundRobin = "weighted_round_robin"
+
+// CanaryRoutes monta as rotas que dividem o tráfego entre as réplicas estáveis e as
+// do canário, com weight% para o canário, em todos os endereços do serviço (ver RoutesFor)
+// Os pesos por upstream compensam números diferentes de réplicas em cada lado
+func CanaryRoutes(sites []Site, stripPrefix bool, stable, canary []Container, weight int) []Route {
+	routes := RoutesFor(sites, stripPrefix, PolicyWeightedRoundRobin, append(append([]Container(nil), stable...), canary...))
+	for r := range routes {
+		for i := range routes[r].Upstreams {
+			if i < len(stable) {
+				routes[r].Upstreams[i].Weight = (100 - weight) * len(canary)
+			} else {
+				routes[r].Upstreams[i].Weight = weight * len(stable)
+			}
+		}
+	}
+	return routes
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "strings"
+
+// ImageDrift compara a imagem em execução de um serviço com a que o registry
+// serve hoje para a mesma tag
+type ImageDrift struct {
+	Project string
+	Service string
+	// Image é a referência declarada (ex: "nginx:alpine")
+	Image string
+	// Running é o digest implantado (label io.oi.digest), vazio se desconhecido
+	Running string
+	// Latest é o digest servido hoje pelo registry para a tag
+	Latest string
+	// Built indica imagem construída localmente (sem registry para comparar)
+	Built bool
+	// Error descreve a falha ao consultar o registry, se houver
+	Error string
+}
+
+// Outdated indica que o próximo 'oi up' baixaria uma imagem diferente
+func (d ImageDrift) Outdated() bool {
+	return !d.Built && d.Error == "" && d.Running != "" && d.Latest != "" && DigestHash(d.Running) != d.Latest
+}
+
+// DigestHash extrai o hash de um repo digest ("nginx@sha256:..." -> "sha256:...")
+func DigestHash(digest string) string {
+	if i := strings.LastIndex(digest, "@"); i >= 0 {
+		return digest[i+1:]
+	}
+	return digest
+}
+
+// IsBuiltDigest indica um digest de imagem construída localmente (ID, sem repositório)
+func IsBuiltDigest(digest string) bool {
+	return digest != "" && !strings.Contains(digest, "@")
+}
@@ -12,6 +12,11 @@ func ErrMissingField(field string) error {
 	return fmt.Errorf("campo obrigatório ausente: %s", field)
 }
 
+// ErrInvalidField retorna erro para campo com valor inválido
+func ErrInvalidField(field, reason string) error {
+	return fmt.Errorf("campo inválido %s: %s", field, reason)
+}
+
 // ErrContainerNotFound indica que um container não foi encontrado
 type ErrContainerNotFound struct {
 	ID string
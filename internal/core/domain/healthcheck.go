@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Valores padrão do health check
+const (
+	DefaultHealthTimeout  = 60 * time.Second
+	defaultHealthPath     = "/"
+	defaultHealthStatus   = 200
+	defaultHealthInterval = 5 * time.Second
+	defaultProbeTimeout   = 3 * time.Second
+	defaultHealthRetries  = 3
+)
+
+// HealthCheck define uma verificação HTTP de saúde declarada no oi.json
+// O orquestrador só promove o novo container se o endpoint responder o status esperado
+type HealthCheck struct {
+	// Path é o caminho HTTP verificado (ex: "/health")
+	Path string `json:"path,omitempty"`
+	// Status é o código HTTP esperado (padrão 200)
+	Status int `json:"status,omitempty"`
+	// Interval é o intervalo entre tentativas (ex: "5s")
+	Interval string `json:"interval,omitempty"`
+	// Timeout é o tempo máximo de cada requisição (ex: "3s")
+	Timeout string `json:"timeout,omitempty"`
+	// Retries é o número de falhas consecutivas toleradas após o start_period
+	Retries int `json:"retries,omitempty"`
+	// StartPeriod é o tempo de boot em que falhas não são contabilizadas (ex: "10s")
+	StartPeriod string `json:"start_period,omitempty"`
+}
+
+// Validate verifica se os campos do health check são válidos
+func (h *HealthCheck) Validate() error {
+	if h.Status != 0 && (h.Status < 100 || h.Status > 599) {
+		return ErrInvalidField("healthcheck.status", "deve ser um status HTTP válido")
+	}
+	if h.Retries < 0 {
+		return ErrInvalidField("healthcheck.retries", "não pode ser negativo")
+	}
+	durations := map[string]string{
+		"healthcheck.interval":     h.Interval,
+		"healthcheck.timeout":      h.Timeout,
+		"healthcheck.start_period": h.StartPeriod,
+	}
+	for field, value := range durations {
+		if value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(value); err != nil || d < 0 {
+			return ErrInvalidField(field, "duração inválida (ex: \"5s\")")
+		}
+	}
+	return nil
+}
+
+// PathOrDefault retorna o caminho verificado
+func (h *HealthCheck) PathOrDefault() string {
+	if h.Path == "" {
+		return defaultHealthPath
+	}
+	if h.Path[0] != '/' {
+		return "/" + h.Path
+	}
+	return h.Path
+}
+
+// StatusOrDefault retorna o status HTTP esperado
+func (h *HealthCheck) StatusOrDefault() int {
+	if h.Status == 0 {
+		return defaultHealthStatus
+	}
+	return h.Status
+}
+
+// IntervalDuration retorna o intervalo entre tentativas
+func (h *HealthCheck) IntervalDuration() time.Duration {
+	return parseDurationOr(h.Interval, defaultHealthInterval)
+}
+
+// TimeoutDuration retorna o timeout de cada requisição
+func (h *HealthCheck) TimeoutDuration() time.Duration {
+	return parseDurationOr(h.Timeout, defaultProbeTimeout)
+}
+
+// StartPeriodDuration retorna o período de boot
+func (h *HealthCheck) StartPeriodDuration() time.Duration {
+	return parseDurationOr(h.StartPeriod, 0)
+}
+
+// RetriesOrDefault retorna o número de falhas toleradas
+func (h *HealthCheck) RetriesOrDefault() int {
+	if h.Retries == 0 {
+		return defaultHealthRetries
+	}
+	return h.Retries
+}
+
+// MaxWait retorna o tempo máximo total de espera pelo container
+// Sem health check declarado, usa DefaultHealthTimeout
+func (h *HealthCheck) MaxWait() time.Duration {
+	if h == nil {
+		return DefaultHealthTimeout
+	}
+	attempts := time.Duration(h.RetriesOrDefault() + 1)
+	return h.StartPeriodDuration() + attempts*(h.IntervalDuration()+h.TimeoutDuration())
+}
+
+// LabelValue serializa o health check para o label do container (vazio se nil)
+// O rollback lê o label para verificar a versão alvo com o health check dela
+func (h *HealthCheck) LabelValue() string {
+	if h == nil {
+		return ""
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// ParseHealthCheckLabel é o inverso de LabelValue; valores vazios ou inválidos retornam nil
+func ParseHealthCheckLabel(value string) *HealthCheck {
+	if value == "" {
+		return nil
+	}
+	var h HealthCheck
+	if err := json.Unmarshal([]byte(value), &h); err != nil || h.Validate() != nil {
+		return nil
+	}
+	return &h
+}
+
+func parseDurationOr(value string, fallback time.Duration) time.Duration {
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fallback
+	}
+	return d
+}
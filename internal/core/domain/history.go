@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// Ações registradas no histórico de deploys
+const (
+	ActionUp       = "up"
+	ActionDown     = "down"
+	ActionRollback = "rollback"
+	ActionPromote  = "promote"
+	ActionAbort    = "abort"
+)
+
+// Resultados possíveis de uma ação
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// DeployRecord é uma entrada do histórico de deploys de um projeto
+type DeployRecord struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Project     string    `json:"project"`
+	Service     string    `json:"service,omitempty"`
+	Action      string    `json:"action"`
+	Version     string    `json:"version,omitempty"`
+	Image       string    `json:"image,omitempty"`
+	ImageDigest string    `json:"image_digest,omitempty"`
+	Outcome     string    `json:"outcome"`
+	Reason      string    `json:"reason,omitempty"`
+	User        string    `json:"user,omitempty"`
+	Host        string    `json:"host,omitempty"`
+	// Source é o caminho do oi.json usado no deploy
+	Source string `json:"source,omitempty"`
+	// Intent é o snapshot da intenção, com valores de ambiente mascarados
+	Intent *Intent `json:"intent,omitempty"`
+}
+
+// Succeeded retorna true se a ação foi concluída com sucesso
+func (r *DeployRecord) Succeeded() bool {
+	return r.Outcome == OutcomeSuccess
+}
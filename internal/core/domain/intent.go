@@ -1,25 +1,96 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Intent representa a intenção declarada no arquivo oi.json
 // É a "fonte da verdade" do que o usuário deseja
 type Intent struct {
 	// Portuguese
-	Nome     string   `json:"nome,omitempty"`
-	Origem   string   `json:"origem,omitempty"`
-	Dominio  string   `json:"dominio,omitempty"`
-	Porta    int      `json:"porta,omitempty"`
-	Recursos Recursos `json:"recursos,omitempty"`
+	Nome     string            `json:"nome,omitempty"`
+	Origem   string            `json:"origem,omitempty"`
+	Dominio  string            `json:"dominio,omitempty"`
+	Porta    int               `json:"porta,omitempty"`
+	Recursos Recursos          `json:"recursos,omitempty"`
+	Ambiente map[string]string `json:"ambiente,omitempty"`
 
 	// English
-	Name      string   `json:"name,omitempty"`
-	Origin    string   `json:"origin,omitempty"`
-	Domain    string   `json:"domain,omitempty"`
-	Port      int      `json:"port,omitempty"`
-	Resources Recursos `json:"resources,omitempty"`
+	Name      string            `json:"name,omitempty"`
+	Origin    string            `json:"origin,omitempty"`
+	Domain    string            `json:"domain,omitempty"`
+	Port      int               `json:"port,omitempty"`
+	Resources Recursos          `json:"resources,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+
+	// Dominios lista endereços adicionais do serviço, além de dominio: aliases
+	// ("www.loja.com"), curingas ("*.loja.com") e prefixos de caminho ("api.loja.com/v2")
+	Dominios []string `json:"dominios,omitempty"`
+	Domains  []string `json:"domains,omitempty"`
+
+	// RemoverPrefixo remove o prefixo de caminho antes de encaminhar ao container
+	// (ex: "api.loja.com/v2/pedidos" chega como "/pedidos")
+	RemoverPrefixo bool `json:"remover_prefixo,omitempty"`
+	StripPrefix    bool `json:"strip_prefix,omitempty"`
+
+	// EnvFile lista arquivos .env (relativos ao oi.json) carregados no ambiente
+	EnvFile []string `json:"env_file,omitempty"`
+
+	// Retencao define quantas versões anteriores ficam paradas para rollback
+	// nil usa DefaultRetention; 0 desabilita a retenção
+	Retencao  *int `json:"retencao,omitempty"`
+	Retention *int `json:"retention,omitempty"`
+
+	// Replicas define quantos containers atendem o serviço (padrão 1)
+	Replicas int `json:"replicas,omitempty"`
+
+	// Balanceamento é a política de distribuição entre réplicas (padrão round_robin)
+	Balanceamento string `json:"balanceamento,omitempty"`
+	LoadBalancing string `json:"load_balancing,omitempty"`
+
+	// Build constrói a imagem a partir de um Dockerfile local em vez de baixá-la
+	// Com build, 'origem' é opcional e vira a tag da imagem construída
+	Build *Build `json:"build,omitempty"`
+
+	// Volumes declara volumes nomeados persistentes: nome -> caminho no container
+	// Sobrevivem a deploys Blue-Green e a 'oi down' (exceto com --volumes)
+	Volumes map[string]string `json:"volumes,omitempty"`
+
+	// HealthCheck define a verificação HTTP feita antes de liberar tráfego
+	HealthCheck *HealthCheck `json:"healthcheck,omitempty"`
+
+	// Canario define a análise automática de 'oi up --canary' (promove ou aborta sozinho)
+	Canario *CanaryAnalysis `json:"canario,omitempty"`
+	Canary  *CanaryAnalysis `json:"canary,omitempty"`
+
+	// AutoUpdate permite que 'oi watch-images' reimplante o serviço quando houver imagem nova
+	AutoUpdate *AutoUpdate `json:"auto_update,omitempty"`
 
 	Dev DevConfig `json:"dev,omitempty"`
+
+	// Servicos declara vários serviços no mesmo projeto (ex: app + banco + cache)
+	// Cada serviço é uma intenção própria; "nome" do projeto é herdado
+	Servicos map[string]Intent `json:"servicos,omitempty"`
+	Services map[string]Intent `json:"services,omitempty"`
+
+	// DependeDe lista os serviços que precisam estar saudáveis antes deste
+	DependeDe []string `json:"depende_de,omitempty"`
+	DependsOn []string `json:"depends_on,omitempty"`
+
+	// Servico é o nome do serviço dentro do projeto (vazio em projetos de serviço único)
+	Servico string `json:"-"`
+
+	// Source é o caminho do arquivo de onde a intenção foi carregada
+	Source string `json:"-"`
+
+	// Digest é a identidade da imagem resolvida no deploy (repo digest do pull ou
+	// ID da imagem construída), preenchida pelo orquestrador
+	Digest string `json:"-"`
 }
 
 // Recursos define os limites de CPU e memória para o container
@@ -32,6 +103,66 @@ type Recursos struct {
 	Memory string `json:"memory,omitempty"`
 }
 
+// NanoCPUs converte a CPU declarada para NanoCPUs
+// Exemplo: "0.5" -> 500000000
+func (r Recursos) NanoCPUs() int64 {
+	if r.CPU == "" {
+		return 0
+	}
+	var value float64
+	fmt.Sscanf(r.CPU, "%f", &value)
+	return int64(value * 1e9)
+}
+
+// MemoryBytes converte a memória declarada para bytes
+// Exemplo: "512mb" -> 536870912
+func (r Recursos) MemoryBytes() int64 {
+	if r.Memoria == "" {
+		return 0
+	}
+
+	mem := strings.ToLower(r.Memoria)
+	var value int64
+	var unit string
+
+	fmt.Sscanf(mem, "%d%s", &value, &unit)
+
+	switch unit {
+	case "kb", "k":
+		return value * 1024
+	case "mb", "m":
+		return value * 1024 * 1024
+	case "gb", "g":
+		return value * 1024 * 1024 * 1024
+	default:
+		return value
+	}
+}
+
+// FormatCPU converte NanoCPUs para a notação do oi.json (ex: "0.5")
+func FormatCPU(nanoCPUs int64) string {
+	if nanoCPUs == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(float64(nanoCPUs)/1e9, 'f', -1, 64)
+}
+
+// FormatMemory converte bytes para a notação do oi.json (ex: "256mb")
+func FormatMemory(bytes int64) string {
+	switch {
+	case bytes == 0:
+		return ""
+	case bytes%(1024*1024*1024) == 0:
+		return fmt.Sprintf("%dgb", bytes/(1024*1024*1024))
+	case bytes%(1024*1024) == 0:
+		return fmt.Sprintf("%dmb", bytes/(1024*1024))
+	case bytes%1024 == 0:
+		return fmt.Sprintf("%dkb", bytes/1024)
+	default:
+		return fmt.Sprintf("%d", bytes)
+	}
+}
+
 // Normalize consolida os campos em Inglês para os campos em Português
 func (i *Intent) Normalize() {
 	if i.Nome == "" {
@@ -46,6 +177,16 @@ func (i *Intent) Normalize() {
 	if i.Porta == 0 {
 		i.Porta = i.Port
 	}
+	if len(i.Dominios) == 0 {
+		i.Dominios = i.Domains
+	}
+	if !i.RemoverPrefixo {
+		i.RemoverPrefixo = i.StripPrefix
+	}
+	// Sem dominio, o primeiro endereço de dominios é o principal
+	if i.Dominio == "" && len(i.Dominios) > 0 {
+		i.Dominio, i.Dominios = i.Dominios[0], i.Dominios[1:]
+	}
 
 	// Recursos
 	if i.Recursos.CPU == "" && i.Resources.CPU != "" {
@@ -58,6 +199,141 @@ func (i *Intent) Normalize() {
 			i.Recursos.Memoria = i.Resources.Memoria
 		}
 	}
+
+	if i.Retencao == nil {
+		i.Retencao = i.Retention
+	}
+	if i.Balanceamento == "" {
+		i.Balanceamento = i.LoadBalancing
+	}
+	if i.Canario == nil {
+		i.Canario = i.Canary
+	}
+	if len(i.DependeDe) == 0 {
+		i.DependeDe = i.DependsOn
+	}
+
+	// Ambiente: chaves em Português têm prioridade sobre as em Inglês
+	if len(i.Env) > 0 {
+		if i.Ambiente == nil {
+			i.Ambiente = make(map[string]string, len(i.Env))
+		}
+		for k, v := range i.Env {
+			if _, ok := i.Ambiente[k]; !ok {
+				i.Ambiente[k] = v
+			}
+		}
+	}
+
+	// Serviços: chaves em Português têm prioridade, e cada serviço é normalizado
+	if len(i.Services) > 0 {
+		if i.Servicos == nil {
+			i.Servicos = make(map[string]Intent, len(i.Services))
+		}
+		for name, svc := range i.Services {
+			if _, ok := i.Servicos[name]; !ok {
+				i.Servicos[name] = svc
+			}
+		}
+	}
+	for name, svc := range i.Servicos {
+		svc.Normalize()
+		i.Servicos[name] = svc
+	}
+}
+
+// DefaultRetention é o número padrão de versões anteriores mantidas para rollback
+const DefaultRetention = 2
+
+// RetentionOrDefault retorna quantas versões anteriores devem ser mantidas
+func (i *Intent) RetentionOrDefault() int {
+	if i.Retencao == nil {
+		return DefaultRetention
+	}
+	return *i.Retencao
+}
+
+// Canonical retorna uma cópia normalizada da intenção, só com os campos em Português
+// Duas intenções equivalentes (ex: "name" vs "nome") têm a mesma forma canônica
+// Espera uma intenção já normalizada (ver Normalize)
+func (i Intent) Canonical() Intent {
+	i.Name, i.Origin, i.Domain, i.Port = "", "", "", 0
+	i.Domains, i.StripPrefix = nil, false
+	i.Resources = Recursos{}
+	i.Recursos.Memory = ""
+	i.Env = nil
+	i.EnvFile = nil
+	i.Retention = nil
+	i.LoadBalancing = ""
+	i.Services = nil
+	i.DependsOn = nil
+	i.Source = ""
+	// O resultado do build entra na versão pelo ID da imagem, não pela configuração
+	i.Build = nil
+	// Análise do canário e auto_update não mudam o container, apenas como o deploy é conduzido
+	i.Canario, i.Canary = nil, nil
+	i.AutoUpdate = nil
+	return i
+}
+
+// Redacted retorna uma cópia da intenção com os valores de ambiente mascarados
+// Usado para registrar snapshots sem vazar segredos
+func (i Intent) Redacted() Intent {
+	i.Ambiente = redactValues(i.Ambiente)
+	i.Env = redactValues(i.Env)
+	return i
+}
+
+func redactValues(values map[string]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	redacted := make(map[string]string, len(values))
+	for k := range values {
+		redacted[k] = "***"
+	}
+	return redacted
+}
+
+// Sites retorna os endereços públicos do serviço: dominio seguido de dominios, sem repetições
+// Endereços inválidos são ignorados (ver Validate)
+func (i *Intent) Sites() []Site {
+	var sites []Site
+	seen := make(map[Site]bool)
+	for _, item := range append([]string{i.Dominio}, i.Dominios...) {
+		site, err := ParseSite(item)
+		if err != nil || seen[site] {
+			continue
+		}
+		seen[site] = true
+		sites = append(sites, site)
+	}
+	return sites
+}
+
+// InternalPort retorna a porta em que o container escuta
+// Se a porta for 0 (dinâmica), o container usa 80 internamente por padrão
+func (i *Intent) InternalPort() int {
+	if i.Porta == 0 {
+		return 80
+	}
+	return i.Porta
+}
+
+// EnvList retorna o ambiente no formato KEY=VALUE, ordenado por chave
+// A ordem estável é importante para o hash de versão
+func (i *Intent) EnvList() []string {
+	keys := make([]string, 0, len(i.Ambiente))
+	for k := range i.Ambiente {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	env := make([]string, 0, len(keys))
+	for _, k := range keys {
+		env = append(env, k+"="+i.Ambiente[k])
+	}
+	return env
 }
 
 // DevConfig define configurações específicas para desenvolvimento (oi up --live)
@@ -68,20 +344,103 @@ type DevConfig struct {
 	Command []string `json:"command,omitempty"`
 }
 
+// projectNamePattern é o formato aceito pelo Docker em nomes de containers, onde o nome
+// do projeto entra (ex: "oi-<projeto>-..."); também exclui separadores de caminho, já
+// que o nome vira o diretório de estado do projeto
+var projectNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
 // Validate verifica se a intenção está completa
 func (i *Intent) Validate() error {
 	if i.Nome == "" {
 		return ErrMissingField("nome")
 	}
-	if i.Origem == "" {
+	if !projectNamePattern.MatchString(i.Nome) {
+		return ErrInvalidField("nome", "use apenas letras, números, '_', '.' e '-', começando com letra ou número")
+	}
+	if i.IsMultiService() {
+		return i.validateServices()
+	}
+	if i.Origem == "" && i.Build == nil {
 		return ErrMissingField("origem")
 	}
 	if i.Dominio == "" {
 		return ErrMissingField("dominio")
 	}
+	return i.validateSettings("")
+}
+
+// validateSettings valida os campos comuns a projetos e serviços
+// prefix identifica o serviço nas mensagens de erro (ex: "servicos.db.")
+func (i *Intent) validateSettings(prefix string) error {
 	if i.Porta < 0 || i.Porta > 65535 {
 		return ErrInvalidPort
 	}
+	if i.Retencao != nil && *i.Retencao < 0 {
+		return ErrInvalidField(prefix+"retencao", "não pode ser negativo")
+	}
+	if i.Replicas < 0 {
+		return ErrInvalidField(prefix+"replicas", "não pode ser negativo")
+	}
+	if err := i.validateSites(prefix); err != nil {
+		return err
+	}
+	if i.Balanceamento != "" && !validPolicies[i.Balanceamento] {
+		return ErrInvalidField(prefix+"balanceamento", "use round_robin, least_conn, random, ip_hash ou first")
+	}
+	for name, target := range i.Volumes {
+		if !volumeNamePattern.MatchString(name) {
+			return ErrInvalidField(prefix+"volumes."+name, "nome deve conter apenas letras minúsculas, números, '_' e '-'")
+		}
+		if !strings.HasPrefix(target, "/") {
+			return ErrInvalidField(prefix+"volumes."+name, "caminho no container deve ser absoluto")
+		}
+	}
+	if i.Build != nil {
+		if err := i.Build.Validate(prefix); err != nil {
+			return err
+		}
+	}
+	if i.HealthCheck != nil {
+		if err := i.HealthCheck.Validate(); err != nil {
+			return err
+		}
+	}
+	if i.Canario != nil {
+		if err := i.Canario.Validate(prefix); err != nil {
+			return err
+		}
+	}
+	if i.AutoUpdate != nil {
+		if i.Build != nil {
+			return ErrInvalidField(prefix+"auto_update", "não se aplica a imagens construídas com build")
+		}
+		if err := i.AutoUpdate.Validate(prefix, i.Origem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateSites valida os endereços de dominio e dominios e o uso de remover_prefixo
+func (i *Intent) validateSites(prefix string) error {
+	hasPath := false
+	if i.Dominio != "" {
+		site, err := ParseSite(i.Dominio)
+		if err != nil {
+			return ErrInvalidField(prefix+"dominio", err.Error())
+		}
+		hasPath = site.Path != ""
+	}
+	for n, item := range i.Dominios {
+		site, err := ParseSite(item)
+		if err != nil {
+			return ErrInvalidField(fmt.Sprintf("%sdominios[%d]", prefix, n), err.Error())
+		}
+		hasPath = hasPath || site.Path != ""
+	}
+	if i.RemoverPrefixo && !hasPath {
+		return ErrInvalidField(prefix+"remover_prefixo", "exige um prefixo de caminho em dominio ou dominios (ex: api.loja.com/v2)")
+	}
 	return nil
 }
 
@@ -110,12 +469,49 @@ type Container struct {
 	ID         string
 	Name       string
 	Project    string
+	Service    string
 	Version    string
 	Image      string
 	Status     ContainerStatus
 	Health     HealthStatus
+	Domain     string
+	Port       int
+	ImageID    string
+	Env        map[string]string
+	NanoCPUs   int64
+	Memory     int64
 	CreatedAt  time.Time
 	PublicPort int
+	// Replica é o índice da réplica dentro da versão (1..N)
+	Replica int
+	// Policy é a política de balanceamento com que o container foi implantado
+	Policy string
+	// StripPrefix indica que o prefixo de caminho dos endereços é removido (remover_prefixo)
+	StripPrefix bool
+	// Volumes mapeia o nome declarado do volume para o caminho montado no container
+	Volumes map[string]string
+	// IP é o endereço do container na network do projeto; vazio quando a network não é
+	// alcançável pelo host (ex: Podman rootless), caso em que vale PublicPort
+	IP string
+	// Digest é a identidade exata da imagem implantada (label io.oi.digest)
+	Digest string
+	// HealthCheck é o bloco healthcheck com que o container foi implantado (nil sem ele)
+	HealthCheck *HealthCheck
+}
+
+// DeployedDigest retorna o digest da imagem implantada
+// Containers anteriores ao label io.oi.digest usam o ID da imagem local
+func (c *Container) DeployedDigest() string {
+	if c.Digest != "" {
+		return c.Digest
+	}
+	return c.ImageID
+}
+
+// Sites retorna os endereços públicos com que o container foi implantado
+// Domain guarda a lista separada por vírgulas (um único domínio em containers antigos)
+func (c *Container) Sites() []Site {
+	return ParseSites(c.Domain)
 }
 
 // IsHealthy retorna true se o container está saudável e pronto para receber tráfego
@@ -123,6 +519,14 @@ func (c *Container) IsHealthy() bool {
 	return c.Status == StatusRunning && c.Health == HealthHealthy
 }
 
+// InternalPort retorna a porta em que o container escuta (80 se dinâmica)
+func (c *Container) InternalPort() int {
+	if c.Port == 0 {
+		return 80
+	}
+	return c.Port
+}
+
 // IsRunning retorna true se o container está em execução
 func (c *Container) IsRunning() bool {
 	return c.Status == StatusRunning
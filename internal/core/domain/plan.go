@@ -0,0 +1,50 @@
+package domain
+
+// Ações possíveis de um plano de deploy
+const (
+	PlanCreate = "create"
+	PlanUpdate = "update"
+	// PlanReconcile: a versão ativa já corresponde à intenção, só o proxy é reconciliado
+	PlanReconcile = "reconcile"
+)
+
+// Ações sobre rotas do proxy
+const (
+	RouteAdd    = "add"
+	RouteUpdate = "update"
+	RouteRemove = "remove"
+)
+
+// Plan descreve o que 'oi up' mudaria, sem executar nada
+type Plan struct {
+	Project string `json:"project"`
+	Service string `json:"service,omitempty"`
+	// Action é "create" (sem versão atual), "update" ou "reconcile" (nada a recriar)
+	Action string `json:"action"`
+	// Current é a versão ativa hoje (vazio se não houver)
+	Current string `json:"current,omitempty"`
+	// Changes lista as diferenças entre a intenção e o container ativo
+	Changes []PlanChange `json:"changes,omitempty"`
+	// Stop lista containers que serão parados (e retidos para rollback)
+	Stop []string `json:"stop,omitempty"`
+	// Remove lista containers que serão removidos
+	Remove []string `json:"remove,omitempty"`
+	// Routes lista as alterações no proxy
+	Routes []RouteChange `json:"routes,omitempty"`
+}
+
+// PlanChange é uma diferença de um campo da intenção
+// Valores de ambiente nunca são exibidos, apenas se foram definidos ou alterados
+type PlanChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// RouteChange é uma alteração de rota no proxy
+type RouteChange struct {
+	Action string `json:"action"`
+	Domain string `json:"domain"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// ReplicasOrDefault retorna o número de réplicas do serviço (mínimo 1)
+func (i *Intent) ReplicasOrDefault() int {
+	if i.Replicas < 1 {
+		return 1
+	}
+	return i.Replicas
+}
+
+// PolicyOrDefault retorna a política de balanceamento entre réplicas
+func (i *Intent) PolicyOrDefault() string {
+	if i.Balanceamento == "" {
+		return DefaultPolicy
+	}
+	return i.Balanceamento
+}
+
+// VersionGroup agrupa as réplicas de uma mesma versão de um serviço
+type VersionGroup struct {
+	Project    string
+	Service    string
+	Version    string
+	Containers []Container
+}
+
+// Running retorna quantas réplicas do grupo estão rodando
+func (g VersionGroup) Running() int {
+	n := 0
+	for _, c := range g.Containers {
+		if c.IsRunning() {
+			n++
+		}
+	}
+	return n
+}
+
+// GroupByVersion agrupa containers por serviço e versão
+// Serviços ficam em ordem alfabética; dentro de cada serviço, versões mais recentes
+// primeiro; dentro de cada versão, réplicas em ordem de índice
+func GroupByVersion(containers []Container) []VersionGroup {
+	index := make(map[[3]string]int)
+	var groups []VersionGroup
+	for _, c := range containers {
+		key := [3]string{c.Project, c.Service, c.Version}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, VersionGroup{Project: c.Project, Service: c.Service, Version: c.Version})
+		}
+		groups[i].Containers = append(groups[i].Containers, c)
+	}
+
+	for _, g := range groups {
+		sort.Slice(g.Containers, func(a, b int) bool {
+			return g.Containers[a].Replica < g.Containers[b].Replica
+		})
+	}
+
+	sort.SliceStable(groups, func(a, b int) bool {
+		ga, gb := groups[a], groups[b]
+		if ga.Project != gb.Project {
+			return ga.Project < gb.Project
+		}
+		if ga.Service != gb.Service {
+			return ga.Service < gb.Service
+		}
+		return ga.newest().After(gb.newest())
+	})
+	return groups
+}
+
+// newest retorna a data de criação mais recente entre as réplicas do grupo
+func (g VersionGroup) newest() time.Time {
+	var t time.Time
+	for _, c := range g.Containers {
+		if c.CreatedAt.After(t) {
+			t = c.CreatedAt
+		}
+	}
+	return t
+}
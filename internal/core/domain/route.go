@@ -0,0 +1,232 @@
+package domain
+
+import (
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Políticas de balanceamento de carga entre réplicas
+const (
+	PolicyRoundRobin = "round_robin"
+	PolicyLeastConn  = "least_conn"
+	PolicyRandom     = "random"
+	PolicyIPHash     = "ip_hash"
+	PolicyFirst      = "first"
+)
+
+// DefaultPolicy é a política usada quando o oi.json não declara balanceamento
+const DefaultPolicy = PolicyRoundRobin
+
+var validPolicies = map[string]bool{
+	PolicyRoundRobin: true,
+	PolicyLeastConn:  true,
+	PolicyRandom:     true,
+	PolicyIPHash:     true,
+	PolicyFirst:      true,
+}
+
+// Route é a rota de um domínio no proxy, com todas as réplicas como upstreams
+type Route struct {
+	// ID identifica a rota no proxy de forma estável entre deploys (ver RouteID)
+	ID string
+	// Domain é o host principal da rota e Aliases os demais hosts atendidos por ela
+	// (ex: "www.loja.com"); todos são exatos ou todos curingas (ver RoutesFor)
+	Domain  string
+	Aliases []string
+	// PathPrefix restringe a rota aos caminhos sob o prefixo (ex: "/v2"); vazio atende todos
+	PathPrefix string
+	// StripPrefix remove PathPrefix do caminho antes de encaminhar aos upstreams
+	StripPrefix bool
+	Upstreams   []Upstream
+	// Policy é a política de balanceamento entre os upstreams (ver Policy*)
+	Policy string
+}
+
+// Upstream é um destino da rota (container na network do proxy)
+type Upstream struct {
+	Host string
+	Port int
+	// Weight é o peso do upstream na política weighted_round_robin (0 = sem peso)
+	Weight int
+}
+
+// Address retorna o upstream no formato host:porta
+func (u Upstream) Address() string {
+	return net.JoinHostPort(u.Host, strconv.Itoa(u.Port))
+}
+
+// Addresses retorna os endereços de todos os upstreams da rota
+func (r Route) Addresses() []string {
+	addresses := make([]string, 0, len(r.Upstreams))
+	for _, u := range r.Upstreams {
+		addresses = append(addresses, u.Address())
+	}
+	return addresses
+}
+
+// Hosts retorna os hosts da rota: Domain seguido de Aliases
+func (r Route) Hosts() []string {
+	hosts := make([]string, 0, 1+len(r.Aliases))
+	if r.Domain != "" {
+		hosts = append(hosts, r.Domain)
+	}
+	return append(hosts, r.Aliases...)
+}
+
+// Sites retorna os endereços atendidos pela rota (cada host com PathPrefix)
+func (r Route) Sites() []Site {
+	hosts := r.Hosts()
+	sites := make([]Site, 0, len(hosts))
+	for _, h := range hosts {
+		sites = append(sites, Site{Host: h, Path: r.PathPrefix})
+	}
+	return sites
+}
+
+// Site retorna o endereço principal da rota ("host[/prefixo]"), usado para consultá-la no proxy
+func (r Route) Site() string {
+	return r.Domain + r.PathPrefix
+}
+
+// Label retorna os endereços da rota para exibição (ex: "loja.com, www.loja.com")
+func (r Route) Label() string {
+	sites := r.Sites()
+	items := make([]string, 0, len(sites))
+	for _, s := range sites {
+		items = append(items, s.String())
+	}
+	return strings.Join(items, ", ")
+}
+
+// Precedence ordena rotas que podem atender a mesma requisição (maior vence): prefixos
+// de caminho mais longos primeiro e, no mesmo prefixo, hosts exatos antes de curingas
+// Usado pelos proxies que avaliam as rotas em ordem ou por prioridade
+func (r Route) Precedence() int {
+	precedence := 2 * len(r.PathPrefix)
+	if !strings.HasPrefix(r.Domain, "*.") {
+		precedence++
+	}
+	return precedence
+}
+
+// Serves verifica se a rota atende o endereço (mesmo host e mesmo prefixo)
+func (r Route) Serves(site Site) bool {
+	if r.PathPrefix != site.Path {
+		return false
+	}
+	for _, h := range r.Hosts() {
+		if strings.EqualFold(h, site.Host) {
+			return true
+		}
+	}
+	return false
+}
+
+// Overlaps verifica se as duas rotas disputam algum endereço (mesmo host e prefixo)
+func (r Route) Overlaps(other Route) bool {
+	for _, s := range other.Sites() {
+		if r.Serves(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicyOrDefault retorna a política de balanceamento da rota
+func (r Route) PolicyOrDefault() string {
+	if r.Policy == "" {
+		return DefaultPolicy
+	}
+	return r.Policy
+}
+
+// Separadores do ID da rota: nenhum deles ocorre em nomes de projeto ou de serviço, então
+// IDs de serviços diferentes nunca coincidem (com "-", o projeto "a" com o serviço "b"
+// colidia com o projeto "a-b")
+const (
+	routeServiceSeparator = "+"
+	routeSuffixSeparator  = "~"
+)
+
+// RouteID retorna o identificador da rota de um serviço: "oi-<projeto>[+<serviço>]"
+func RouteID(project, service string) string {
+	if service != "" {
+		return "oi-" + project + routeServiceSeparator + service
+	}
+	return "oi-" + project
+}
+
+// RouteFor monta a rota de um domínio apontando para os containers informados
+// Os containers devem ser réplicas de um mesmo serviço
+func RouteFor(domain string, policy string, containers []Container) Route {
+	route := Route{Domain: domain, Policy: policy}
+	if len(containers) > 0 {
+		route.ID = RouteID(containers[0].Project, containers[0].Service)
+	}
+	for _, c := range containers {
+		route.Upstreams = append(route.Upstreams, Upstream{Host: c.Name, Port: c.InternalPort()})
+	}
+	return route
+}
+
+// RoutesFor monta as rotas de um serviço para os seus endereços públicos, apontando
+// para os containers informados (réplicas de um mesmo serviço)
+// Endereços com o mesmo prefixo de caminho dividem uma rota (os demais hosts viram
+// Aliases) e hosts curinga ficam numa rota à parte, para que cada rota tenha uma só
+// precedência. A rota do primeiro endereço usa RouteID; as demais recebem um sufixo
+// derivado do prefixo (ex: "oi-loja~v2", "oi-loja~curinga")
+func RoutesFor(sites []Site, stripPrefix bool, policy string, containers []Container) []Route {
+	var routes []Route
+	groups := make(map[string]int)
+	ids := make(map[string]bool)
+	for _, s := range sites {
+		key := s.Path
+		if s.IsWildcard() {
+			key += " *"
+		}
+		if i, ok := groups[key]; ok {
+			if !routes[i].Serves(s) {
+				routes[i].Aliases = append(routes[i].Aliases, s.Host)
+			}
+			continue
+		}
+
+		route := RouteFor(s.Host, policy, containers)
+		route.PathPrefix = s.Path
+		route.StripPrefix = stripPrefix && s.Path != ""
+		if len(routes) > 0 && route.ID != "" {
+			base := route.ID + routeSuffixSeparator + routeSuffix(s)
+			route.ID = base
+			for n := 2; ids[route.ID]; n++ {
+				route.ID = base + "-" + strconv.Itoa(n)
+			}
+		}
+		ids[route.ID] = true
+		groups[key] = len(routes)
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+// routeSuffix deriva o sufixo do ID da rota de um endereço secundário
+// ("/api/v2" -> "api-v2"; curingas ganham "curinga")
+func routeSuffix(s Site) string {
+	var parts []string
+	if s.Path != "" {
+		slug := strings.Map(func(r rune) rune {
+			if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+				return r
+			}
+			return '-'
+		}, strings.ToLower(s.Path[1:]))
+		if slug = strings.Trim(slug, "-"); slug == "" {
+			slug = "caminho"
+		}
+		parts = append(parts, slug)
+	}
+	if s.IsWildcard() {
+		parts = append(parts, "curinga")
+	}
+	return strings.Join(parts, "-")
+}
@@ -0,0 +1,84 @@
+package domain
+
+import "testing"
+
+func TestRouteIDsDoNotCollide(t *testing.T) {
+	sites := func(addresses ...string) []Site {
+		var result []Site
+		for _, a := range addresses {
+			s, err := ParseSite(a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result = append(result, s)
+		}
+		return result
+	}
+	ids := func(project, service string, s []Site) []string {
+		var result []string
+		for _, r := range RoutesFor(s, false, "", []Container{{Project: project, Service: service, Name: "c", Port: 80}}) {
+			result = append(result, r.ID)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name string
+		a, b []string
+	}{
+		{
+			name: "serviço de projeto e projeto com hífen",
+			a:    []string{RouteID("a", "b")},
+			b:    []string{RouteID("a-b", "")},
+		},
+		{
+			name: "serviço e prefixo de mesmo nome",
+			a:    ids("a", "b", sites("a.com")),
+			b:    ids("a", "", sites("a.com", "a.com/b")),
+		},
+		{
+			name: "prefixo e projeto com o sufixo no nome",
+			a:    ids("a", "", sites("a.com", "a.com/v2")),
+			b:    ids("a--v2", "", sites("b.com")),
+		},
+		{
+			name: "curinga de serviço e serviço com o sufixo no nome",
+			a:    ids("a", "b", sites("a.com", "*.a.com")),
+			b:    ids("a", "b-curinga", sites("b.com")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, a := range tt.a {
+				for _, b := range tt.b {
+					if a == b {
+						t.Errorf("rotas %v e %v compartilham o ID %q", tt.a, tt.b, a)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRoutesForIDs(t *testing.T) {
+	var s []Site
+	for _, a := range []string{"loja.com", "www.loja.com", "loja.com/v2", "*.loja.com"} {
+		site, err := ParseSite(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s = append(s, site)
+	}
+	routes := RoutesFor(s, false, "", []Container{{Project: "loja", Service: "api", Name: "c", Port: 80}})
+
+	want := []string{"oi-loja+api", "oi-loja+api~v2", "oi-loja+api~curinga"}
+	if len(routes) != len(want) {
+		t.Fatalf("RoutesFor = %+v; esperadas %d rotas", routes, len(want))
+	}
+	for i, r := range routes {
+		if r.ID != want[i] {
+			t.Errorf("rota %d: ID = %q; esperado %q", i, r.ID, want[i])
+		}
+	}
+}
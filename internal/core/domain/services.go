@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// serviceNamePattern restringe nomes de serviço a algo válido como hostname na network
+var serviceNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// IsMultiService retorna true se a intenção declara serviços
+func (i *Intent) IsMultiService() bool {
+	return len(i.Servicos) > 0
+}
+
+// ServicesInOrder retorna as intenções de cada serviço em ordem de dependência
+// (dependências primeiro; empates em ordem alfabética)
+// Cada intenção retornada herda o nome do projeto e tem Servico preenchido
+func (i *Intent) ServicesInOrder() ([]Intent, error) {
+	names := make([]string, 0, len(i.Servicos))
+	for name := range i.Servicos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Ordenação topológica (Kahn)
+	pending := make(map[string]int, len(names))
+	dependents := make(map[string][]string)
+	for _, name := range names {
+		deps := i.Servicos[name].DependeDe
+		pending[name] = len(deps)
+		for _, dep := range deps {
+			if _, ok := i.Servicos[dep]; !ok {
+				return nil, ErrInvalidField("servicos."+name+".depende_de", fmt.Sprintf("serviço '%s' não existe", dep))
+			}
+			if dep == name {
+				return nil, ErrInvalidField("servicos."+name+".depende_de", "serviço não pode depender de si mesmo")
+			}
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	var ready []string
+	for _, name := range names {
+		if pending[name] == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	ordered := make([]Intent, 0, len(names))
+	for len(ready) > 0 {
+		name := ready[0]
+		ready = ready[1:]
+
+		svc := i.Servicos[name]
+		svc.Nome = i.Nome
+		svc.Servico = name
+		svc.Source = i.Source
+		ordered = append(ordered, svc)
+
+		next := dependents[name]
+		sort.Strings(next)
+		for _, d := range next {
+			pending[d]--
+			if pending[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+		sort.Strings(ready)
+	}
+
+	if len(ordered) != len(names) {
+		var cycle []string
+		for _, name := range names {
+			if pending[name] > 0 {
+				cycle = append(cycle, name)
+			}
+		}
+		return nil, ErrInvalidField("servicos", fmt.Sprintf("dependência circular entre %s", strings.Join(cycle, ", ")))
+	}
+
+	return ordered, nil
+}
+
+// validateServices valida um projeto com vários serviços
+func (i *Intent) validateServices() error {
+	if i.Origem != "" {
+		return ErrInvalidField("origem", "em projetos com serviços, declare a origem em cada serviço")
+	}
+	if i.Build != nil {
+		return ErrInvalidField("build", "em projetos com serviços, declare o build em cada serviço")
+	}
+	if i.AutoUpdate != nil {
+		return ErrInvalidField("auto_update", "em projetos com serviços, declare auto_update em cada serviço")
+	}
+
+	for name, svc := range i.Servicos {
+		prefix := "servicos." + name + "."
+		if !serviceNamePattern.MatchString(name) {
+			return ErrInvalidField("servicos."+name, "nome deve conter apenas letras minúsculas, números e hífens")
+		}
+		if svc.IsMultiService() {
+			return ErrInvalidField(prefix+"servicos", "serviços não podem ser aninhados")
+		}
+		if svc.Origem == "" && svc.Build == nil {
+			return ErrMissingField(prefix + "origem")
+		}
+		if err := svc.validateSettings(prefix); err != nil {
+			return err
+		}
+	}
+
+	// Valida dependências (inexistentes ou circulares)
+	_, err := i.ServicesInOrder()
+	return err
+}
+
+// IsPublic retorna true se o serviço deve receber rota no proxy
+func (i *Intent) IsPublic() bool {
+	return i.Dominio != "" || len(i.Dominios) > 0
+}
+
+// Label retorna o identificador exibido ao usuário ("projeto" ou "projeto/serviço")
+func (i *Intent) Label() string {
+	if i.Servico == "" {
+		return i.Nome
+	}
+	return i.Nome + "/" + i.Servico
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Site é um endereço público de um serviço: um host (exato ou curinga) e um prefixo de
+// caminho opcional, declarado como "host[/prefixo]" em dominio/dominios
+// Exemplos: "loja.com", "www.loja.com", "*.loja.com", "api.loja.com/v2"
+type Site struct {
+	Host string
+	// Path é o prefixo de caminho (ex: "/v2"); vazio atende todos os caminhos do host
+	Path string
+}
+
+// String retorna o endereço no formato do oi.json ("host[/prefixo]")
+func (s Site) String() string {
+	return s.Host + s.Path
+}
+
+// IsWildcard indica host curinga ("*.loja.com"), que atende um nível de subdomínio
+func (s Site) IsWildcard() bool {
+	return strings.HasPrefix(s.Host, "*.")
+}
+
+// hostPattern aceita hostnames em minúsculas, com curinga apenas no primeiro nível
+var hostPattern = regexp.MustCompile(`^(\*\.)?([a-z0-9]([a-z0-9_-]*[a-z0-9])?\.)*[a-z0-9]([a-z0-9_-]*[a-z0-9])?$`)
+
+// pathSegmentPattern restringe os segmentos do prefixo a caracteres seguros em todos os proxies
+var pathSegmentPattern = regexp.MustCompile(`^[A-Za-z0-9._~%@:+-]+$`)
+
+// ParseSite interpreta um endereço "host[/prefixo]" (ex: "api.loja.com/v2")
+// O host vai para minúsculas e a barra final do prefixo é descartada ("loja.com/" = "loja.com")
+func ParseSite(s string) (Site, error) {
+	raw := strings.TrimSpace(s)
+	host, path := raw, ""
+	if i := strings.Index(raw, "/"); i >= 0 {
+		host, path = raw[:i], strings.TrimRight(raw[i:], "/")
+	}
+	host = strings.ToLower(host)
+
+	if host == "" {
+		return Site{}, fmt.Errorf("endereço %q sem host", s)
+	}
+	if !hostPattern.MatchString(host) {
+		return Site{}, fmt.Errorf("host inválido em %q (curinga só no início, como *.loja.com)", s)
+	}
+	if path != "" {
+		for _, segment := range strings.Split(path[1:], "/") {
+			if segment == "." || segment == ".." || !pathSegmentPattern.MatchString(segment) {
+				return Site{}, fmt.Errorf("prefixo de caminho inválido em %q", s)
+			}
+		}
+	}
+	return Site{Host: host, Path: path}, nil
+}
+
+// ParseSites interpreta a lista de endereços separada por vírgulas (label io.oi.domain)
+// Entradas inválidas são ignoradas
+func ParseSites(list string) []Site {
+	var sites []Site
+	for _, item := range strings.Split(list, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		if site, err := ParseSite(item); err == nil {
+			sites = append(sites, site)
+		}
+	}
+	return sites
+}
+
+// JoinSites é o inverso de ParseSites
+func JoinSites(sites []Site) string {
+	items := make([]string, 0, len(sites))
+	for _, s := range sites {
+		items = append(items, s.String())
+	}
+	return strings.Join(items, ",")
+}
+
+// MatchHost verifica se o host de uma requisição casa com o host de um endereço
+// O curinga atende exatamente um nível: "*.loja.com" casa "api.loja.com", mas não
+// "loja.com" nem "a.b.loja.com"
+func MatchHost(pattern, host string) bool {
+	pattern, host = strings.ToLower(pattern), strings.ToLower(host)
+	if !strings.HasPrefix(pattern, "*.") {
+		return pattern == host
+	}
+	label, rest, ok := strings.Cut(host, ".")
+	return ok && label != "" && rest == pattern[2:]
+}
+
+// MatchPath verifica se o caminho está sob o prefixo: o próprio prefixo ou um subcaminho
+// ("/v2" casa "/v2" e "/v2/pedidos", mas não "/v2beta"); prefixo vazio casa qualquer caminho
+func MatchPath(prefix, path string) bool {
+	if prefix == "" {
+		return true
+	}
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// StripPath remove o prefixo do caminho, mantendo a barra inicial
+// ("/v2/pedidos" -> "/pedidos", "/v2" -> "/")
+func StripPath(prefix, path string) string {
+	if !MatchPath(prefix, path) {
+		return path
+	}
+	stripped := strings.TrimPrefix(path, prefix)
+	if stripped == "" {
+		return "/"
+	}
+	return stripped
+}
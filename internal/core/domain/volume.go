@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"regexp"
+	"time"
+)
+
+// volumeNamePattern restringe nomes de volume declarados no oi.json
+var volumeNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Volume representa um volume nomeado gerenciado pelo OI
+type Volume struct {
+	// Name é o nome real do volume no runtime (ex: "oi-loja.pgdata")
+	Name string
+	// Project é o projeto dono do volume
+	Project string
+	// Key é o nome declarado no oi.json (ex: "pgdata")
+	Key       string
+	Driver    string
+	CreatedAt time.Time
+}
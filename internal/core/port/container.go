@@ -15,12 +15,25 @@ type ContainerRuntime interface {
 	// Se project for vazio, retorna todos os containers OI
 	List(ctx context.Context, project string) ([]domain.Container, error)
 
-	// Pull baixa a imagem do registry
-	Pull(ctx context.Context, image string) error
+	// Pull baixa a imagem do registry e retorna o digest resolvido
+	// (repo digest quando disponível, senão o ID da imagem local)
+	Pull(ctx context.Context, image string) (string, error)
+
+	// RemoteDigest consulta o registry e retorna o digest servido hoje para a imagem
+	// (ex: "sha256:..."), sem baixá-la
+	RemoteDigest(ctx context.Context, image string) (string, error)
+
+	// ListTags lista as tags publicadas no registry para o repositório da imagem
+	ListTags(ctx context.Context, image string) ([]string, error)
+
+	// Build constrói uma imagem a partir de um Dockerfile local, marca com tag
+	// e retorna o ID da imagem resultante
+	Build(ctx context.Context, build domain.Build, tag string) (string, error)
 
 	// Create cria um novo container baseado na intenção
+	// replica: índice da réplica dentro da versão (1..N)
 	// live: se true, aplica configurações de desenvolvimento (volumes, command)
-	Create(ctx context.Context, intent domain.Intent, version string, publishPort bool, live bool) (string, error)
+	Create(ctx context.Context, intent domain.Intent, version string, replica int, publishPort bool, live bool) (string, error)
 
 	// Start inicia um container parado
 	Start(ctx context.Context, containerID string) error
@@ -46,6 +59,16 @@ type ContainerRuntime interface {
 	// ListNetworks retorna todas as networks gerenciadas pelo OI
 	ListNetworks(ctx context.Context) ([]string, error)
 
+	// EnsureVolume garante que o volume nomeado do projeto existe e retorna seu nome real
+	EnsureVolume(ctx context.Context, project string, name string) (string, error)
+
+	// ListVolumes retorna os volumes gerenciados pelo OI
+	// Se project for vazio, retorna os volumes de todos os projetos
+	ListVolumes(ctx context.Context, project string) ([]domain.Volume, error)
+
+	// RemoveVolume remove um volume pelo nome real
+	RemoveVolume(ctx context.Context, name string) error
+
 	// Logs escreve os logs do container nos writers fornecidos
 	Logs(ctx context.Context, containerID string, stdout, stderr io.Writer, follow bool, tail string) error
 }
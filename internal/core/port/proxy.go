@@ -1,21 +1,33 @@
 package port
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
 
 // ProxyManager define as operações para gerenciar o reverse proxy
 // Abstraído do Caddy para facilitar testes e possível troca de proxy
 type ProxyManager interface {
-	// AddRoute adiciona ou atualiza uma rota de domínio para upstream
-	AddRoute(ctx context.Context, domain string, upstream string, port int) error
+	// AddRoute adiciona ou substitui a rota (identificada por route.ID) com todos os seus upstreams
+	// A rota casa com qualquer um de route.Hosts() e, se houver, com route.PathPrefix
+	// (removido do caminho com route.StripPrefix); entre rotas que atendem a mesma
+	// requisição, vence a de maior route.Precedence()
+	// Chamadas repetidas com o mesmo ID atualizam a rota existente, sem duplicá-la
+	AddRoute(ctx context.Context, route domain.Route) error
 
-	// RemoveRoute remove uma rota de domínio
-	RemoveRoute(ctx context.Context, domain string) error
+	// RemoveRoute remove a rota identificada por route.ID (e rotas antigas sem ID para os
+	// mesmos endereços); rotas de outros prefixos no mesmo host não são afetadas
+	RemoveRoute(ctx context.Context, route domain.Route) error
 
-	// HasRoute verifica se uma rota existe
-	HasRoute(ctx context.Context, domain string) (bool, error)
+	// HasRoute verifica se existe rota para o endereço ("host[/prefixo]", ver GetUpstreams)
+	HasRoute(ctx context.Context, site string) (bool, error)
 
-	// GetUpstream retorna o upstream atual para um domínio
-	GetUpstream(ctx context.Context, domain string) (string, error)
+	// GetUpstreams retorna os upstreams atuais (host:porta) da rota que atende o endereço
+	// "host[/prefixo]" (ex: "loja.com" ou "api.loja.com/v2"), com o host e o prefixo exatos
+	// com que a rota foi declarada
+	GetUpstreams(ctx context.Context, site string) ([]string, error)
 
 	// Reload força recarregamento da configuração
 	Reload(ctx context.Context) error
@@ -23,3 +35,14 @@ type ProxyManager interface {
 	// Health verifica se o proxy está saudável
 	Health(ctx context.Context) error
 }
+
+// TrafficAnalyzer é implementado por proxies capazes de medir o tráfego por upstream
+// É opcional: o orquestrador só analisa canários se o ProxyManager também o implementar
+type TrafficAnalyzer interface {
+	// EnableTrafficLog garante que o proxy registre cada requisição com o upstream que a atendeu
+	EnableTrafficLog(ctx context.Context) error
+
+	// TrafficStats resume as requisições a qualquer um dos hosts atendidas pelos upstreams
+	// (host:porta) desde since; hosts podem ser curingas (ver domain.MatchHost)
+	TrafficStats(ctx context.Context, hosts []string, upstreams []string, since time.Time) (domain.TrafficStats, error)
+}
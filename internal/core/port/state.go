@@ -0,0 +1,33 @@
+package port
+
+import (
+	"context"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// StateStore persiste o estado do OI entre execuções
+// Abstraído do sistema de arquivos para facilitar testes
+type StateStore interface {
+	// ActiveVersion retorna a versão de um serviço que está (ou deveria estar) recebendo tráfego
+	// service é vazio em projetos de serviço único. Retorna string vazia se não houver registro
+	ActiveVersion(ctx context.Context, project string, service string) (string, error)
+
+	// SetActiveVersion registra a versão ativa de um serviço
+	// Se version for vazio, remove o registro
+	SetActiveVersion(ctx context.Context, project string, service string, version string) error
+
+	// Canary retorna o deploy canário em andamento de um serviço (nil se não houver)
+	Canary(ctx context.Context, project string, service string) (*domain.Canary, error)
+
+	// SetCanary registra o canário em andamento do serviço
+	// Se canary for nil, remove o registro
+	SetCanary(ctx context.Context, project string, service string, canary *domain.Canary) error
+
+	// AppendHistory adiciona uma entrada ao histórico de deploys do projeto
+	AppendHistory(ctx context.Context, record domain.DeployRecord) error
+
+	// History retorna o histórico de deploys, do mais antigo ao mais recente
+	// Se project for vazio, retorna o histórico de todos os projetos
+	History(ctx context.Context, project string) ([]domain.DeployRecord, error)
+}
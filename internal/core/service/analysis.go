@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+	"github.com/crom-tech/oi/internal/core/port"
+)
+
+// canaryAnalyzer prepara a análise automática declarada no bloco 'canario'
+// Retorna nil (decisão manual) se não houver análise declarada ou se o proxy não
+// conseguir medir o tráfego por upstream
+func (o *Orchestrator) canaryAnalyzer(ctx context.Context, intent domain.Intent) port.TrafficAnalyzer {
+	if intent.Canario == nil {
+		return nil
+	}
+
+	analyzer, ok := o.proxy.(port.TrafficAnalyzer)
+	if !ok {
+		fmt.Printf("⚠️  Aviso: o proxy não mede tráfego por upstream, análise do canário desabilitada\n")
+		return nil
+	}
+	if err := analyzer.EnableTrafficLog(ctx); err != nil {
+		fmt.Printf("⚠️  Aviso: análise do canário desabilitada: %v\n", err)
+		return nil
+	}
+	return analyzer
+}
+
+// analyzeCanary observa o tráfego do canário durante a janela declarada e decide:
+//   - limite ultrapassado (com o mínimo de requisições): aborta na hora
+//   - janela concluída dentro dos limites: promove
+//   - tráfego insuficiente: mantém o canário para decisão manual
+//
+// As métricas da versão estável são exibidas como referência
+func (o *Orchestrator) analyzeCanary(ctx context.Context, analyzer port.TrafficAnalyzer, intent domain.Intent, stable, created []domain.Container) error {
+	analysis := intent.Canario
+	canaryAddrs := domain.RouteFor(intent.Dominio, "", created).Addresses()
+	stableAddrs := domain.RouteFor(intent.Dominio, "", stable).Addresses()
+	var hosts []string
+	for _, site := range intent.Sites() {
+		hosts = append(hosts, site.Host)
+	}
+
+	since := time.Now()
+	deadline := since.Add(analysis.WindowDuration())
+	minRequests := analysis.MinRequestsOrDefault()
+
+	fmt.Printf("\n🔬 Analisando canário por %s (verificação a cada %s, mínimo de %d requisições)\n",
+		analysis.WindowDuration(), analysis.IntervalDuration(), minRequests)
+	fmt.Printf("   Limites: %s\n", describeLimits(analysis))
+
+	var canaryStats, stableStats domain.TrafficStats
+	for {
+		wait := analysis.IntervalDuration()
+		if remaining := time.Until(deadline); remaining < wait {
+			wait = remaining
+		}
+
+		select {
+		case <-ctx.Done():
+			fmt.Printf("\n⚠️  Análise interrompida, o canário continua em andamento\n")
+			fmt.Printf("   Use 'oi promote' ou 'oi abort' para concluí-lo\n")
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+
+		var err error
+		if canaryStats, err = analyzer.TrafficStats(ctx, hosts, canaryAddrs, since); err != nil {
+			fmt.Printf("⚠️  Aviso: falha ao medir o canário: %v\n", err)
+		}
+		if stableStats, err = analyzer.TrafficStats(ctx, hosts, stableAddrs, since); err != nil {
+			fmt.Printf("⚠️  Aviso: falha ao medir a versão estável: %v\n", err)
+		}
+
+		elapsed := time.Since(since).Round(time.Second)
+		fmt.Printf("   [%s] canário: %s | estável: %s\n", elapsed, canaryStats, stableStats)
+
+		if canaryStats.Requests >= minRequests {
+			if reason := analysis.Check(canaryStats); reason != "" {
+				fmt.Printf("\n❌ Canário reprovado: %s\n", reason)
+				fmt.Printf("   Evidência: canário %s | estável %s\n", canaryStats, stableStats)
+				if err := o.Abort(ctx, intent.Nome, intent.Servico); err != nil {
+					return fmt.Errorf("falha ao abortar canário: %w", err)
+				}
+				return domain.ErrDeployFailed{
+					Project: intent.Nome,
+					Reason:  fmt.Sprintf("canário reprovado na análise: %s", reason),
+				}
+			}
+		}
+
+		if !time.Now().Before(deadline) {
+			break
+		}
+	}
+
+	if canaryStats.Requests < minRequests {
+		fmt.Printf("\nℹ️  Tráfego insuficiente para decidir (%d de %d requisições ao canário)\n", canaryStats.Requests, minRequests)
+		fmt.Printf("   O canário continua em andamento: use 'oi promote' ou 'oi abort'\n\n")
+		return nil
+	}
+
+	fmt.Printf("\n✅ Canário aprovado após %s\n", analysis.WindowDuration())
+	fmt.Printf("   Evidência: canário %s | estável %s\n", canaryStats, stableStats)
+	return o.Promote(ctx, intent.Nome, intent.Servico)
+}
+
+// describeLimits formata os limites declarados para a saída do deploy
+func describeLimits(analysis *domain.CanaryAnalysis) string {
+	limits := ""
+	if analysis.MaxErrorRate != nil {
+		limits = fmt.Sprintf("5xx até %.1f%%", *analysis.MaxErrorRate*100)
+	}
+	if p95 := analysis.MaxP95Duration(); p95 > 0 {
+		if limits != "" {
+			limits += ", "
+		}
+		limits += fmt.Sprintf("p95 até %s", p95)
+	}
+	return limits
+}
@@ -0,0 +1,129 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crom-tech/oi/internal/adapter/fake"
+	"github.com/crom-tech/oi/internal/core/domain"
+	"github.com/crom-tech/oi/internal/core/service"
+)
+
+// analyzingProxy é o proxy em memória com port.TrafficAnalyzer: a versão estável
+// (upstream stable) recebe stableStats e qualquer outro conjunto de upstreams, canaryStats
+type analyzingProxy struct {
+	*fake.Proxy
+	stable      string
+	stableStats domain.TrafficStats
+	canaryStats domain.TrafficStats
+	enableErr   error
+	statsErr    error
+}
+
+func (p *analyzingProxy) EnableTrafficLog(ctx context.Context) error {
+	return p.enableErr
+}
+
+func (p *analyzingProxy) TrafficStats(ctx context.Context, hosts []string, upstreams []string, since time.Time) (domain.TrafficStats, error) {
+	if p.statsErr != nil {
+		return domain.TrafficStats{}, p.statsErr
+	}
+	if len(upstreams) == 1 && upstreams[0] == p.stable {
+		return p.stableStats, nil
+	}
+	return p.canaryStats, nil
+}
+
+func TestCanaryAnalysis(t *testing.T) {
+	maxErrorRate := 0.05
+
+	tests := []struct {
+		name        string
+		canaryStats domain.TrafficStats
+		enableErr   error
+		statsErr    error
+		// outcome: "promote", "abort" ou "manual" (canário continua em andamento)
+		outcome string
+	}{
+		{name: "dentro dos limites", canaryStats: domain.TrafficStats{Requests: 50, Errors: 1}, outcome: "promote"},
+		{name: "taxa de 5xx acima do limite", canaryStats: domain.TrafficStats{Requests: 50, Errors: 10}, outcome: "abort"},
+		{name: "taxa no limite", canaryStats: domain.TrafficStats{Requests: 20, Errors: 1}, outcome: "promote"},
+		{name: "abaixo do mínimo de requisições", canaryStats: domain.TrafficStats{Requests: 5, Errors: 5}, outcome: "manual"},
+		{name: "falha ao medir o tráfego", canaryStats: domain.TrafficStats{Requests: 50}, statsErr: errors.New("access log indisponível"), outcome: "manual"},
+		{name: "log de tráfego indisponível", canaryStats: domain.TrafficStats{Requests: 50, Errors: 50}, enableErr: errors.New("sem permissão"), outcome: "manual"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newEnv(t)
+			proxy := &analyzingProxy{
+				Proxy:       e.proxy,
+				stableStats: domain.TrafficStats{Requests: 200},
+				canaryStats: tt.canaryStats,
+				enableErr:   tt.enableErr,
+				statsErr:    tt.statsErr,
+			}
+			orch := service.NewOrchestrator(e.runtime, proxy, e.state)
+
+			if err := orch.Up(e.ctx, webIntent("v1"), service.UpOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			stable := e.running(t, "loja")[0]
+			proxy.stable = stable.Name + ":80"
+
+			intent := webIntent("v2")
+			intent.Canario = &domain.CanaryAnalysis{Window: "30ms", Interval: "10ms", MaxErrorRate: &maxErrorRate, MinRequests: 20}
+			err := orch.Up(e.ctx, intent, service.UpOptions{Canary: 10})
+
+			var canary *domain.Container
+			for _, c := range e.containers(t, "loja") {
+				if c.ID != stable.ID {
+					canary = &c
+				}
+			}
+			state, stateErr := e.state.Canary(e.ctx, "loja", "")
+			if stateErr != nil {
+				t.Fatal(stateErr)
+			}
+
+			switch tt.outcome {
+			case "promote":
+				if err != nil {
+					t.Fatalf("Up = %v; esperado canário promovido", err)
+				}
+				if canary == nil || state != nil {
+					t.Fatalf("canário = %+v, estado = %+v; esperado promovido", canary, state)
+				}
+				e.assertRoute(t, "loja.localhost", upstreamsOf([]domain.Container{*canary}))
+				if got := e.activeVersion(t, "loja", ""); got != canary.Version {
+					t.Fatalf("versão ativa = %q; esperado o canário %q", got, canary.Version)
+				}
+			case "abort":
+				var failed domain.ErrDeployFailed
+				if !errors.As(err, &failed) || !strings.Contains(err.Error(), "5xx") {
+					t.Fatalf("Up = %v; esperado ErrDeployFailed pela taxa de 5xx", err)
+				}
+				if canary != nil || state != nil {
+					t.Fatalf("canário = %+v, estado = %+v; esperado descartado", canary, state)
+				}
+				e.assertRoute(t, "loja.localhost", upstreamsOf([]domain.Container{stable}))
+				if got := e.activeVersion(t, "loja", ""); got != stable.Version {
+					t.Fatalf("versão ativa = %q; esperado a estável", got)
+				}
+			case "manual":
+				if err != nil {
+					t.Fatalf("Up = %v; esperado canário mantido", err)
+				}
+				if canary == nil || state == nil || state.Version != canary.Version {
+					t.Fatalf("canário = %+v, estado = %+v; esperado em andamento", canary, state)
+				}
+				if route, _ := e.proxy.Route("loja.localhost"); len(route.Upstreams) != 2 {
+					t.Fatalf("upstreams = %v; esperado o tráfego dividido", route.Addresses())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// CheckImageUpdate verifica se há uma imagem nova para um serviço com auto_update
+// Espera a intenção de um único serviço (ver domain.Intent.ServicesInOrder)
+// Retorna nil se o serviço não estiver implantado ou já estiver atualizado
+func (o *Orchestrator) CheckImageUpdate(ctx context.Context, intent domain.Intent) (*domain.ImageUpdate, error) {
+	if intent.AutoUpdate == nil {
+		return nil, nil
+	}
+
+	containers, err := o.runtime.List(ctx, intent.Nome)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar containers: %w", err)
+	}
+	active := o.findActive(ctx, intent.Nome, intent.Servico, filterService(containers, intent.Servico))
+	if active == nil {
+		// O watcher não faz o primeiro deploy: isso continua sendo papel do 'oi up'
+		return nil, nil
+	}
+
+	update := &domain.ImageUpdate{
+		Project: intent.Nome,
+		Service: intent.Servico,
+		Policy:  intent.AutoUpdate.Policy,
+		From:    intent.Origem,
+		To:      intent.Origem,
+		Running: active.Digest,
+	}
+
+	// 1. patch/minor: procura uma tag maior no registry
+	if update.Policy == domain.AutoUpdatePatch || update.Policy == domain.AutoUpdateMinor {
+		repo, tag := domain.SplitImageTag(intent.Origem)
+		tags, err := o.runtime.ListTags(ctx, intent.Origem)
+		if err != nil {
+			return nil, err
+		}
+		if next := domain.NextTag(tag, tags, update.Policy); next != "" {
+			update.To = repo + ":" + next
+		}
+	}
+
+	// 2. Digest servido hoje para a tag alvo
+	latest, err := o.runtime.RemoteDigest(ctx, update.To)
+	if err != nil {
+		return nil, err
+	}
+	update.Latest = latest
+
+	if update.TagChanged() {
+		return update, nil
+	}
+	if update.Running == "" {
+		return nil, fmt.Errorf("digest implantado desconhecido para '%s' (faça um 'oi up' para registrá-lo)", intent.Label())
+	}
+	if domain.DigestHash(update.Running) == latest {
+		return nil, nil
+	}
+	return update, nil
+}
+
+// DeployedSources retorna os oi.json dos projetos em execução, segundo o último
+// 'oi up' bem-sucedido de cada um no histórico
+func (o *Orchestrator) DeployedSources(ctx context.Context) ([]string, error) {
+	if o.state == nil {
+		return nil, fmt.Errorf("estado local indisponível, não é possível localizar os oi.json implantados")
+	}
+
+	containers, err := o.runtime.List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar containers: %w", err)
+	}
+
+	projects := make(map[string]bool)
+	seen := make(map[string]bool)
+	var sources []string
+	for _, key := range uniqueServices(containers) {
+		if projects[key.project] {
+			continue
+		}
+		projects[key.project] = true
+
+		records, err := o.state.History(ctx, key.project)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler histórico de '%s': %w", key.project, err)
+		}
+		for i := len(records) - 1; i >= 0; i-- {
+			r := records[i]
+			if r.Action == domain.ActionUp && r.Succeeded() && r.Source != "" {
+				if !seen[r.Source] {
+					seen[r.Source] = true
+					sources = append(sources, r.Source)
+				}
+				break
+			}
+		}
+	}
+	return sources, nil
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// resolveImage garante que a imagem do serviço existe localmente e retorna sua identidade
+// Com build, constrói a partir do Dockerfile e retorna o ID da imagem;
+// sem build, baixa do registry e retorna o digest
+func (o *Orchestrator) resolveImage(ctx context.Context, intent domain.Intent) (string, error) {
+	if intent.Build != nil {
+		fmt.Printf("🔨 Construindo imagem '%s' a partir de %s...\n", intent.Origem, intent.Build.Context)
+		imageID, err := o.runtime.Build(ctx, *intent.Build, intent.Origem)
+		if err != nil {
+			return "", fmt.Errorf("falha ao construir imagem: %w", err)
+		}
+		return imageID, nil
+	}
+
+	fmt.Printf("📦 Baixando imagem '%s'...\n", intent.Origem)
+	digest, err := o.runtime.Pull(ctx, intent.Origem)
+	if err != nil {
+		return "", fmt.Errorf("falha ao baixar imagem: %w", err)
+	}
+	return digest, nil
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// startCanary coloca a nova versão ao lado da estável, com weight% do tráfego
+// A versão ativa continua sendo a estável até 'oi promote'
+func (o *Orchestrator) startCanary(ctx context.Context, intent domain.Intent, version string, stable, created []domain.Container, weight int) error {
+	fmt.Printf("🐤 Configurando canário: %d%% para %s, %d%% para %s...\n",
+		weight, shortVersion(version), 100-weight, shortVersion(stable[0].Version))
+
+	ids := make([]string, 0, len(created))
+	for _, c := range created {
+		ids = append(ids, c.ID)
+	}
+
+	routes := domain.CanaryRoutes(intent.Sites(), intent.RemoverPrefixo, stable, created, weight)
+	if err := applyRoutes(ctx, o.proxy, routes, nil); err != nil {
+		// Sem a divisão de tráfego o canário não faz sentido: descarta as novas réplicas
+		o.discard(ctx, ids)
+		return fmt.Errorf("falha ao configurar proxy para o canário: %w", err)
+	}
+
+	canary := &domain.Canary{
+		Project:   intent.Nome,
+		Service:   intent.Servico,
+		Version:   version,
+		Stable:    stable[0].Version,
+		Weight:    weight,
+		Retention: intent.RetentionOrDefault(),
+		StartedAt: time.Now().UTC(),
+	}
+	if err := o.state.SetCanary(ctx, intent.Nome, intent.Servico, canary); err != nil {
+		// Sem o registro, 'oi promote' e 'oi abort' não encontrariam o canário: devolve
+		// todo o tráfego à versão estável antes de descartar as novas réplicas
+		fmt.Printf("❌ Falha ao registrar canário, devolvendo o tráfego para %s...\n", shortVersion(stable[0].Version))
+		if err := applyRoutes(ctx, o.proxy, deployedRoutes(stable[0], stable), routes); err != nil {
+			fmt.Printf("⚠️  Aviso: falha ao restaurar o proxy: %v\n", err)
+		}
+		o.discard(ctx, ids)
+		return fmt.Errorf("falha ao registrar canário: %w", err)
+	}
+
+	fmt.Printf("\n✅ Canário no ar! '%s' versão %s recebe %d%% do tráfego\n", intent.Label(), shortVersion(version), weight)
+	return nil
+}
+
+// stableReplicas retorna as réplicas em execução da versão ativa do serviço
+func (o *Orchestrator) stableReplicas(ctx context.Context, intent domain.Intent, current []domain.Container) []domain.Container {
+	active := o.findActive(ctx, intent.Nome, intent.Servico, current)
+	if active == nil {
+		return nil
+	}
+
+	var running []domain.Container
+	for _, c := range filterVersion(current, active.Version) {
+		if c.IsRunning() {
+			running = append(running, c)
+		}
+	}
+	return running
+}
+
+// Promote envia 100% do tráfego ao canário e aposenta a versão estável
+// service só pode ser omitido se houver um único canário no projeto
+func (o *Orchestrator) Promote(ctx context.Context, project string, service string) (err error) {
+	record := newRecord(domain.ActionPromote, project)
+	defer func() { o.recordHistory(ctx, record, err) }()
+
+	canary, containers, err := o.resolveCanary(ctx, project, service)
+	if err != nil {
+		return err
+	}
+	record.Service = canary.Service
+	record.Version = canary.Version
+
+	replicas := filterVersion(containers, canary.Version)
+	if len(replicas) == 0 {
+		return fmt.Errorf("containers do canário %s não encontrados", shortVersion(canary.Version))
+	}
+
+	fmt.Printf("🚀 Promovendo canário %s de '%s'...\n", shortVersion(canary.Version), labelOf(project, canary.Service))
+
+	// 1. Apontar 100% do tráfego para as réplicas do canário
+	promoted := make([]domain.Container, 0, len(replicas))
+	for _, c := range replicas {
+		ctr, err := o.runtime.Inspect(ctx, c.ID)
+		if err != nil {
+			return fmt.Errorf("falha ao inspecionar container: %w", err)
+		}
+		promoted = append(promoted, *ctr)
+	}
+	record.Image = promoted[0].Image
+	record.ImageDigest = promoted[0].DeployedDigest()
+
+	if routes := deployedRoutes(promoted[0], promoted); len(routes) > 0 {
+		fmt.Printf("🔀 Configurando proxy para %s...\n", routesLabel(routes))
+		// Endereços que só a versão estável tinha deixam de ser roteados
+		var previous []domain.Route
+		if stable := filterVersion(containers, canary.Stable); len(stable) > 0 {
+			previous = deployedRoutes(stable[0], stable)
+		}
+		if err := applyRoutes(ctx, o.proxy, routes, previous); err != nil {
+			return fmt.Errorf("falha ao configurar proxy: %w", err)
+		}
+	}
+
+	// 2. Aposentar a versão estável, como num deploy completo
+	o.retireOld(ctx, containers, canary.Version, canary.Retention)
+	o.setActiveVersion(ctx, project, canary.Service, canary.Version)
+	o.clearCanary(ctx, project, canary.Service)
+
+	fmt.Printf("✅ Canário promovido! Versão ativa: %s\n", shortVersion(canary.Version))
+	return nil
+}
+
+// Abort devolve 100% do tráfego à versão estável e descarta o canário
+// service só pode ser omitido se houver um único canário no projeto
+func (o *Orchestrator) Abort(ctx context.Context, project string, service string) (err error) {
+	record := newRecord(domain.ActionAbort, project)
+	defer func() { o.recordHistory(ctx, record, err) }()
+
+	canary, containers, err := o.resolveCanary(ctx, project, service)
+	if err != nil {
+		return err
+	}
+	record.Service = canary.Service
+	record.Version = canary.Stable
+
+	fmt.Printf("🛑 Abortando canário %s de '%s'...\n", shortVersion(canary.Version), labelOf(project, canary.Service))
+
+	// 1. Apontar 100% do tráfego de volta para a versão estável
+	var stable []domain.Container
+	for _, c := range filterVersion(containers, canary.Stable) {
+		if c.IsRunning() {
+			stable = append(stable, c)
+		}
+	}
+	if len(stable) == 0 {
+		return fmt.Errorf("versão estável %s não está rodando; use 'oi promote' ou 'oi rollback'", shortVersion(canary.Stable))
+	}
+
+	if routes := deployedRoutes(stable[0], stable); len(routes) > 0 {
+		fmt.Printf("🔀 Configurando proxy para %s...\n", routesLabel(routes))
+		// Endereços novos do canário deixam de ser roteados
+		var previous []domain.Route
+		if canaryReplicas := filterVersion(containers, canary.Version); len(canaryReplicas) > 0 {
+			previous = deployedRoutes(canaryReplicas[0], canaryReplicas)
+		}
+		if err := applyRoutes(ctx, o.proxy, routes, previous); err != nil {
+			return fmt.Errorf("falha ao configurar proxy: %w", err)
+		}
+	}
+
+	// 2. Descartar as réplicas do canário
+	var ids []string
+	for _, c := range filterVersion(containers, canary.Version) {
+		fmt.Printf("🗑️  Removendo %s...\n", c.Name)
+		ids = append(ids, c.ID)
+	}
+	o.discard(ctx, ids)
+
+	o.clearCanary(ctx, project, canary.Service)
+
+	fmt.Printf("✅ Canário abortado. Versão ativa: %s\n", shortVersion(canary.Stable))
+	return nil
+}
+
+// Canaries retorna os canários em andamento de um projeto (ou de todos)
+func (o *Orchestrator) Canaries(ctx context.Context, project string) ([]domain.Canary, error) {
+	if o.state == nil {
+		return nil, nil
+	}
+
+	containers, err := o.runtime.List(ctx, project)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar containers: %w", err)
+	}
+
+	var canaries []domain.Canary
+	for _, key := range uniqueServices(containers) {
+		if canary := o.canary(ctx, key.project, key.service, containers); canary != nil {
+			canaries = append(canaries, *canary)
+		}
+	}
+	return canaries, nil
+}
+
+// resolveCanary encontra o canário alvo de promote/abort e os containers do serviço
+func (o *Orchestrator) resolveCanary(ctx context.Context, project, service string) (*domain.Canary, []domain.Container, error) {
+	if o.state == nil {
+		return nil, nil, fmt.Errorf("estado local indisponível, não é possível localizar o canário")
+	}
+	if o.proxy == nil {
+		return nil, nil, fmt.Errorf("proxy indisponível, não é possível mover o tráfego do canário")
+	}
+
+	containers, err := o.runtime.List(ctx, project)
+	if err != nil {
+		return nil, nil, fmt.Errorf("falha ao listar containers: %w", err)
+	}
+
+	var found []*domain.Canary
+	for _, key := range uniqueServices(containers) {
+		if service != "" && key.service != service {
+			continue
+		}
+		if canary := o.canary(ctx, key.project, key.service, containers); canary != nil {
+			found = append(found, canary)
+		}
+	}
+
+	switch {
+	case len(found) == 0:
+		return nil, nil, fmt.Errorf("nenhum canário em andamento para '%s'", labelOf(project, service))
+	case len(found) > 1:
+		return nil, nil, fmt.Errorf("projeto '%s' tem vários canários em andamento, especifique --service", project)
+	}
+
+	return found[0], filterService(containers, found[0].Service), nil
+}
+
+// canary retorna o canário em andamento do serviço (nil se não houver ou sem StateStore)
+// containers são os do projeto: um canário sem nenhum container da sua versão (ex:
+// removidos à mão) é descartado, para não bloquear os próximos deploys
+func (o *Orchestrator) canary(ctx context.Context, project, service string, containers []domain.Container) *domain.Canary {
+	if o.state == nil {
+		return nil
+	}
+	canary, err := o.state.Canary(ctx, project, service)
+	if err != nil {
+		fmt.Printf("⚠️  Aviso: falha ao ler canário: %v\n", err)
+		return nil
+	}
+	if canary != nil && len(filterVersion(filterService(containers, service), canary.Version)) == 0 {
+		fmt.Printf("🧹 Descartando canário %s de '%s': seus containers não existem mais\n", shortVersion(canary.Version), labelOf(project, service))
+		o.clearCanary(ctx, project, service)
+		return nil
+	}
+	return canary
+}
+
+// clearCanary remove o registro do canário do serviço, se houver StateStore
+// Falhas de persistência apenas geram aviso
+func (o *Orchestrator) clearCanary(ctx context.Context, project, service string) {
+	if o.state == nil {
+		return
+	}
+	if err := o.state.SetCanary(ctx, project, service, nil); err != nil {
+		fmt.Printf("⚠️  Aviso: falha ao limpar canário: %v\n", err)
+	}
+}
+
+// labelOf formata projeto e serviço como em domain.Intent.Label
+func labelOf(project, service string) string {
+	if service == "" {
+		return project
+	}
+	return project + "/" + service
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// probeHealth verifica o endpoint HTTP declarado em healthcheck até obter o status esperado
+// Falhas durante o start_period não contam; depois dele, mais de "retries" falhas consecutivas
+// reprovam o container
+func (o *Orchestrator) probeHealth(ctx context.Context, ctr *domain.Container, port int, hc *domain.HealthCheck) error {
+	address := probeAddress(ctr, port)
+	if address == "" {
+		return domain.ErrHealthCheckFailed{
+			ContainerID: ctr.ID,
+			Reason:      "container sem endereço acessível para health check HTTP",
+		}
+	}
+
+	url := fmt.Sprintf("http://%s%s", address, hc.PathOrDefault())
+	expected := hc.StatusOrDefault()
+	client := &http.Client{
+		Timeout: hc.TimeoutDuration(),
+		// Redirects não são seguidos: o status esperado é o da resposta original
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	graceUntil := time.Now().Add(hc.StartPeriodDuration())
+	failures := 0
+	var lastErr error
+
+	for {
+		lastErr = probeOnce(ctx, client, url, expected)
+		if lastErr == nil {
+			return nil
+		}
+
+		if time.Now().After(graceUntil) {
+			failures++
+			if failures > hc.RetriesOrDefault() {
+				return domain.ErrHealthCheckFailed{
+					ContainerID: ctr.ID,
+					Reason:      fmt.Sprintf("%s após %d tentativas: %v", url, failures, lastErr),
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(hc.IntervalDuration()):
+		}
+	}
+}
+
+// probeOnce faz uma única requisição de health check
+func probeOnce(ctx context.Context, client *http.Client, url string, expected int) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != expected {
+		return fmt.Errorf("status %d (esperado %d)", resp.StatusCode, expected)
+	}
+	return nil
+}
+
+// probeAddress determina host:porta para alcançar o container a partir do host
+// Prefere o IP na network do projeto; sem ele, usa a porta publicada no host
+func probeAddress(ctr *domain.Container, port int) string {
+	if ctr.IP != "" {
+		return net.JoinHostPort(ctr.IP, strconv.Itoa(port))
+	}
+	if ctr.PublicPort != 0 {
+		return net.JoinHostPort("127.0.0.1", strconv.Itoa(ctr.PublicPort))
+	}
+	return ""
+}
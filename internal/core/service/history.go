@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// newRecord cria uma entrada de histórico com data, usuário e host preenchidos
+func newRecord(action, project string) domain.DeployRecord {
+	host, _ := os.Hostname()
+	return domain.DeployRecord{
+		Timestamp: time.Now().UTC(),
+		Project:   project,
+		Action:    action,
+		User:      currentUser(),
+		Host:      host,
+	}
+}
+
+// recordHistory grava a entrada no histórico com o resultado da ação
+// Falhas de persistência não invalidam a operação, apenas geram aviso
+func (o *Orchestrator) recordHistory(ctx context.Context, record domain.DeployRecord, err error) {
+	if o.state == nil || record.Project == "" {
+		return
+	}
+
+	record.Outcome = domain.OutcomeSuccess
+	if err != nil {
+		record.Outcome = domain.OutcomeFailure
+		record.Reason = err.Error()
+	}
+
+	if err := o.state.AppendHistory(ctx, record); err != nil {
+		fmt.Printf("⚠️  Aviso: falha ao registrar histórico: %v\n", err)
+	}
+}
+
+// currentUser identifica quem executou o comando
+// Com sudo, registra o usuário original em vez de root
+func currentUser() string {
+	if u := os.Getenv("SUDO_USER"); u != "" {
+		return u
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
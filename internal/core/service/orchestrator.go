@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net"
@@ -18,124 +19,227 @@ import (
 type Orchestrator struct {
 	runtime port.ContainerRuntime
 	proxy   port.ProxyManager
+	state   port.StateStore
 }
 
 // NewOrchestrator cria uma nova instância do Orchestrator
-func NewOrchestrator(runtime port.ContainerRuntime, proxy port.ProxyManager) *Orchestrator {
+// proxy e state são opcionais (nil desabilita roteamento e persistência de estado)
+func NewOrchestrator(runtime port.ContainerRuntime, proxy port.ProxyManager, state port.StateStore) *Orchestrator {
 	return &Orchestrator{
 		runtime: runtime,
 		proxy:   proxy,
+		state:   state,
 	}
 }
 
+// UpOptions ajusta o comportamento de um deploy
+type UpOptions struct {
+	// Live aplica as configurações de desenvolvimento (volumes, command)
+	Live bool
+	// Force recria os containers mesmo sem mudanças
+	Force bool
+	// Canary, se maior que zero, envia essa porcentagem do tráfego à nova versão
+	// em vez de trocar tudo de uma vez (ver Promote e Abort)
+	Canary int
+}
+
 // Up realiza o deploy da intenção usando Blue-Green strategy
 // Se falhar, mantém a versão anterior funcional (Zero-Downtime)
-func (o *Orchestrator) Up(ctx context.Context, intent domain.Intent, live bool) error {
+// Se a versão (intenção + digest da imagem) já estiver rodando saudável, apenas
+// reconcilia a rota do proxy. opts.Force recria o container mesmo sem mudanças
+// Projetos com vários serviços são implantados em ordem de dependência
+func (o *Orchestrator) Up(ctx context.Context, intent domain.Intent, opts UpOptions) error {
+	if opts.Canary < 0 || opts.Canary > 99 {
+		return fmt.Errorf("canário deve receber entre 1%% e 99%% do tráfego")
+	}
+	if opts.Canary > 0 && (o.proxy == nil || o.state == nil) {
+		return fmt.Errorf("deploy canário exige o proxy e o estado local disponíveis")
+	}
+
+	if intent.IsMultiService() {
+		return o.upProject(ctx, intent, opts)
+	}
+	return o.upService(ctx, intent, opts)
+}
+
+// upService faz o deploy Blue-Green de um único serviço
+func (o *Orchestrator) upService(ctx context.Context, intent domain.Intent, opts UpOptions) (err error) {
+	// Com build, a imagem construída recebe a tag de 'origem' (ou uma tag gerada)
+	intent.Origem = intent.ImageTag()
+
+	// Todo deploy, com sucesso ou não, entra no histórico
+	record := newRecord(domain.ActionUp, intent.Nome)
+	record.Service = intent.Servico
+	record.Image = intent.Origem
+	record.Source = intent.Source
+	snapshot := intent.Redacted()
+	record.Intent = &snapshot
+	defer func() { o.recordHistory(ctx, record, err) }()
+
+	// Serviços internos (sem domínio) não recebem rota no proxy
+	proxy := o.proxy
+	if !intent.IsPublic() {
+		proxy = nil
+	}
+
 	// 0. Validação Fail-Fast: DNS
-	if err := o.verifyDomain(intent.Dominio); err != nil {
-		return err
+	if intent.IsPublic() {
+		if err := o.verifySites(intent.Sites()); err != nil {
+			return err
+		}
 	}
 
 	// 0.1. Validação Fail-Fast: Proxy acessível
-	if o.proxy != nil {
+	if proxy != nil {
 		fmt.Printf("🔍 Verificando conectividade com proxy...\n")
-		if err := o.proxy.Health(ctx); err != nil {
-			return fmt.Errorf("❌ Proxy (Caddy) não acessível. Verifique se está rodando: %w", err)
+		if err := proxy.Health(ctx); err != nil {
+			return fmt.Errorf("❌ Proxy não acessível. Verifique se está rodando: %w", err)
 		}
 	}
 
-	// 1. Gerar version hash
-	version := o.generateVersion(intent)
-
-	fmt.Printf("🚀 Iniciando deploy de '%s' (versão %s)\n", intent.Nome, version[:8])
-
-	// 2. Garantir network do projeto
+	// 1. Garantir network do projeto
 	fmt.Printf("🌐 Criando/verificando network...\n")
 	if _, err := o.runtime.EnsureNetwork(ctx, intent.Nome); err != nil {
 		return fmt.Errorf("falha ao criar network: %w", err)
 	}
+	if err := o.ensureVolumes(ctx, intent); err != nil {
+		return err
+	}
 
-	// 3. Listar containers atuais do projeto
-	current, err := o.runtime.List(ctx, intent.Nome)
+	// 2. Listar containers atuais do serviço
+	all, err := o.runtime.List(ctx, intent.Nome)
 	if err != nil {
 		return fmt.Errorf("falha ao listar containers: %w", err)
 	}
+	current := filterService(all, intent.Servico)
 
-	// 4. Baixar imagem
+	// 2.1. Um canário em andamento precisa ser promovido ou abortado antes
+	if canary := o.canary(ctx, intent.Nome, intent.Servico, current); canary != nil {
+		return fmt.Errorf("canário da versão %s em andamento para '%s': use 'oi promote' ou 'oi abort'", shortVersion(canary.Version), intent.Label())
+	}
+
+	// 3. Construir ou baixar imagem
 	// Se for live, talvez queremos garantir pull? Sim, imagem base ainda precisa.
-	fmt.Printf("📦 Baixando imagem '%s'...\n", intent.Origem)
-	if err := o.runtime.Pull(ctx, intent.Origem); err != nil {
-		return fmt.Errorf("falha ao baixar imagem: %w", err)
+	digest, err := o.resolveImage(ctx, intent)
+	if err != nil {
+		return err
+	}
+	record.ImageDigest = digest
+	intent.Digest = digest
+
+	// 4. Gerar version hash (conteúdo da intenção + digest/ID da imagem)
+	version := o.generateVersion(intent, digest, opts.Live, opts.Force)
+	record.Version = version
+
+	// 4.1. Mesma versão já em execução: nada a recriar
+	if existing := filterVersion(current, version); len(existing) > 0 {
+		reconciled, err := o.reconcileExisting(ctx, intent, existing, current)
+		if err != nil {
+			return err
+		}
+		if reconciled {
+			return nil
+		}
+		current = excludeVersion(current, version)
 	}
 
-	// 5. Criar novo container (Blue-Green)
-	fmt.Printf("🐳 Criando container...\n")
+	fmt.Printf("🚀 Iniciando deploy de '%s' (versão %s)\n", intent.Label(), version[:8])
 
+	// 5. Criar e iniciar as novas réplicas (Blue-Green)
 	// Se não tem proxy, publica a porta diretamente no host para acesso local
-	publishPort := (o.proxy == nil)
+	// Serviços internos nunca são publicados: são acessados pela network do projeto
+	publishPort := o.proxy == nil && intent.IsPublic()
+	replicas := intent.ReplicasOrDefault()
+
+	newIDs := make([]string, 0, replicas)
+	for replica := 1; replica <= replicas; replica++ {
+		fmt.Printf("🐳 Criando container (réplica %d/%d)...\n", replica, replicas)
+		newID, err := o.runtime.Create(ctx, intent, version, replica, publishPort, opts.Live)
+		if err != nil {
+			o.discard(ctx, newIDs)
+			return fmt.Errorf("falha ao criar container: %w", err)
+		}
+		newIDs = append(newIDs, newID)
 
-	newID, err := o.runtime.Create(ctx, intent, version, publishPort, live)
-	if err != nil {
-		return fmt.Errorf("falha ao criar container: %w", err)
+		// 6. Iniciar container
+		fmt.Printf("▶️  Iniciando container...\n")
+		if err := o.runtime.Start(ctx, newID); err != nil {
+			o.discard(ctx, newIDs) // Cleanup dos containers criados
+			return fmt.Errorf("falha ao iniciar container: %w", err)
+		}
 	}
 
-	// 6. Iniciar container
-	fmt.Printf("▶️  Iniciando container...\n")
-	if err := o.runtime.Start(ctx, newID); err != nil {
-		o.runtime.Remove(ctx, newID, true) // Cleanup do container criado
-		return fmt.Errorf("falha ao iniciar container: %w", err)
+	// 7. Aguardar todas as réplicas healthy (timeout definido pelo bloco healthcheck, padrão 60s)
+	maxWait := intent.HealthCheck.MaxWait()
+	fmt.Printf("💓 Aguardando health check de %d réplica(s) (max %s)...\n", replicas, maxWait)
+	for _, id := range newIDs {
+		if err := o.runtime.WaitHealthy(ctx, id, maxWait); err != nil {
+			return o.abortDeploy(ctx, intent, newIDs, err)
+		}
 	}
 
-	// 7. Aguardar healthy (60 segundos de timeout)
-	fmt.Printf("💓 Aguardando health check (max 60s)...\n")
-	if err := o.runtime.WaitHealthy(ctx, newID, 60*time.Second); err != nil {
-		fmt.Printf("❌ Health check falhou, rollback...\n")
-		o.runtime.Stop(ctx, newID, 10*time.Second)
-		o.runtime.Remove(ctx, newID, true)
-		return domain.ErrDeployFailed{
-			Project: intent.Nome,
-			Reason:  fmt.Sprintf("health check falhou: %v", err),
+	// 8. Obter informações dos containers para o proxy
+	created := make([]domain.Container, 0, len(newIDs))
+	for _, id := range newIDs {
+		container, err := o.runtime.Inspect(ctx, id)
+		if err != nil {
+			o.discard(ctx, newIDs)
+			return fmt.Errorf("falha ao inspecionar container: %w", err)
 		}
+		created = append(created, *container)
 	}
 
-	// 8. Obter informações do container para proxy
-	container, err := o.runtime.Inspect(ctx, newID)
-	if err != nil {
-		return fmt.Errorf("falha ao inspecionar container: %w", err)
+	// 8.1. Health check HTTP declarado no oi.json, antes de liberar tráfego
+	if intent.HealthCheck != nil {
+		fmt.Printf("🩺 Verificando %s (esperado %d)...\n", intent.HealthCheck.PathOrDefault(), intent.HealthCheck.StatusOrDefault())
+		for i := range created {
+			if err := o.probeHealth(ctx, &created[i], intent.InternalPort(), intent.HealthCheck); err != nil {
+				return o.abortDeploy(ctx, intent, newIDs, err)
+			}
+		}
 	}
 
-	// 9. Atualizar proxy para novo container
-	// 9. Atualizar proxy para novo container
-	// 9. Atualizar proxy para novo container
-	if o.proxy != nil {
-		fmt.Printf("🔀 Configurando proxy para %s...\n", intent.Dominio)
-
-		// Se porta for 0 (dinâmica), o container usa 80 internamente por padrão
-		proxyPort := intent.Porta
-		if proxyPort == 0 {
-			proxyPort = 80
+	// 8.2. Canário: divide o tráfego com a versão estável em vez de substituí-la
+	if opts.Canary > 0 && proxy != nil {
+		if stable := o.stableReplicas(ctx, intent, current); len(stable) > 0 {
+			analyzer := o.canaryAnalyzer(ctx, intent)
+			if err := o.startCanary(ctx, intent, version, stable, created, opts.Canary); err != nil {
+				return err
+			}
+			if analyzer == nil {
+				fmt.Printf("   Use 'oi promote' para enviar 100%% ou 'oi abort' para descartá-lo\n\n")
+				return nil
+			}
+			return o.analyzeCanary(ctx, analyzer, intent, stable, created)
 		}
+		fmt.Printf("ℹ️  Nenhuma versão estável rodando, fazendo deploy completo...\n")
+	}
+
+	// 9. Atualizar proxy para o novo conjunto de réplicas
+	if proxy != nil {
+		routes := routesFor(intent, created)
+		fmt.Printf("🔀 Configurando proxy para %s...\n", routesLabel(routes))
 
-		if err := o.proxy.AddRoute(ctx, intent.Dominio, container.Name, proxyPort); err != nil {
-			// Não faz rollback aqui pois o container está healthy
+		previous := o.activeRoutes(ctx, intent.Nome, intent.Servico, current)
+		if err := applyRoutes(ctx, proxy, routes, previous); err != nil {
+			// Não faz rollback aqui pois os containers estão healthy
 			fmt.Printf("⚠️  Aviso: falha ao configurar proxy: %v\n", err)
 		}
 	}
 
-	// 10. Remover containers antigos (graceful)
-	if len(current) > 0 {
-		fmt.Printf("🧹 Removendo %d container(s) antigo(s)...\n", len(current))
-		for _, c := range current {
-			if c.ID != newID {
-				o.runtime.Stop(ctx, c.ID, 30*time.Second)
-				o.runtime.Remove(ctx, c.ID, false)
-			}
-		}
-	}
+	// 10. Aposentar o conjunto antigo (graceful), mantendo as versões mais recentes para rollback
+	o.retireOld(ctx, current, version, intent.RetentionOrDefault())
+	o.setActiveVersion(ctx, intent.Nome, intent.Servico, version)
+	container := &created[0]
 
 	// 11. Mensagem de sucesso com opções de acesso
 	fmt.Printf("\n✅ Deploy completo!\n")
 
+	if !intent.IsPublic() {
+		fmt.Printf("   Serviço interno: %s:%d (network do projeto)\n\n", intent.Servico, intent.InternalPort())
+		return nil
+	}
+
 	// Porta para exibição (usar a real do container)
 	displayPort := container.PublicPort
 	if displayPort == 0 {
@@ -148,17 +252,184 @@ func (o *Orchestrator) Up(ctx context.Context, intent domain.Intent, live bool)
 		fmt.Printf("   • http://localhost:%d\n", displayPort)
 		fmt.Printf("   • http://%s:%d (requer /etc/hosts)\n", intent.Dominio, displayPort)
 		if o.proxy != nil {
-			fmt.Printf("   • https://%s (via Caddy, se configurado)\n", intent.Dominio)
+			fmt.Printf("   • https://%s (via proxy, se configurado)\n", intent.Dominio)
 		}
 	} else {
 		fmt.Printf("   Acesse: https://%s\n", intent.Dominio)
+		for _, site := range intent.Sites() {
+			if site.String() != intent.Dominio {
+				fmt.Printf("           https://%s\n", site)
+			}
+		}
 	}
 	fmt.Println()
 	return nil
 }
 
+// filterVersion retorna os containers (réplicas) com a versão informada
+func filterVersion(containers []domain.Container, version string) []domain.Container {
+	var result []domain.Container
+	for _, c := range containers {
+		if c.Version == version {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// excludeVersion retorna os containers exceto os da versão informada
+func excludeVersion(containers []domain.Container, version string) []domain.Container {
+	result := make([]domain.Container, 0, len(containers))
+	for _, c := range containers {
+		if c.Version != version {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// reconcileExisting trata um 'oi up' cuja versão já existe
+// Se todas as réplicas estiverem saudáveis, só garante a rota e retorna true
+// Se alguma estiver parada ou doente (ou faltar réplica), remove o conjunto para que
+// seja recriado e retorna false
+func (o *Orchestrator) reconcileExisting(ctx context.Context, intent domain.Intent, existing []domain.Container, current []domain.Container) (bool, error) {
+	version := existing[0].Version
+
+	healthy := make([]domain.Container, 0, len(existing))
+	for _, c := range existing {
+		ctr, err := o.runtime.Inspect(ctx, c.ID)
+		if err != nil {
+			return false, fmt.Errorf("falha ao inspecionar container: %w", err)
+		}
+		if ctr.IsHealthy() {
+			healthy = append(healthy, *ctr)
+		}
+	}
+
+	if len(healthy) != intent.ReplicasOrDefault() || len(healthy) != len(existing) {
+		fmt.Printf("♻️  Versão %s existe mas não está saudável, recriando...\n", shortVersion(version))
+		for _, c := range existing {
+			o.runtime.Stop(ctx, c.ID, 10*time.Second)
+			if err := o.runtime.Remove(ctx, c.ID, true); err != nil {
+				return false, fmt.Errorf("falha ao remover container antigo: %w", err)
+			}
+		}
+		return false, nil
+	}
+
+	fmt.Printf("✨ '%s' já está na versão %s, nada a recriar\n", intent.Label(), shortVersion(version))
+
+	if o.proxy != nil && intent.IsPublic() {
+		routes := routesFor(intent, healthy)
+		inSync := true
+		for _, route := range routes {
+			currentUpstreams, err := o.proxy.GetUpstreams(ctx, route.Site())
+			if err != nil {
+				fmt.Printf("⚠️  Aviso: falha ao consultar proxy: %v\n", err)
+			}
+			inSync = inSync && sameUpstreams(currentUpstreams, route.Addresses())
+		}
+		if !inSync {
+			fmt.Printf("🔀 Reconciliando proxy para %s...\n", routesLabel(routes))
+			previous := o.activeRoutes(ctx, intent.Nome, intent.Servico, current)
+			if err := applyRoutes(ctx, o.proxy, routes, previous); err != nil {
+				fmt.Printf("⚠️  Aviso: falha ao configurar proxy: %v\n", err)
+			}
+		}
+	}
+
+	// Outras versões que ainda estejam rodando (ex: após 'oi start') voltam a ficar retidas
+	o.retireOld(ctx, current, version, intent.RetentionOrDefault())
+	o.setActiveVersion(ctx, intent.Nome, intent.Servico, version)
+	return true, nil
+}
+
+// abortDeploy descarta as novas réplicas após falha de health check
+// A versão anterior continua recebendo tráfego
+func (o *Orchestrator) abortDeploy(ctx context.Context, intent domain.Intent, containerIDs []string, cause error) error {
+	fmt.Printf("❌ Health check falhou, rollback...\n")
+	o.discard(ctx, containerIDs)
+	return domain.ErrDeployFailed{
+		Project: intent.Label(),
+		Reason:  fmt.Sprintf("health check falhou: %v", cause),
+	}
+}
+
+// discard para e remove containers criados por um deploy que não foi adiante
+func (o *Orchestrator) discard(ctx context.Context, containerIDs []string) {
+	for _, id := range containerIDs {
+		o.runtime.Stop(ctx, id, 10*time.Second)
+		o.runtime.Remove(ctx, id, true)
+	}
+}
+
+// retireOld para as versões antigas e remove as que excedem a retenção
+// As "keep" versões mais recentes (com todas as suas réplicas) ficam paradas,
+// disponíveis para 'oi rollback'
+func (o *Orchestrator) retireOld(ctx context.Context, current []domain.Container, activeVersion string, keep int) {
+	old := domain.GroupByVersion(excludeVersion(current, activeVersion))
+	if len(old) == 0 {
+		return
+	}
+
+	fmt.Printf("🧹 Aposentando %d versão(ões) antiga(s) (mantendo %d para rollback)...\n", len(old), min(keep, len(old)))
+	for i, group := range old {
+		for _, c := range group.Containers {
+			if c.IsRunning() {
+				o.runtime.Stop(ctx, c.ID, 30*time.Second)
+			}
+			if i >= keep {
+				o.runtime.Remove(ctx, c.ID, false)
+			}
+		}
+	}
+}
+
+// sameUpstreams compara dois conjuntos de upstreams, ignorando a ordem
+func sameUpstreams(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]int, len(a))
+	for _, u := range a {
+		seen[u]++
+	}
+	for _, u := range b {
+		if seen[u] == 0 {
+			return false
+		}
+		seen[u]--
+	}
+	return true
+}
+
+// setActiveVersion registra a versão ativa do serviço, se houver StateStore
+// Falhas de persistência não invalidam o deploy, apenas geram aviso
+func (o *Orchestrator) setActiveVersion(ctx context.Context, project, service, version string) {
+	if o.state == nil {
+		return
+	}
+	if err := o.state.SetActiveVersion(ctx, project, service, version); err != nil {
+		fmt.Printf("⚠️  Aviso: falha ao registrar versão ativa: %v\n", err)
+	}
+}
+
+// activeVersion retorna a versão ativa registrada do serviço (vazio se desconhecida)
+func (o *Orchestrator) activeVersion(ctx context.Context, project, service string) string {
+	if o.state == nil {
+		return ""
+	}
+	version, err := o.state.ActiveVersion(ctx, project, service)
+	if err != nil {
+		fmt.Printf("⚠️  Aviso: falha ao ler versão ativa: %v\n", err)
+		return ""
+	}
+	return version
+}
+
 // Down remove todos os containers e recursos de um projeto (ou todos se project == "")
-func (o *Orchestrator) Down(ctx context.Context, project string) error {
+// Volumes nomeados só são removidos com removeVolumes (opt-in explícito, dados são perdidos)
+func (o *Orchestrator) Down(ctx context.Context, project string, removeVolumes bool) error {
 	label := project
 	if label == "" {
 		label = "TODOS OS PROJETOS"
@@ -183,11 +454,15 @@ func (o *Orchestrator) Down(ctx context.Context, project string) error {
 		o.runtime.Remove(ctx, c.ID, false)
 	}
 
-	// 3. Remover rotas do proxy
+	// 3. Remover rotas do proxy (as de todos os endereços de cada serviço público)
 	if o.proxy != nil {
+		removed := make(map[string]bool)
 		for _, c := range containers {
-			if domain := c.Project; domain != "" {
-				o.proxy.RemoveRoute(ctx, domain)
+			for _, route := range deployedRoutes(c, []domain.Container{c}) {
+				if !removed[route.ID] {
+					removed[route.ID] = true
+					o.proxy.RemoveRoute(ctx, route)
+				}
 			}
 		}
 	}
@@ -214,6 +489,20 @@ func (o *Orchestrator) Down(ctx context.Context, project string) error {
 		}
 	}
 
+	// 5. Remover volumes nomeados, se solicitado
+	if removeVolumes {
+		o.removeProjectVolumes(ctx, project)
+	}
+
+	// 6. Limpar estado da versão ativa (e canário em andamento) e registrar no histórico
+	for _, key := range uniqueServices(containers) {
+		o.setActiveVersion(ctx, key.project, key.service, "")
+		o.clearCanary(ctx, key.project, key.service)
+	}
+	for _, p := range uniqueProjects(containers) {
+		o.recordHistory(ctx, newRecord(domain.ActionDown, p), nil)
+	}
+
 	fmt.Printf("✅ Recursos de '%s' removidos com sucesso!\n", label)
 	return nil
 }
@@ -254,7 +543,16 @@ func (o *Orchestrator) Start(ctx context.Context, project string) error {
 		return nil
 	}
 
+	// Versões retidas para rollback permanecem paradas: só a versão ativa é iniciada
+	active := make(map[serviceKey]string)
+	for _, key := range uniqueServices(containers) {
+		active[key] = o.activeVersion(ctx, key.project, key.service)
+	}
+
 	for _, c := range containers {
+		if v := active[keyOf(c)]; v != "" && c.Version != v {
+			continue
+		}
 		if c.Status != domain.StatusRunning {
 			fmt.Printf("▶️  Iniciando %s...\n", c.Name)
 			if err := o.runtime.Start(ctx, c.ID); err != nil {
@@ -266,13 +564,41 @@ func (o *Orchestrator) Start(ctx context.Context, project string) error {
 	return nil
 }
 
+// uniqueProjects retorna os nomes de projeto distintos de uma lista de containers
+func uniqueProjects(containers []domain.Container) []string {
+	seen := make(map[string]bool)
+	var projects []string
+	for _, c := range containers {
+		if c.Project != "" && !seen[c.Project] {
+			seen[c.Project] = true
+			projects = append(projects, c.Project)
+		}
+	}
+	return projects
+}
+
 // Status retorna o estado atual de um projeto
 func (o *Orchestrator) Status(ctx context.Context, project string) ([]domain.Container, error) {
 	return o.runtime.List(ctx, project)
 }
 
+// verifySites verifica o DNS de cada host dos endereços (curingas não são verificados)
+func (o *Orchestrator) verifySites(sites []domain.Site) error {
+	seen := make(map[string]bool)
+	for _, site := range sites {
+		if site.IsWildcard() || seen[site.Host] {
+			continue
+		}
+		seen[site.Host] = true
+		if err := o.verifyDomain(site.Host); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // verifyDomain valida se o domínio está configurado corretamente
-// Evita falhas silenciosas na emissão de SSL pelo Caddy
+// Evita falhas silenciosas na emissão de SSL pelo proxy
 func (o *Orchestrator) verifyDomain(domain string) error {
 	// Bypass para desenvolvimento local
 	if strings.HasSuffix(domain, ".localhost") {
@@ -288,48 +614,60 @@ func (o *Orchestrator) verifyDomain(domain string) error {
 	return nil
 }
 
-// generateVersion gera um hash único para a versão
-func (o *Orchestrator) generateVersion(intent domain.Intent) string {
-	data := fmt.Sprintf("%s-%s-%s-%d-%s",
-		intent.Nome,
-		intent.Origem,
-		intent.Dominio,
-		intent.Porta,
-		time.Now().Format(time.RFC3339),
-	)
-	hash := sha256.Sum256([]byte(data))
-	return hex.EncodeToString(hash[:])
+// generateVersion gera a versão como hash de conteúdo da intenção normalizada e do
+// digest da imagem: a mesma intenção com a mesma imagem gera sempre a mesma versão
+// force mistura o horário atual, forçando uma versão nova a cada deploy
+func (o *Orchestrator) generateVersion(intent domain.Intent, digest string, live bool, force bool) string {
+	canonical, _ := json.Marshal(intent.Canonical())
+
+	h := sha256.New()
+	h.Write(canonical)
+	fmt.Fprintf(h, "\x00%s\x00live=%t", digest, live)
+	if force {
+		fmt.Fprintf(h, "\x00%s", time.Now().Format(time.RFC3339Nano))
+	}
+	return hex.EncodeToString(h.Sum(nil))
 }
 
 // Logs obtém o stream de logs do container principal do projeto
-func (o *Orchestrator) Logs(ctx context.Context, project string, stdout, stderr io.Writer, follow bool, tail string) error {
+// Em projetos com vários serviços e service vazio, acompanha todos os serviços
+// com o nome de cada um como prefixo
+func (o *Orchestrator) Logs(ctx context.Context, project string, service string, stdout, stderr io.Writer, follow bool, tail string) error {
 	containers, err := o.runtime.List(ctx, project)
 	if err != nil {
 		return fmt.Errorf("falha ao listar containers: %w", err)
 	}
 
+	if service != "" {
+		containers = filterService(containers, service)
+		if len(containers) == 0 {
+			return fmt.Errorf("nenhum container encontrado para o serviço '%s/%s'", project, service)
+		}
+	}
+
 	if len(containers) == 0 {
 		return fmt.Errorf("nenhum container encontrado para o projeto '%s'", project)
 	}
 
-	// Tenta encontrar um container rodando
-	var targetID string
-	var targetName string
+	keys := uniqueServices(containers)
+	if len(keys) > 1 {
+		return o.multiLogs(ctx, containers, keys, stdout, stderr, follow, tail)
+	}
 
+	target := logTarget(containers)
+	fmt.Printf("📜 Exibindo logs de '%s'...\n", target.Name)
+	return o.runtime.Logs(ctx, target.ID, stdout, stderr, follow, tail)
+}
+
+// logTarget escolhe o container cujos logs serão exibidos
+func logTarget(containers []domain.Container) domain.Container {
+	// Tenta encontrar um container rodando
 	for _, c := range containers {
 		if c.Status == domain.StatusRunning {
-			targetID = c.ID
-			targetName = c.Name
-			break
+			return c
 		}
 	}
 
 	// Se nenhum estiver rodando, pega o primeiro da lista (mais recente geralmente)
-	if targetID == "" {
-		targetID = containers[0].ID
-		targetName = containers[0].Name
-	}
-
-	fmt.Printf("📜 Exibindo logs de '%s'...\n", targetName)
-	return o.runtime.Logs(ctx, targetID, stdout, stderr, follow, tail)
+	return containers[0]
 }
@@ -0,0 +1,730 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/crom-tech/oi/internal/adapter/fake"
+	"github.com/crom-tech/oi/internal/adapter/state"
+	"github.com/crom-tech/oi/internal/core/domain"
+	"github.com/crom-tech/oi/internal/core/service"
+)
+
+// env reúne o orquestrador e os fakes de um teste
+type env struct {
+	ctx     context.Context
+	runtime *fake.Runtime
+	proxy   *fake.Proxy
+	state   *state.FileStore
+	orch    *service.Orchestrator
+}
+
+func newEnv(t *testing.T) *env {
+	t.Helper()
+	store, err := state.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &env{
+		ctx:     context.Background(),
+		runtime: fake.NewRuntime(),
+		proxy:   fake.NewProxy(),
+		state:   store,
+	}
+	e.orch = service.NewOrchestrator(e.runtime, e.proxy, e.state)
+	return e
+}
+
+// webIntent é um serviço público simples; mode diferencia as versões
+func webIntent(mode string) domain.Intent {
+	return domain.Intent{
+		Nome:     "loja",
+		Origem:   "nginx:1.25",
+		Dominio:  "loja.localhost",
+		Porta:    80,
+		Ambiente: map[string]string{"MODE": mode},
+	}
+}
+
+func (e *env) up(t *testing.T, intent domain.Intent) {
+	t.Helper()
+	if err := e.orch.Up(e.ctx, intent, service.UpOptions{}); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+}
+
+func (e *env) containers(t *testing.T, project string) []domain.Container {
+	t.Helper()
+	containers, err := e.runtime.List(e.ctx, project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return containers
+}
+
+// running retorna os containers rodando do projeto
+func (e *env) running(t *testing.T, project string) []domain.Container {
+	t.Helper()
+	var result []domain.Container
+	for _, c := range e.containers(t, project) {
+		if c.IsRunning() {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// upstreamsOf retorna os upstreams esperados para os containers
+func upstreamsOf(containers []domain.Container) []string {
+	var addresses []string
+	for _, c := range containers {
+		addresses = append(addresses, c.Name+":80")
+	}
+	return addresses
+}
+
+func (e *env) assertRoute(t *testing.T, domainName string, want []string) {
+	t.Helper()
+	route, ok := e.proxy.Route(domainName)
+	if !ok {
+		t.Fatalf("rota de %s não existe", domainName)
+	}
+	if got := route.Addresses(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("upstreams de %s = %v, esperado %v", domainName, got, want)
+	}
+}
+
+func (e *env) activeVersion(t *testing.T, project, svc string) string {
+	t.Helper()
+	version, err := e.state.ActiveVersion(e.ctx, project, svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return version
+}
+
+func TestUpBlueGreen(t *testing.T) {
+	e := newEnv(t)
+
+	e.up(t, webIntent("v1"))
+	blue := e.running(t, "loja")
+	if len(blue) != 1 {
+		t.Fatalf("esperado 1 container rodando, obtido %d", len(blue))
+	}
+	e.assertRoute(t, "loja.localhost", upstreamsOf(blue))
+	if got := e.activeVersion(t, "loja", ""); got != blue[0].Version {
+		t.Fatalf("versão ativa = %q, esperado %q", got, blue[0].Version)
+	}
+
+	// Mesma intenção e mesma imagem: nada é recriado
+	e.up(t, webIntent("v1"))
+	if n := len(e.containers(t, "loja")); n != 1 {
+		t.Fatalf("redeploy sem mudanças criou containers: %d", n)
+	}
+
+	// Nova intenção: o Green assume a rota e o Blue fica parado para rollback
+	e.up(t, webIntent("v2"))
+	green := e.running(t, "loja")
+	if len(green) != 1 || green[0].Version == blue[0].Version {
+		t.Fatalf("esperado só a nova versão rodando, obtido %+v", green)
+	}
+	e.assertRoute(t, "loja.localhost", upstreamsOf(green))
+	if n := len(e.containers(t, "loja")); n != 2 {
+		t.Fatalf("versão anterior não foi retida: %d containers", n)
+	}
+	if got := e.activeVersion(t, "loja", ""); got != green[0].Version {
+		t.Fatalf("versão ativa = %q, esperado %q", got, green[0].Version)
+	}
+}
+
+func TestUpNewImageDigestDeploysNewVersion(t *testing.T) {
+	e := newEnv(t)
+	e.up(t, webIntent("v1"))
+	before := e.running(t, "loja")[0]
+
+	digest := e.runtime.PublishImage("nginx:1.25")
+	e.up(t, webIntent("v1"))
+
+	after := e.running(t, "loja")
+	if len(after) != 1 || after[0].Version == before.Version {
+		t.Fatalf("imagem republicada não gerou nova versão")
+	}
+	if !strings.HasSuffix(after[0].Digest, digest) {
+		t.Fatalf("digest implantado = %q, esperado %q", after[0].Digest, digest)
+	}
+}
+
+func TestUpReplicas(t *testing.T) {
+	e := newEnv(t)
+	intent := webIntent("v1")
+	intent.Replicas = 3
+
+	e.up(t, intent)
+	replicas := e.running(t, "loja")
+	if len(replicas) != 3 {
+		t.Fatalf("esperado 3 réplicas, obtido %d", len(replicas))
+	}
+	e.assertRoute(t, "loja.localhost", upstreamsOf(replicas))
+}
+
+func TestUpHealthCheckFailureKeepsCurrentVersion(t *testing.T) {
+	e := newEnv(t)
+	e.up(t, webIntent("v1"))
+	blue := e.running(t, "loja")
+
+	e.runtime.Unhealthy = func(c domain.Container) bool { return c.Env["MODE"] == "v2" }
+	err := e.orch.Up(e.ctx, webIntent("v2"), service.UpOptions{})
+
+	var failed domain.ErrDeployFailed
+	if !errors.As(err, &failed) {
+		t.Fatalf("esperado ErrDeployFailed, obtido %v", err)
+	}
+	if all := e.containers(t, "loja"); len(all) != 1 || all[0].ID != blue[0].ID || !all[0].IsRunning() {
+		t.Fatalf("containers da versão com falha não foram descartados: %+v", all)
+	}
+	e.assertRoute(t, "loja.localhost", upstreamsOf(blue))
+	if got := e.activeVersion(t, "loja", ""); got != blue[0].Version {
+		t.Fatalf("versão ativa mudou após falha: %q", got)
+	}
+
+	history, err := e.state.History(e.ctx, "loja")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := history[len(history)-1]; last.Outcome != domain.OutcomeFailure {
+		t.Fatalf("falha não registrada no histórico: %+v", last)
+	}
+}
+
+func TestRollbackChecksTargetHealth(t *testing.T) {
+	e := newEnv(t)
+	var failing atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+	_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	e.runtime.IP = "127.0.0.1"
+
+	intent := webIntent("v1")
+	intent.Porta, _ = strconv.Atoi(port)
+	intent.HealthCheck = &domain.HealthCheck{Path: "/health", Interval: "10ms", Timeout: "200ms", Retries: 1}
+	e.up(t, intent)
+	intent.Ambiente = map[string]string{"MODE": "v2"}
+	intent.HealthCheck = nil
+	e.up(t, intent)
+	current := e.running(t, "loja")
+
+	// A versão alvo foi implantada com healthcheck: o rollback faz a mesma verificação HTTP
+	failing.Store(true)
+	err := e.orch.Rollback(e.ctx, "loja", "", "")
+	var failed domain.ErrDeployFailed
+	if !errors.As(err, &failed) {
+		t.Fatalf("esperado ErrDeployFailed, obtido %v", err)
+	}
+	if running := e.running(t, "loja"); len(running) != 1 || running[0].ID != current[0].ID {
+		t.Fatalf("versão alvo deveria ter sido parada: %+v", running)
+	}
+	e.assertRoute(t, "loja.localhost", []string{current[0].Name + ":" + port})
+
+	failing.Store(false)
+	if err := e.orch.Rollback(e.ctx, "loja", "", ""); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if running := e.running(t, "loja"); len(running) != 1 || running[0].ID == current[0].ID {
+		t.Fatalf("rollback não trocou a versão: %+v", running)
+	}
+}
+
+func TestUpPullFailure(t *testing.T) {
+	e := newEnv(t)
+	e.runtime.PullErr = errors.New("manifest unknown")
+
+	err := e.orch.Up(e.ctx, webIntent("v1"), service.UpOptions{})
+	if err == nil || !strings.Contains(err.Error(), "manifest unknown") {
+		t.Fatalf("esperado erro do pull, obtido %v", err)
+	}
+	if n := len(e.containers(t, "loja")); n != 0 {
+		t.Fatalf("pull com falha criou %d container(s)", n)
+	}
+	if _, ok := e.proxy.Route("loja.localhost"); ok {
+		t.Fatal("pull com falha criou rota")
+	}
+}
+
+func TestUpProxyUnavailable(t *testing.T) {
+	e := newEnv(t)
+	e.proxy.HealthErr = errors.New("connection refused")
+
+	if err := e.orch.Up(e.ctx, webIntent("v1"), service.UpOptions{}); err == nil {
+		t.Fatal("esperado erro com proxy fora do ar")
+	}
+	if n := len(e.containers(t, "loja")); n != 0 {
+		t.Fatalf("deploy com proxy fora do ar criou %d container(s)", n)
+	}
+}
+
+func TestUpRouteFailureIsOnlyAWarning(t *testing.T) {
+	e := newEnv(t)
+	e.proxy.AddRouteErr = errors.New("admin API recusou a rota")
+
+	// Os containers já estão saudáveis: o deploy segue e a rota pode ser reconciliada depois
+	e.up(t, webIntent("v1"))
+	if n := len(e.running(t, "loja")); n != 1 {
+		t.Fatalf("esperado 1 container rodando, obtido %d", n)
+	}
+	if _, ok := e.proxy.Route("loja.localhost"); ok {
+		t.Fatal("rota não deveria existir")
+	}
+
+	// Próximo 'oi up' sem mudanças reconcilia a rota
+	e.proxy.AddRouteErr = nil
+	e.up(t, webIntent("v1"))
+	e.assertRoute(t, "loja.localhost", upstreamsOf(e.running(t, "loja")))
+}
+
+func TestUpInternalServiceHasNoRoute(t *testing.T) {
+	e := newEnv(t)
+	intent := webIntent("v1")
+	intent.Dominio = ""
+
+	e.up(t, intent)
+	if routes := e.proxy.Routes(); len(routes) != 0 {
+		t.Fatalf("serviço interno recebeu rota: %+v", routes)
+	}
+	if c := e.running(t, "loja"); len(c) != 1 || c[0].PublicPort != 0 {
+		t.Fatalf("serviço interno não deveria publicar porta: %+v", c)
+	}
+}
+
+func TestUpWithoutProxyPublishesPort(t *testing.T) {
+	e := newEnv(t)
+	orch := service.NewOrchestrator(e.runtime, nil, nil)
+
+	if err := orch.Up(e.ctx, webIntent("v1"), service.UpOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if c := e.running(t, "loja"); len(c) != 1 || c[0].PublicPort == 0 {
+		t.Fatalf("sem proxy a porta deveria ser publicada: %+v", c)
+	}
+}
+
+func TestUpMultipleSitesAndPathPrefix(t *testing.T) {
+	e := newEnv(t)
+	intent := webIntent("v1")
+	intent.Dominios = []string{"www.loja.localhost", "*.loja.localhost", "loja.localhost/v2"}
+	intent.RemoverPrefixo = true
+	e.up(t, intent)
+
+	running := upstreamsOf(e.running(t, "loja"))
+	for _, site := range []string{"loja.localhost", "www.loja.localhost", "*.loja.localhost", "loja.localhost/v2"} {
+		e.assertRoute(t, site, running)
+	}
+	if routes := e.proxy.Routes(); len(routes) != 3 {
+		t.Fatalf("esperadas 3 rotas (hosts exatos, curinga e prefixo), obtidas %+v", routes)
+	}
+	if r, _ := e.proxy.Route("loja.localhost/v2"); !r.StripPrefix || r.ID != "oi-loja~v2" {
+		t.Fatalf("rota do prefixo inesperada: %+v", r)
+	}
+	if r, _ := e.proxy.Route("loja.localhost"); r.StripPrefix || r.ID != "oi-loja" {
+		t.Fatalf("rota principal inesperada: %+v", r)
+	}
+
+	// Endereços retirados do oi.json saem do proxy no próximo deploy
+	intent = webIntent("v2")
+	intent.Dominios = []string{"www.loja.localhost"}
+	e.up(t, intent)
+	running = upstreamsOf(e.running(t, "loja"))
+	e.assertRoute(t, "www.loja.localhost", running)
+	for _, site := range []string{"*.loja.localhost", "loja.localhost/v2"} {
+		if _, ok := e.proxy.Route(site); ok {
+			t.Fatalf("rota de %s deveria ter sido removida", site)
+		}
+	}
+
+	if err := e.orch.Down(e.ctx, "loja", false); err != nil {
+		t.Fatal(err)
+	}
+	if routes := e.proxy.Routes(); len(routes) != 0 {
+		t.Fatalf("Down deixou rotas: %+v", routes)
+	}
+}
+
+func TestUpProjectsShareHost(t *testing.T) {
+	e := newEnv(t)
+	e.up(t, webIntent("v1"))
+	api := webIntent("v1")
+	api.Nome = "api"
+	api.Dominio = "loja.localhost/api"
+	e.up(t, api)
+
+	e.assertRoute(t, "loja.localhost", upstreamsOf(e.running(t, "loja")))
+	e.assertRoute(t, "loja.localhost/api", upstreamsOf(e.running(t, "api")))
+
+	if err := e.orch.Down(e.ctx, "api", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := e.proxy.Route("loja.localhost/api"); ok {
+		t.Fatal("Down não removeu a rota do prefixo")
+	}
+	e.assertRoute(t, "loja.localhost", upstreamsOf(e.running(t, "loja")))
+}
+
+func TestDown(t *testing.T) {
+	e := newEnv(t)
+	intent := webIntent("v1")
+	intent.Volumes = map[string]string{"dados": "/data"}
+	e.up(t, intent)
+	intent.Ambiente["MODE"] = "v2"
+	e.up(t, intent)
+
+	if err := e.orch.Down(e.ctx, "loja", false); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(e.containers(t, "loja")); n != 0 {
+		t.Fatalf("Down deixou %d container(s)", n)
+	}
+	if _, ok := e.proxy.Route("loja.localhost"); ok {
+		t.Fatal("Down não removeu a rota")
+	}
+	if e.runtime.HasNetwork("loja") {
+		t.Fatal("Down não removeu a network")
+	}
+	if got := e.activeVersion(t, "loja", ""); got != "" {
+		t.Fatalf("Down não limpou a versão ativa: %q", got)
+	}
+
+	// Volumes só são removidos com removeVolumes
+	volumes, _ := e.runtime.ListVolumes(e.ctx, "loja")
+	if len(volumes) != 1 {
+		t.Fatalf("Down sem --volumes removeu volumes: %+v", volumes)
+	}
+	e.up(t, intent)
+	if err := e.orch.Down(e.ctx, "loja", true); err != nil {
+		t.Fatal(err)
+	}
+	if volumes, _ := e.runtime.ListVolumes(e.ctx, "loja"); len(volumes) != 0 {
+		t.Fatalf("Down com volumes manteve %+v", volumes)
+	}
+}
+
+func TestDownKeepsOtherProjects(t *testing.T) {
+	e := newEnv(t)
+	e.up(t, webIntent("v1"))
+	other := webIntent("v1")
+	other.Nome = "blog"
+	other.Dominio = "blog.localhost"
+	e.up(t, other)
+
+	if err := e.orch.Down(e.ctx, "loja", false); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(e.running(t, "blog")); n != 1 {
+		t.Fatalf("Down de outro projeto afetou blog: %d container(s)", n)
+	}
+	e.assertRoute(t, "blog.localhost", upstreamsOf(e.running(t, "blog")))
+}
+
+func TestStopAndStart(t *testing.T) {
+	e := newEnv(t)
+	e.up(t, webIntent("v1"))
+	e.up(t, webIntent("v2"))
+	active := e.running(t, "loja")[0]
+
+	if err := e.orch.Stop(e.ctx, "loja"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(e.running(t, "loja")); n != 0 {
+		t.Fatalf("Stop deixou %d container(s) rodando", n)
+	}
+
+	// Só a versão ativa volta: a retida para rollback continua parada
+	if err := e.orch.Start(e.ctx, "loja"); err != nil {
+		t.Fatal(err)
+	}
+	running := e.running(t, "loja")
+	if len(running) != 1 || running[0].ID != active.ID {
+		t.Fatalf("Start deveria iniciar só a versão ativa, obtido %+v", running)
+	}
+}
+
+func TestLogsPrefersRunningContainer(t *testing.T) {
+	e := newEnv(t)
+	e.up(t, webIntent("v1"))
+	e.up(t, webIntent("v2"))
+	active := e.running(t, "loja")[0]
+
+	var stdout, stderr bytes.Buffer
+	if err := e.orch.Logs(e.ctx, "loja", "", &stdout, &stderr, false, "all"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := stdout.String(), "log de "+active.Name+"\n"; got != want {
+		t.Fatalf("logs = %q, esperado %q", got, want)
+	}
+}
+
+func TestLogsMultiService(t *testing.T) {
+	e := newEnv(t)
+	project := domain.Intent{
+		Nome: "loja",
+		Servicos: map[string]domain.Intent{
+			"api": {Origem: "loja/api:1.0", Dominio: "loja.localhost", Porta: 8080, DependeDe: []string{"db"}},
+			"db":  {Origem: "postgres:16", Porta: 5432},
+		},
+	}
+	e.up(t, project)
+
+	var stdout, stderr bytes.Buffer
+	if err := e.orch.Logs(e.ctx, "loja", "", &stdout, &stderr, false, "all"); err != nil {
+		t.Fatal(err)
+	}
+	for _, prefix := range []string{"api | log de oi-loja-api-", "db  | log de oi-loja-db-"} {
+		if !strings.Contains(stdout.String(), prefix) {
+			t.Fatalf("logs sem %q:\n%s", prefix, stdout.String())
+		}
+	}
+
+	stdout.Reset()
+	if err := e.orch.Logs(e.ctx, "loja", "db", &stdout, &stderr, false, "all"); err != nil {
+		t.Fatal(err)
+	}
+	if got := stdout.String(); !strings.HasPrefix(got, "log de oi-loja-db-") {
+		t.Fatalf("logs do serviço db = %q", got)
+	}
+
+	if err := e.orch.Logs(e.ctx, "loja", "cache", &stdout, &stderr, false, "all"); err == nil {
+		t.Fatal("esperado erro para serviço inexistente")
+	}
+	if err := e.orch.Logs(e.ctx, "nada", "", &stdout, &stderr, false, "all"); err == nil {
+		t.Fatal("esperado erro para projeto sem containers")
+	}
+}
+
+func TestPlanWithoutChangesOnlyReconciles(t *testing.T) {
+	e := newEnv(t)
+	e.up(t, webIntent("v1"))
+	e.up(t, webIntent("v2"))
+	active := e.running(t, "loja")
+
+	plans, err := e.orch.Plan(e.ctx, webIntent("v2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan := plans[0]
+	if plan.Action != domain.PlanReconcile || plan.Current != active[0].Version {
+		t.Fatalf("plano = %+v; esperado reconcile da versão ativa", plan)
+	}
+	if len(plan.Changes) != 0 || len(plan.Stop) != 0 || len(plan.Remove) != 0 || len(plan.Routes) != 0 {
+		t.Fatalf("plano sem mudanças lista alterações: %+v", plan)
+	}
+
+	// Rota fora de sincronia: o plano mostra só a reconciliação do proxy
+	if err := e.proxy.RemoveRoute(e.ctx, domain.Route{ID: "oi-loja", Domain: "loja.localhost"}); err != nil {
+		t.Fatal(err)
+	}
+	plans, err = e.orch.Plan(e.ctx, webIntent("v2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := domain.RouteChange{Action: domain.RouteAdd, Domain: "loja.localhost", To: upstreamsOf(active)[0]}
+	if plan := plans[0]; plan.Action != domain.PlanReconcile || len(plan.Routes) != 1 || plan.Routes[0] != want || len(plan.Stop) != 0 {
+		t.Fatalf("plano = %+v; esperada só a rota %+v", plan, want)
+	}
+
+	// Nova imagem na mesma tag: 'oi up' faria um novo deploy
+	digest := e.runtime.PublishImage("nginx:1.25")
+	plans, err = e.orch.Plan(e.ctx, webIntent("v2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan := plans[0]; plan.Action != domain.PlanUpdate || len(plan.Changes) != 1 || plan.Changes[0].Field != "imagem" || plan.Changes[0].To != digest {
+		t.Fatalf("plano = %+v; esperada atualização da imagem para %s", plan, digest)
+	}
+	if plan := plans[0]; len(plan.Stop) != 1 || plan.Stop[0] != active[0].Name {
+		t.Fatalf("Stop = %v; esperado %s", plan.Stop, active[0].Name)
+	}
+}
+
+// weightsOf retorna os upstreams da rota no formato "host:porta=peso"
+func (e *env) weightsOf(t *testing.T, domainName string) []string {
+	t.Helper()
+	route, ok := e.proxy.Route(domainName)
+	if !ok {
+		t.Fatalf("rota de %s não existe", domainName)
+	}
+	var weights []string
+	for _, u := range route.Upstreams {
+		weights = append(weights, u.Address()+"="+strconv.Itoa(u.Weight))
+	}
+	return weights
+}
+
+// startCanary implanta v1 e inicia o canário de v2 com weight% do tráfego
+// Retorna as réplicas estável e canário
+func (e *env) startCanary(t *testing.T, weight int) (domain.Container, domain.Container) {
+	t.Helper()
+	e.up(t, webIntent("v1"))
+	stable := e.running(t, "loja")[0]
+	if err := e.orch.Up(e.ctx, webIntent("v2"), service.UpOptions{Canary: weight}); err != nil {
+		t.Fatalf("Up com canário: %v", err)
+	}
+	for _, c := range e.running(t, "loja") {
+		if c.ID != stable.ID {
+			return stable, c
+		}
+	}
+	t.Fatal("réplica do canário não está rodando")
+	return domain.Container{}, domain.Container{}
+}
+
+func TestCanary(t *testing.T) {
+	tests := []struct {
+		name string
+		// finish conclui o canário (promote ou abort)
+		finish func(e *env) error
+		// promoted indica se a versão do canário deve ficar ativa
+		promoted bool
+	}{
+		{name: "promote", finish: func(e *env) error { return e.orch.Promote(e.ctx, "loja", "") }, promoted: true},
+		{name: "abort", finish: func(e *env) error { return e.orch.Abort(e.ctx, "loja", "") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newEnv(t)
+			stable, canary := e.startCanary(t, 20)
+
+			// As duas versões dividem a rota por peso; a estável continua ativa
+			want := []string{stable.Name + ":80=80", canary.Name + ":80=20"}
+			if got := e.weightsOf(t, "loja.localhost"); strings.Join(got, ",") != strings.Join(want, ",") {
+				t.Fatalf("upstreams = %v; esperado %v", got, want)
+			}
+			if got := e.activeVersion(t, "loja", ""); got != stable.Version {
+				t.Fatalf("versão ativa = %q durante o canário; esperado a estável", got)
+			}
+			canaries, err := e.orch.Canaries(e.ctx, "loja")
+			if err != nil || len(canaries) != 1 || canaries[0].Version != canary.Version || canaries[0].Stable != stable.Version || canaries[0].Weight != 20 {
+				t.Fatalf("Canaries = %+v, %v", canaries, err)
+			}
+
+			// Novos deploys e rollbacks esperam o canário ser concluído
+			if err := e.orch.Up(e.ctx, webIntent("v3"), service.UpOptions{}); err == nil || !strings.Contains(err.Error(), "oi promote") {
+				t.Fatalf("Up durante o canário = %v; esperado bloqueio", err)
+			}
+			if err := e.orch.Rollback(e.ctx, "loja", "", ""); err == nil || !strings.Contains(err.Error(), "oi promote") {
+				t.Fatalf("Rollback durante o canário = %v; esperado bloqueio", err)
+			}
+
+			if err := tt.finish(e); err != nil {
+				t.Fatal(err)
+			}
+
+			winner, loser := stable, canary
+			if tt.promoted {
+				winner, loser = canary, stable
+			}
+			e.assertRoute(t, "loja.localhost", upstreamsOf([]domain.Container{winner}))
+			if running := e.running(t, "loja"); len(running) != 1 || running[0].ID != winner.ID {
+				t.Fatalf("rodando = %+v; esperado só %s", running, winner.Name)
+			}
+			if got := e.activeVersion(t, "loja", ""); got != winner.Version {
+				t.Fatalf("versão ativa = %q; esperado %q", got, winner.Version)
+			}
+			if c, err := e.state.Canary(e.ctx, "loja", ""); err != nil || c != nil {
+				t.Fatalf("canário não foi limpo: %+v, %v", c, err)
+			}
+
+			// A versão estável fica retida para rollback; a do canário abortado é descartada
+			retained := false
+			for _, c := range e.containers(t, "loja") {
+				retained = retained || c.ID == loser.ID
+			}
+			if retained != tt.promoted {
+				t.Fatalf("versão %s retida = %v; esperado %v", loser.Name, retained, tt.promoted)
+			}
+
+			e.up(t, webIntent("v3"))
+		})
+	}
+}
+
+func TestCanaryWithoutStableVersionDeploysFully(t *testing.T) {
+	e := newEnv(t)
+	if err := e.orch.Up(e.ctx, webIntent("v1"), service.UpOptions{Canary: 20}); err != nil {
+		t.Fatal(err)
+	}
+	e.assertRoute(t, "loja.localhost", upstreamsOf(e.running(t, "loja")))
+	if c, _ := e.state.Canary(e.ctx, "loja", ""); c != nil {
+		t.Fatalf("primeiro deploy não deveria ser canário: %+v", c)
+	}
+}
+
+func TestDownClearsCanary(t *testing.T) {
+	e := newEnv(t)
+	e.startCanary(t, 10)
+
+	if err := e.orch.Down(e.ctx, "loja", false); err != nil {
+		t.Fatal(err)
+	}
+	if c, err := e.state.Canary(e.ctx, "loja", ""); err != nil || c != nil {
+		t.Fatalf("Down deixou o canário: %+v, %v", c, err)
+	}
+	e.up(t, webIntent("v1"))
+}
+
+func TestCanaryWithoutContainersIsDropped(t *testing.T) {
+	e := newEnv(t)
+	_, canary := e.startCanary(t, 10)
+
+	// Containers do canário removidos por fora do OI
+	if err := e.runtime.Remove(e.ctx, canary.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.orch.Promote(e.ctx, "loja", ""); err == nil || !strings.Contains(err.Error(), "nenhum canário") {
+		t.Fatalf("Promote = %v; esperado nenhum canário em andamento", err)
+	}
+	e.up(t, webIntent("v3"))
+	if c, _ := e.state.Canary(e.ctx, "loja", ""); c != nil {
+		t.Fatalf("canário sem containers não foi descartado: %+v", c)
+	}
+}
+
+// canaryFailingStore é um StateStore que não consegue registrar canários
+type canaryFailingStore struct {
+	*state.FileStore
+}
+
+func (s canaryFailingStore) SetCanary(ctx context.Context, project, service string, canary *domain.Canary) error {
+	if canary != nil {
+		return errors.New("disco cheio")
+	}
+	return s.FileStore.SetCanary(ctx, project, service, canary)
+}
+
+func TestCanaryStateFailureRestoresStableRoute(t *testing.T) {
+	e := newEnv(t)
+	e.up(t, webIntent("v1"))
+	stable := e.running(t, "loja")
+
+	orch := service.NewOrchestrator(e.runtime, e.proxy, canaryFailingStore{e.state})
+	err := orch.Up(e.ctx, webIntent("v2"), service.UpOptions{Canary: 20})
+	if err == nil || !strings.Contains(err.Error(), "disco cheio") {
+		t.Fatalf("Up = %v; esperado erro ao registrar o canário", err)
+	}
+	if got, want := e.weightsOf(t, "loja.localhost"), []string{stable[0].Name + ":80=0"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("upstreams = %v; esperado só a versão estável %v", got, want)
+	}
+	if all := e.containers(t, "loja"); len(all) != 1 || all[0].ID != stable[0].ID {
+		t.Fatalf("réplicas do canário não foram descartadas: %+v", all)
+	}
+}
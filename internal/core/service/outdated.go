@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// Outdated compara o digest implantado de cada serviço com o que o registry serve
+// hoje para a mesma tag, sem baixar nada
+// Serviços com imagem diferente no registry mudariam no próximo 'oi up'
+func (o *Orchestrator) Outdated(ctx context.Context, project string) ([]domain.ImageDrift, error) {
+	containers, err := o.runtime.List(ctx, project)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar containers: %w", err)
+	}
+
+	// Cada tag é consultada uma vez, mesmo se usada por vários serviços
+	latest := make(map[string]string)
+	failures := make(map[string]string)
+
+	var drifts []domain.ImageDrift
+	for _, key := range uniqueServices(containers) {
+		var service []domain.Container
+		for _, c := range containers {
+			if keyOf(c) == key {
+				service = append(service, c)
+			}
+		}
+
+		active := o.findActive(ctx, key.project, key.service, service)
+		if active == nil {
+			continue
+		}
+
+		// A listagem mostra o ID da imagem quando a tag já aponta para outra;
+		// a inspeção traz a referência usada na criação (ex: "nginx:alpine")
+		if ctr, err := o.runtime.Inspect(ctx, active.ID); err == nil {
+			active = ctr
+		}
+
+		drift := domain.ImageDrift{
+			Project: key.project,
+			Service: key.service,
+			Image:   active.Image,
+			Running: active.Digest,
+			Built:   domain.IsBuiltDigest(active.Digest),
+		}
+		if drift.Built {
+			drifts = append(drifts, drift)
+			continue
+		}
+
+		if _, done := latest[active.Image]; !done {
+			digest, err := o.runtime.RemoteDigest(ctx, active.Image)
+			if err != nil {
+				failures[active.Image] = err.Error()
+			}
+			latest[active.Image] = digest
+		}
+		drift.Latest = latest[active.Image]
+		drift.Error = failures[active.Image]
+
+		drifts = append(drifts, drift)
+	}
+	return drifts, nil
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// Plan compara a intenção com o que está rodando e descreve o que 'oi up' faria
+// Retorna um plano por serviço (um único plano em projetos de serviço único)
+// Não altera containers, networks nem rotas
+func (o *Orchestrator) Plan(ctx context.Context, intent domain.Intent) ([]*domain.Plan, error) {
+	services := []domain.Intent{intent}
+	if intent.IsMultiService() {
+		var err error
+		if services, err = intent.ServicesInOrder(); err != nil {
+			return nil, err
+		}
+	}
+
+	all, err := o.runtime.List(ctx, intent.Nome)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar containers: %w", err)
+	}
+
+	plans := make([]*domain.Plan, 0, len(services))
+	for _, svc := range services {
+		plan, err := o.planService(ctx, svc, filterService(all, svc.Servico))
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// planService planeja o deploy de um único serviço
+func (o *Orchestrator) planService(ctx context.Context, intent domain.Intent, containers []domain.Container) (*domain.Plan, error) {
+	plan := &domain.Plan{Project: intent.Nome, Service: intent.Servico, Action: domain.PlanCreate}
+	intent.Origem = intent.ImageTag()
+
+	// 1. Encontrar o container ativo (versão registrada ou o que está rodando)
+	active := o.findActive(ctx, intent.Nome, intent.Servico, containers)
+
+	name := intent.Nome
+	if intent.Servico != "" {
+		name += "-" + intent.Servico
+	}
+	newUpstream := fmt.Sprintf("oi-%s-<nova>:%d", name, intent.InternalPort())
+	if replicas := intent.ReplicasOrDefault(); replicas > 1 {
+		newUpstream = fmt.Sprintf("%s (x%d, %s)", newUpstream, replicas, intent.PolicyOrDefault())
+	}
+
+	if active == nil {
+		plan.Changes = diffIntent(intent, nil)
+	} else {
+		current, err := o.runtime.Inspect(ctx, active.ID)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao inspecionar container: %w", err)
+		}
+		plan.Current = current.Version
+		plan.Changes = diffIntent(intent, current)
+		plan.Changes = append(plan.Changes, diffReplicas(intent, current, filterVersion(containers, active.Version))...)
+		plan.Action = domain.PlanUpdate
+
+		// 1.1. Mesma versão (intenção + imagem) já saudável: 'oi up' só reconcilia o proxy
+		healthy, change, err := o.planReconcile(ctx, intent, current, filterVersion(containers, active.Version))
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			plan.Changes = append(plan.Changes, *change)
+		}
+		if healthy != nil {
+			plan.Action = domain.PlanReconcile
+			// Outras versões só mudam se estiverem rodando (ex: após 'oi start'), como em reconcileExisting
+			plan.Stop, plan.Remove = planRetire(excludeVersion(containers, active.Version), intent.RetentionOrDefault())
+			if o.proxy != nil && intent.IsPublic() {
+				if plan.Routes, err = o.planRouteSync(ctx, routesFor(intent, healthy)); err != nil {
+					return nil, err
+				}
+			}
+			return plan, nil
+		}
+	}
+
+	// O resultado do build só é conhecido ao construir: o plano apenas sinaliza
+	if intent.Build != nil {
+		plan.Changes = append(plan.Changes, domain.PlanChange{
+			Field: "build",
+			From:  "",
+			To:    intent.Build.Context,
+		})
+	}
+
+	// 2. Containers afetados pela aposentadoria (a nova versão substitui todas as atuais)
+	plan.Stop, plan.Remove = planRetire(containers, intent.RetentionOrDefault())
+
+	// 3. Rotas do proxy (apenas serviços públicos), uma por grupo de endereços
+	if o.proxy != nil && intent.IsPublic() {
+		routes := routesFor(intent, nil)
+		planned := make(map[domain.Site]bool)
+		for _, route := range routes {
+			for _, site := range route.Sites() {
+				planned[site] = true
+			}
+			upstreams, err := o.proxy.GetUpstreams(ctx, route.Site())
+			if err != nil {
+				return nil, fmt.Errorf("falha ao consultar proxy: %w", err)
+			}
+			change := domain.RouteChange{Action: domain.RouteAdd, Domain: route.Label(), To: newUpstream}
+			if len(upstreams) > 0 {
+				change.Action = domain.RouteUpdate
+				change.From = strings.Join(upstreams, ", ")
+			}
+			plan.Routes = append(plan.Routes, change)
+		}
+
+		// Rotas da versão ativa cujo endereço principal saiu da intenção
+		if active != nil {
+			for _, route := range deployedRoutes(*active, []domain.Container{*active}) {
+				if planned[domain.Site{Host: route.Domain, Path: route.PathPrefix}] {
+					continue
+				}
+				if old, err := o.proxy.GetUpstreams(ctx, route.Site()); err == nil && len(old) > 0 {
+					plan.Routes = append(plan.Routes, domain.RouteChange{
+						Action: domain.RouteRemove,
+						Domain: route.Label(),
+						From:   strings.Join(old, ", "),
+					})
+				}
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// planReconcile verifica se 'oi up' manteria a versão ativa, como em upService: mesma
+// intenção, mesma imagem e todas as réplicas saudáveis. Retorna as réplicas (nil se um
+// novo deploy seria feito) e, se o registry servir outra imagem na tag, a mudança
+// Com build, o ID da imagem só é conhecido ao construir: sempre planeja um deploy
+func (o *Orchestrator) planReconcile(ctx context.Context, intent domain.Intent, current *domain.Container, group []domain.Container) ([]domain.Container, *domain.PlanChange, error) {
+	// Outra tag já aparece como mudança de origem e sempre gera uma nova versão
+	if intent.Build != nil || intent.Origem != current.Image {
+		return nil, nil, nil
+	}
+
+	// Sem acesso ao registry, considera a imagem implantada (o pull manteria a mesma)
+	digest := current.DeployedDigest()
+	var change *domain.PlanChange
+	if latest, err := o.runtime.RemoteDigest(ctx, intent.Origem); err == nil && latest != domain.DigestHash(digest) {
+		change = &domain.PlanChange{Field: "imagem", From: domain.DigestHash(digest), To: latest}
+		digest = latest
+	}
+
+	intent.Digest = digest
+	if o.generateVersion(intent, digest, false, false) != current.Version {
+		return nil, change, nil
+	}
+
+	healthy := make([]domain.Container, 0, len(group))
+	for _, c := range group {
+		ctr, err := o.runtime.Inspect(ctx, c.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("falha ao inspecionar container: %w", err)
+		}
+		if !ctr.IsHealthy() {
+			return nil, nil, nil
+		}
+		healthy = append(healthy, *ctr)
+	}
+	if len(healthy) != intent.ReplicasOrDefault() {
+		return nil, nil, nil
+	}
+	return healthy, nil, nil
+}
+
+// planRouteSync lista as rotas que não apontam para os upstreams esperados
+func (o *Orchestrator) planRouteSync(ctx context.Context, routes []domain.Route) ([]domain.RouteChange, error) {
+	var changes []domain.RouteChange
+	for _, route := range routes {
+		upstreams, err := o.proxy.GetUpstreams(ctx, route.Site())
+		if err != nil {
+			return nil, fmt.Errorf("falha ao consultar proxy: %w", err)
+		}
+		if sameUpstreams(upstreams, route.Addresses()) {
+			continue
+		}
+		change := domain.RouteChange{Action: domain.RouteAdd, Domain: route.Label(), To: strings.Join(route.Addresses(), ", ")}
+		if len(upstreams) > 0 {
+			change.Action = domain.RouteUpdate
+			change.From = strings.Join(upstreams, ", ")
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// planRetire lista os containers que retireOld pararia e removeria (containers não
+// incluem a versão que fica ativa)
+func planRetire(containers []domain.Container, keep int) (stop, remove []string) {
+	for i, group := range domain.GroupByVersion(containers) {
+		for _, c := range group.Containers {
+			if c.IsRunning() {
+				stop = append(stop, c.Name)
+			}
+			if i >= keep {
+				remove = append(remove, c.Name)
+			}
+		}
+	}
+	return stop, remove
+}
+
+// findActive retorna o container da versão ativa do serviço (nil se não houver)
+// containers deve conter apenas containers do serviço
+func (o *Orchestrator) findActive(ctx context.Context, project, service string, containers []domain.Container) *domain.Container {
+	if version := o.activeVersion(ctx, project, service); version != "" {
+		for i := range containers {
+			if containers[i].Version == version {
+				return &containers[i]
+			}
+		}
+	}
+	for i := range containers {
+		if containers[i].IsRunning() {
+			return &containers[i]
+		}
+	}
+	return nil
+}
+
+// diffIntent lista as diferenças entre a intenção e o container atual
+// Com current nil, todos os campos declarados aparecem como novos
+func diffIntent(intent domain.Intent, current *domain.Container) []domain.PlanChange {
+	if current == nil {
+		current = &domain.Container{}
+	}
+
+	var changes []domain.PlanChange
+	add := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, domain.PlanChange{Field: field, From: from, To: to})
+		}
+	}
+
+	add("origem", current.Image, intent.Origem)
+	add("dominio", current.Domain, domain.JoinSites(intent.Sites()))
+	add("remover_prefixo", boolString(current.StripPrefix), boolString(intent.RemoverPrefixo))
+	add("porta", portString(current.Port), portString(intent.Porta))
+	add("recursos.cpu", domain.FormatCPU(current.NanoCPUs), domain.FormatCPU(intent.Recursos.NanoCPUs()))
+	add("recursos.memoria", domain.FormatMemory(current.Memory), domain.FormatMemory(intent.Recursos.MemoryBytes()))
+
+	keys := make([]string, 0, len(intent.Ambiente))
+	for k := range intent.Ambiente {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		old, ok := current.Env[k]
+		switch {
+		case !ok:
+			add("ambiente."+k, "(ausente)", "(definida)")
+		case old != intent.Ambiente[k]:
+			add("ambiente."+k, "(valor atual)", "(novo valor)")
+		}
+	}
+
+	names := make(map[string]bool)
+	for name := range intent.Volumes {
+		names[name] = true
+	}
+	for name := range current.Volumes {
+		names[name] = true
+	}
+	volumes := make([]string, 0, len(names))
+	for name := range names {
+		volumes = append(volumes, name)
+	}
+	sort.Strings(volumes)
+	for _, name := range volumes {
+		add("volumes."+name, current.Volumes[name], intent.Volumes[name])
+	}
+
+	return changes
+}
+
+// diffReplicas compara o número de réplicas e a política de balanceamento
+// current é a réplica inspecionada e group são todas as réplicas da versão ativa
+func diffReplicas(intent domain.Intent, current *domain.Container, group []domain.Container) []domain.PlanChange {
+	var changes []domain.PlanChange
+	if from, to := len(group), intent.ReplicasOrDefault(); from != to {
+		changes = append(changes, domain.PlanChange{Field: "replicas", From: strconv.Itoa(from), To: strconv.Itoa(to)})
+	}
+	if current.Policy != intent.Balanceamento {
+		changes = append(changes, domain.PlanChange{Field: "balanceamento", From: current.Policy, To: intent.Balanceamento})
+	}
+	return changes
+}
+
+// sortedByNewest retorna uma cópia dos containers, mais recentes primeiro
+func sortedByNewest(containers []domain.Container) []domain.Container {
+	sorted := append([]domain.Container(nil), containers...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.After(sorted[j].CreatedAt)
+	})
+	return sorted
+}
+
+func boolString(value bool) string {
+	if !value {
+		return ""
+	}
+	return "true"
+}
+
+func portString(port int) string {
+	if port == 0 {
+		return ""
+	}
+	return strconv.Itoa(port)
+}
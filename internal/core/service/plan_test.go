@@ -0,0 +1,247 @@
+package service_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+	"github.com/crom-tech/oi/internal/core/service"
+)
+
+func TestPlan(t *testing.T) {
+	retention := 1
+
+	tests := []struct {
+		name string
+		// deployed são as intenções implantadas antes do plano, em ordem
+		deployed []domain.Intent
+		intent   domain.Intent
+		noProxy  bool
+		// want monta o plano esperado a partir dos containers implantados
+		want func(containers []domain.Container) domain.Plan
+	}{
+		{
+			name:   "novo deploy",
+			intent: webIntent("v1"),
+			want: func(containers []domain.Container) domain.Plan {
+				return domain.Plan{
+					Project: "loja",
+					Action:  domain.PlanCreate,
+					Changes: []domain.PlanChange{
+						{Field: "origem", To: "nginx:1.25"},
+						{Field: "dominio", To: "loja.localhost"},
+						{Field: "porta", To: "80"},
+						{Field: "ambiente.MODE", From: "(ausente)", To: "(definida)"},
+					},
+					Routes: []domain.RouteChange{
+						{Action: domain.RouteAdd, Domain: "loja.localhost", To: "oi-loja-<nova>:80"},
+					},
+				}
+			},
+		},
+		{
+			name:    "novo deploy sem proxy",
+			intent:  webIntent("v1"),
+			noProxy: true,
+			want: func(containers []domain.Container) domain.Plan {
+				return domain.Plan{
+					Project: "loja",
+					Action:  domain.PlanCreate,
+					Changes: []domain.PlanChange{
+						{Field: "origem", To: "nginx:1.25"},
+						{Field: "dominio", To: "loja.localhost"},
+						{Field: "porta", To: "80"},
+						{Field: "ambiente.MODE", From: "(ausente)", To: "(definida)"},
+					},
+				}
+			},
+		},
+		{
+			name:     "atualização de ambiente e porta",
+			deployed: []domain.Intent{webIntent("v1")},
+			intent: func() domain.Intent {
+				i := webIntent("v2")
+				i.Porta = 8080
+				i.Ambiente["NOVA"] = "1"
+				return i
+			}(),
+			want: func(containers []domain.Container) domain.Plan {
+				active := containers[0]
+				return domain.Plan{
+					Project: "loja",
+					Action:  domain.PlanUpdate,
+					Current: active.Version,
+					Changes: []domain.PlanChange{
+						{Field: "porta", From: "80", To: "8080"},
+						{Field: "ambiente.MODE", From: "(valor atual)", To: "(novo valor)"},
+						{Field: "ambiente.NOVA", From: "(ausente)", To: "(definida)"},
+					},
+					Stop: []string{active.Name},
+					Routes: []domain.RouteChange{
+						{Action: domain.RouteUpdate, Domain: "loja.localhost", From: active.Name + ":80", To: "oi-loja-<nova>:8080"},
+					},
+				}
+			},
+		},
+		{
+			name:     "réplicas, balanceamento e imagem",
+			deployed: []domain.Intent{webIntent("v1")},
+			intent: func() domain.Intent {
+				i := webIntent("v1")
+				i.Origem = "nginx:1.27"
+				i.Replicas = 2
+				i.Balanceamento = domain.PolicyLeastConn
+				return i
+			}(),
+			want: func(containers []domain.Container) domain.Plan {
+				active := containers[0]
+				return domain.Plan{
+					Project: "loja",
+					Action:  domain.PlanUpdate,
+					Current: active.Version,
+					Changes: []domain.PlanChange{
+						{Field: "origem", From: "nginx:1.25", To: "nginx:1.27"},
+						{Field: "replicas", From: "1", To: "2"},
+						{Field: "balanceamento", To: domain.PolicyLeastConn},
+					},
+					Stop: []string{active.Name},
+					Routes: []domain.RouteChange{
+						{Action: domain.RouteUpdate, Domain: "loja.localhost", From: active.Name + ":80", To: "oi-loja-<nova>:80 (x2, least_conn)"},
+					},
+				}
+			},
+		},
+		{
+			name: "endereços adicionados e retirados",
+			deployed: func() []domain.Intent {
+				i := webIntent("v1")
+				i.Dominios = []string{"loja.localhost/v2"}
+				return []domain.Intent{i}
+			}(),
+			intent: func() domain.Intent {
+				i := webIntent("v1")
+				i.Dominios = []string{"www.loja.localhost"}
+				return i
+			}(),
+			want: func(containers []domain.Container) domain.Plan {
+				active := containers[0]
+				return domain.Plan{
+					Project: "loja",
+					Action:  domain.PlanUpdate,
+					Current: active.Version,
+					Changes: []domain.PlanChange{
+						{Field: "dominio", From: "loja.localhost,loja.localhost/v2", To: "loja.localhost,www.loja.localhost"},
+					},
+					Stop: []string{active.Name},
+					Routes: []domain.RouteChange{
+						{Action: domain.RouteUpdate, Domain: "loja.localhost, www.loja.localhost", From: active.Name + ":80", To: "oi-loja-<nova>:80"},
+						{Action: domain.RouteRemove, Domain: "loja.localhost/v2", From: active.Name + ":80"},
+					},
+				}
+			},
+		},
+		{
+			name: "retenção",
+			deployed: func() []domain.Intent {
+				var deployed []domain.Intent
+				for _, mode := range []string{"v1", "v2", "v3"} {
+					i := webIntent(mode)
+					i.Retencao = &retention
+					deployed = append(deployed, i)
+				}
+				return deployed
+			}(),
+			intent: func() domain.Intent {
+				i := webIntent("v4")
+				i.Retencao = &retention
+				return i
+			}(),
+			want: func(containers []domain.Container) domain.Plan {
+				// v1 já saiu na aposentadoria do deploy de v3
+				retained, active := containers[0], containers[1]
+				return domain.Plan{
+					Project: "loja",
+					Action:  domain.PlanUpdate,
+					Current: active.Version,
+					Changes: []domain.PlanChange{
+						{Field: "ambiente.MODE", From: "(valor atual)", To: "(novo valor)"},
+					},
+					Stop:   []string{active.Name},
+					Remove: []string{retained.Name},
+					Routes: []domain.RouteChange{
+						{Action: domain.RouteUpdate, Domain: "loja.localhost", From: active.Name + ":80", To: "oi-loja-<nova>:80"},
+					},
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newEnv(t)
+			for _, intent := range tt.deployed {
+				e.up(t, intent)
+			}
+			containers := e.containers(t, "loja")
+			routes := e.proxy.Routes()
+
+			orch := e.orch
+			if tt.noProxy {
+				orch = service.NewOrchestrator(e.runtime, nil, e.state)
+			}
+			plans, err := orch.Plan(e.ctx, tt.intent)
+			if err != nil {
+				t.Fatalf("Plan: %v", err)
+			}
+			if len(plans) != 1 {
+				t.Fatalf("Plan retornou %d planos; esperado 1", len(plans))
+			}
+			if want := tt.want(containers); !reflect.DeepEqual(*plans[0], want) {
+				t.Errorf("plano =\n%+v\nesperado\n%+v", *plans[0], want)
+			}
+
+			// O plano não altera containers nem rotas
+			if after := e.containers(t, "loja"); !reflect.DeepEqual(after, containers) {
+				t.Errorf("Plan alterou os containers: %+v", after)
+			}
+			if after := e.proxy.Routes(); !reflect.DeepEqual(after, routes) {
+				t.Errorf("Plan alterou as rotas: %+v", after)
+			}
+		})
+	}
+}
+
+func TestPlanMultiService(t *testing.T) {
+	e := newEnv(t)
+	project := domain.Intent{
+		Nome: "loja",
+		Servicos: map[string]domain.Intent{
+			"api": {Origem: "loja/api:1.0", Dominio: "loja.localhost", Porta: 8080, DependeDe: []string{"db"}},
+			"db":  {Origem: "postgres:16", Porta: 5432},
+		},
+	}
+	e.up(t, project)
+
+	api := project.Servicos["api"]
+	api.Origem = "loja/api:1.1"
+	project.Servicos["api"] = api
+	plans, err := e.orch.Plan(e.ctx, project)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Um plano por serviço, em ordem de dependência
+	if len(plans) != 2 || plans[0].Service != "db" || plans[1].Service != "api" {
+		t.Fatalf("planos = %+v; esperado db e api", plans)
+	}
+	if db := plans[0]; db.Action != domain.PlanReconcile || len(db.Routes) != 0 {
+		t.Errorf("plano de db = %+v; esperado só reconciliar, sem rotas", db)
+	}
+	want := []domain.PlanChange{{Field: "origem", From: "loja/api:1.0", To: "loja/api:1.1"}}
+	if api := plans[1]; api.Action != domain.PlanUpdate || !reflect.DeepEqual(api.Changes, want) {
+		t.Errorf("plano de api = %+v; esperado %+v", api, want)
+	}
+	if api := plans[1]; len(api.Routes) != 1 || api.Routes[0].To != "oi-loja-api-<nova>:8080" {
+		t.Errorf("rotas de api = %+v", api.Routes)
+	}
+}
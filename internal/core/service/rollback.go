@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// Rollback volta um serviço para uma versão retida (parada) usando o mesmo fluxo Blue-Green
+// Se toVersion for vazio, usa a versão retida mais recente
+// service só pode ser omitido em projetos de serviço único
+func (o *Orchestrator) Rollback(ctx context.Context, project string, service string, toVersion string) (err error) {
+	record := newRecord(domain.ActionRollback, project)
+	record.Service = service
+	defer func() { o.recordHistory(ctx, record, err) }()
+
+	containers, err := o.runtime.List(ctx, project)
+	if err != nil {
+		return fmt.Errorf("falha ao listar containers: %w", err)
+	}
+	if len(containers) == 0 {
+		return fmt.Errorf("nenhum container encontrado para o projeto '%s'", project)
+	}
+
+	if service == "" && len(uniqueServices(containers)) > 1 {
+		return fmt.Errorf("projeto '%s' tem vários serviços, especifique --service", project)
+	}
+	containers = filterService(containers, service)
+	if len(containers) == 0 {
+		return fmt.Errorf("nenhum container encontrado para o serviço '%s/%s'", project, service)
+	}
+
+	// Um canário em andamento precisa ser promovido ou abortado antes, senão a
+	// conclusão dele sobrescreveria as rotas do rollback
+	if canary := o.canary(ctx, project, service, containers); canary != nil {
+		return fmt.Errorf("canário da versão %s em andamento para '%s': use 'oi promote' ou 'oi abort'", shortVersion(canary.Version), labelOf(project, service))
+	}
+
+	// 1. Descobrir a versão atual (registrada ou, na falta dela, a que está rodando)
+	currentVersion := ""
+	if active := o.findActive(ctx, project, service, containers); active != nil {
+		currentVersion = active.Version
+	}
+
+	// 2. Escolher a versão alvo entre as retidas
+	target, err := selectRollbackTarget(containers, currentVersion, toVersion)
+	if err != nil {
+		return err
+	}
+
+	record.Version = target.Version
+	record.Image = target.Image
+
+	fmt.Printf("⏪ Rollback de '%s': %s -> %s\n", project, shortVersion(currentVersion), shortVersion(target.Version))
+
+	// 3. Iniciar todas as réplicas da versão alvo e aguardar healthy
+	replicas := filterVersion(containers, target.Version)
+	for _, c := range replicas {
+		fmt.Printf("▶️  Iniciando %s...\n", c.Name)
+		if err := o.runtime.Start(ctx, c.ID); err != nil {
+			o.stopAll(ctx, replicas)
+			return fmt.Errorf("falha ao iniciar container: %w", err)
+		}
+	}
+
+	// O health check é o gravado no deploy da versão alvo (o oi.json atual pode ter mudado)
+	hc := target.HealthCheck
+	maxWait := hc.MaxWait()
+	fmt.Printf("💓 Aguardando health check (max %s)...\n", maxWait)
+	started := make([]domain.Container, 0, len(replicas))
+	for _, c := range replicas {
+		if err := o.runtime.WaitHealthy(ctx, c.ID, maxWait); err != nil {
+			return o.abortRollback(ctx, project, replicas, err)
+		}
+
+		ctr, err := o.runtime.Inspect(ctx, c.ID)
+		if err != nil {
+			o.stopAll(ctx, replicas)
+			return fmt.Errorf("falha ao inspecionar container: %w", err)
+		}
+		started = append(started, *ctr)
+	}
+
+	// Mesmo health check HTTP do 'oi up', antes de devolver o tráfego à versão alvo
+	if hc != nil {
+		fmt.Printf("🩺 Verificando %s (esperado %d)...\n", hc.PathOrDefault(), hc.StatusOrDefault())
+		for i := range started {
+			if err := o.probeHealth(ctx, &started[i], started[i].InternalPort(), hc); err != nil {
+				return o.abortRollback(ctx, project, replicas, err)
+			}
+		}
+	}
+	record.ImageDigest = started[0].DeployedDigest()
+
+	// 4. Apontar o proxy para as réplicas da versão alvo
+	if routes := deployedRoutes(started[0], started); o.proxy != nil && len(routes) > 0 {
+		fmt.Printf("🔀 Configurando proxy para %s...\n", routesLabel(routes))
+		// Endereços que só a versão atual tinha deixam de ser roteados
+		var previous []domain.Route
+		if current := filterVersion(containers, currentVersion); currentVersion != "" && len(current) > 0 {
+			previous = deployedRoutes(current[0], current)
+		}
+		if err := applyRoutes(ctx, o.proxy, routes, previous); err != nil {
+			fmt.Printf("⚠️  Aviso: falha ao configurar proxy: %v\n", err)
+		}
+	}
+
+	// 5. Parar a versão atual (fica retida para um eventual rollforward)
+	for _, c := range containers {
+		if c.Version != target.Version && c.IsRunning() {
+			fmt.Printf("🛑 Parando %s...\n", c.Name)
+			o.runtime.Stop(ctx, c.ID, 30*time.Second)
+		}
+	}
+
+	o.setActiveVersion(ctx, project, service, target.Version)
+
+	fmt.Printf("✅ Rollback completo! Versão ativa: %s\n", shortVersion(target.Version))
+	return nil
+}
+
+// abortRollback para a versão alvo que não ficou saudável; a versão atual segue no ar
+func (o *Orchestrator) abortRollback(ctx context.Context, project string, replicas []domain.Container, err error) error {
+	fmt.Printf("❌ Versão alvo não ficou saudável, mantendo a atual...\n")
+	o.stopAll(ctx, replicas)
+	return domain.ErrDeployFailed{
+		Project: project,
+		Reason:  fmt.Sprintf("rollback falhou: %v", err),
+	}
+}
+
+// stopAll para os containers informados (usado ao desistir de um rollback)
+func (o *Orchestrator) stopAll(ctx context.Context, containers []domain.Container) {
+	for _, c := range containers {
+		o.runtime.Stop(ctx, c.ID, 10*time.Second)
+	}
+}
+
+// selectRollbackTarget escolhe o container alvo do rollback
+// toVersion aceita um prefixo da versão (ex: os 8 caracteres exibidos em 'oi status')
+func selectRollbackTarget(containers []domain.Container, currentVersion, toVersion string) (*domain.Container, error) {
+	candidates := make([]domain.Container, 0, len(containers))
+	for _, c := range sortedByNewest(containers) {
+		if c.Version != currentVersion {
+			candidates = append(candidates, c)
+		}
+	}
+
+	if toVersion == "" {
+		if len(candidates) == 0 {
+			return nil, fmt.Errorf("nenhuma versão anterior retida para rollback")
+		}
+		return &candidates[0], nil
+	}
+
+	if strings.HasPrefix(currentVersion, toVersion) {
+		return nil, fmt.Errorf("versão %s já está ativa", toVersion)
+	}
+
+	var match *domain.Container
+	for i := range candidates {
+		if strings.HasPrefix(candidates[i].Version, toVersion) {
+			if match != nil && match.Version != candidates[i].Version {
+				return nil, fmt.Errorf("versão %s é ambígua, use mais caracteres", toVersion)
+			}
+			if match == nil {
+				match = &candidates[i]
+			}
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("versão %s não encontrada entre as versões retidas", toVersion)
+	}
+	return match, nil
+}
+
+// shortVersion retorna os 8 primeiros caracteres da versão, como exibido ao usuário
+func shortVersion(version string) string {
+	if version == "" {
+		return "?"
+	}
+	if len(version) > 8 {
+		return version[:8]
+	}
+	return version
+}
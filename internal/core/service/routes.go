@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+	"github.com/crom-tech/oi/internal/core/port"
+)
+
+// routesFor monta as rotas dos endereços da intenção apontando para os containers
+func routesFor(intent domain.Intent, containers []domain.Container) []domain.Route {
+	return domain.RoutesFor(intent.Sites(), intent.RemoverPrefixo, intent.Balanceamento, containers)
+}
+
+// deployedRoutes monta as rotas com os endereços gravados no deploy do container c
+// (labels), apontando para os containers informados
+func deployedRoutes(c domain.Container, containers []domain.Container) []domain.Route {
+	return domain.RoutesFor(c.Sites(), c.StripPrefix, c.Policy, containers)
+}
+
+// activeRoutes retorna as rotas publicadas pela versão ativa do serviço (nil se não houver)
+// containers deve conter apenas containers do serviço
+func (o *Orchestrator) activeRoutes(ctx context.Context, project, service string, containers []domain.Container) []domain.Route {
+	active := o.findActive(ctx, project, service, containers)
+	if active == nil {
+		return nil
+	}
+	return deployedRoutes(*active, []domain.Container{*active})
+}
+
+// applyRoutes grava as rotas no proxy e remove as de previous que deixaram de existir
+// (ex: prefixo retirado de dominios); para na primeira falha
+func applyRoutes(ctx context.Context, proxy port.ProxyManager, routes []domain.Route, previous []domain.Route) error {
+	keep := make(map[string]bool, len(routes))
+	for _, r := range routes {
+		if err := proxy.AddRoute(ctx, r); err != nil {
+			return err
+		}
+		keep[r.ID] = true
+	}
+	for _, r := range previous {
+		if keep[r.ID] {
+			continue
+		}
+		if err := proxy.RemoveRoute(ctx, r); err != nil {
+			return fmt.Errorf("falha ao remover rota de %s: %w", r.Label(), err)
+		}
+	}
+	return nil
+}
+
+// routesLabel retorna os endereços das rotas para exibição
+func routesLabel(routes []domain.Route) string {
+	labels := make([]string, 0, len(routes))
+	for _, r := range routes {
+		labels = append(labels, r.Label())
+	}
+	return strings.Join(labels, ", ")
+}
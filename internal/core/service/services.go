@@ -0,0 +1,162 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// serviceKey identifica um serviço de um projeto
+type serviceKey struct {
+	project string
+	service string
+}
+
+// keyOf retorna o serviço ao qual o container pertence
+func keyOf(c domain.Container) serviceKey {
+	return serviceKey{project: c.Project, service: c.Service}
+}
+
+// upProject implanta um projeto com vários serviços em ordem de dependência
+// Cada serviço só é implantado depois que suas dependências estão saudáveis;
+// se um serviço falhar, os que dependem dele não são tocados
+func (o *Orchestrator) upProject(ctx context.Context, intent domain.Intent, opts UpOptions) error {
+	services, err := intent.ServicesInOrder()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(services))
+	for _, svc := range services {
+		names = append(names, svc.Servico)
+	}
+	fmt.Printf("🧩 Projeto '%s' com %d serviço(s): %s\n", intent.Nome, len(services), strings.Join(names, " → "))
+
+	for _, svc := range services {
+		fmt.Printf("\n🔹 Serviço '%s'\n", svc.Servico)
+		if err := o.upService(ctx, svc, opts); err != nil {
+			return fmt.Errorf("serviço '%s' falhou, dependentes não foram atualizados: %w", svc.Servico, err)
+		}
+	}
+
+	o.warnOrphans(ctx, intent)
+	return nil
+}
+
+// warnOrphans avisa sobre containers de serviços que não estão mais na intenção
+// Eles não são removidos automaticamente: use 'oi down' ou remova manualmente
+func (o *Orchestrator) warnOrphans(ctx context.Context, intent domain.Intent) {
+	containers, err := o.runtime.List(ctx, intent.Nome)
+	if err != nil {
+		return
+	}
+	for _, c := range containers {
+		if _, ok := intent.Servicos[c.Service]; !ok && c.IsRunning() {
+			fmt.Printf("⚠️  Container %s pertence a um serviço fora do oi.json ('%s')\n", c.Name, c.Service)
+		}
+	}
+}
+
+// filterService retorna os containers de um serviço
+func filterService(containers []domain.Container, service string) []domain.Container {
+	result := make([]domain.Container, 0, len(containers))
+	for _, c := range containers {
+		if c.Service == service {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// uniqueServices retorna os serviços distintos de uma lista de containers
+func uniqueServices(containers []domain.Container) []serviceKey {
+	seen := make(map[serviceKey]bool)
+	var keys []serviceKey
+	for _, c := range containers {
+		key := keyOf(c)
+		if c.Project != "" && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// multiLogs acompanha os logs de vários serviços ao mesmo tempo, prefixando cada linha
+func (o *Orchestrator) multiLogs(ctx context.Context, containers []domain.Container, keys []serviceKey, stdout, stderr io.Writer, follow bool, tail string) error {
+	width := 0
+	for _, key := range keys {
+		width = max(width, len(key.service))
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	errs := make(chan error, len(keys))
+
+	fmt.Printf("📜 Exibindo logs de %d serviço(s)...\n", len(keys))
+	for _, key := range keys {
+		target := logTarget(filterService(containers, key.service))
+		prefix := fmt.Sprintf("%-*s | ", width, key.service)
+
+		out := &prefixWriter{w: stdout, prefix: prefix, mu: &mu}
+		errOut := &prefixWriter{w: stderr, prefix: prefix, mu: &mu}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := o.runtime.Logs(ctx, target.ID, out, errOut, follow, tail); err != nil {
+				errs <- fmt.Errorf("%s: %w", target.Name, err)
+			}
+			out.Flush()
+			errOut.Flush()
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
+
+// prefixWriter escreve cada linha completa com um prefixo
+// O mutex é compartilhado entre writers para não intercalar linhas
+type prefixWriter struct {
+	w      io.Writer
+	prefix string
+	mu     *sync.Mutex
+	buf    []byte
+}
+
+func (p *prefixWriter) Write(data []byte) (int, error) {
+	p.buf = append(p.buf, data...)
+	for {
+		i := bytes.IndexByte(p.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if err := p.writeLine(p.buf[:i+1]); err != nil {
+			return 0, err
+		}
+		p.buf = p.buf[i+1:]
+	}
+	return len(data), nil
+}
+
+// Flush escreve o que restou no buffer (última linha sem quebra)
+func (p *prefixWriter) Flush() {
+	if len(p.buf) > 0 {
+		p.writeLine(append(p.buf, '\n'))
+		p.buf = nil
+	}
+}
+
+func (p *prefixWriter) writeLine(line []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err := fmt.Fprintf(p.w, "%s%s", p.prefix, line)
+	return err
+}
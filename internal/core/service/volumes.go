@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/crom-tech/oi/internal/core/domain"
+)
+
+// ensureVolumes cria (se necessário) os volumes nomeados declarados na intenção
+func (o *Orchestrator) ensureVolumes(ctx context.Context, intent domain.Intent) error {
+	if len(intent.Volumes) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(intent.Volumes))
+	for name := range intent.Volumes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Printf("💾 Criando/verificando %d volume(s)...\n", len(names))
+	for _, name := range names {
+		if _, err := o.runtime.EnsureVolume(ctx, intent.Nome, name); err != nil {
+			return fmt.Errorf("falha ao criar volume '%s': %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Volumes lista os volumes nomeados gerenciados pelo OI (de um projeto ou de todos)
+func (o *Orchestrator) Volumes(ctx context.Context, project string) ([]domain.Volume, error) {
+	volumes, err := o.runtime.ListVolumes(ctx, project)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar volumes: %w", err)
+	}
+	return volumes, nil
+}
+
+// RemoveVolumes remove volumes nomeados de um projeto
+// names aceita tanto o nome declarado no oi.json ("pgdata") quanto o nome real ("oi-loja.pgdata")
+func (o *Orchestrator) RemoveVolumes(ctx context.Context, project string, names []string) error {
+	volumes, err := o.runtime.ListVolumes(ctx, project)
+	if err != nil {
+		return fmt.Errorf("falha ao listar volumes: %w", err)
+	}
+
+	var failed []string
+	for _, name := range names {
+		vol := findVolume(volumes, name, project != "")
+		if vol == nil {
+			fmt.Printf("⚠️  Volume '%s' não encontrado\n", name)
+			failed = append(failed, name)
+			continue
+		}
+
+		fmt.Printf("🗑️  Removendo volume %s...\n", vol.Name)
+		if err := o.runtime.RemoveVolume(ctx, vol.Name); err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+			failed = append(failed, name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("falha ao remover %d volume(s): %v", len(failed), failed)
+	}
+	return nil
+}
+
+// removeProjectVolumes remove todos os volumes de um projeto (ou de todos se project == "")
+// Erros são apenas avisos, como no restante do 'oi down'
+func (o *Orchestrator) removeProjectVolumes(ctx context.Context, project string) {
+	volumes, err := o.runtime.ListVolumes(ctx, project)
+	if err != nil {
+		fmt.Printf("⚠️  Aviso: falha ao listar volumes: %v\n", err)
+		return
+	}
+
+	for _, vol := range volumes {
+		fmt.Printf("💾 Removendo volume %s...\n", vol.Name)
+		if err := o.runtime.RemoveVolume(ctx, vol.Name); err != nil {
+			fmt.Printf("⚠️  Aviso: %v\n", err)
+		}
+	}
+}
+
+// findVolume procura um volume pelo nome real ou, com byKey, pelo nome declarado
+// Sem projeto definido o nome declarado é ambíguo, então apenas o nome real é aceito
+func findVolume(volumes []domain.Volume, name string, byKey bool) *domain.Volume {
+	for i := range volumes {
+		if volumes[i].Name == name || (byKey && volumes[i].Key == name) {
+			return &volumes[i]
+		}
+	}
+	return nil
+}
@@ -5,20 +5,34 @@ const (
 	Prefix  = "io.oi."
 	Managed = Prefix + "managed"
 	Project = Prefix + "project"
+	Service = Prefix + "service"
 	Version = Prefix + "version"
 	Domain  = Prefix + "domain"
 	Port    = Prefix + "port"
+	Volume  = Prefix + "volume"
+	Replica = Prefix + "replica"
+	Policy  = Prefix + "lb-policy"
+	Digest  = Prefix + "digest"
+	// StripPrefix marca serviços cujo prefixo de caminho é removido antes do container
+	StripPrefix = Prefix + "strip-prefix"
+	// HealthCheck guarda o bloco healthcheck do deploy (JSON), usado no rollback
+	HealthCheck = Prefix + "healthcheck"
 )
 
 // OILabels retorna o conjunto de labels padrão para um container OI
-func OILabels(project, version, domain string, port int) map[string]string {
-	return map[string]string{
+// service é omitido em projetos de serviço único
+func OILabels(project, service, version, domain string, port int) map[string]string {
+	l := map[string]string{
 		Managed: "true",
 		Project: project,
 		Version: version,
 		Domain:  domain,
 		Port:    itoa(port),
 	}
+	if service != "" {
+		l[Service] = service
+	}
+	return l
 }
 
 // ManagedFilter retorna o filtro para listar containers gerenciados
@@ -31,6 +45,36 @@ func ProjectFilter(projectName string) string {
 	return Project + "=" + projectName
 }
 
+// ParsePort converte o valor do label de porta para int (0 se inválido)
+func ParsePort(value string) int {
+	return parseUint(value)
+}
+
+// ParseReplica converte o valor do label de réplica para int
+// Containers sem o label (anteriores às réplicas) são a réplica 1
+func ParseReplica(value string) int {
+	if replica := parseUint(value); replica > 0 {
+		return replica
+	}
+	return 1
+}
+
+// ReplicaValue formata o índice da réplica para o label
+func ReplicaValue(replica int) string {
+	return itoa(replica)
+}
+
+func parseUint(value string) int {
+	n := 0
+	for _, ch := range value {
+		if ch < '0' || ch > '9' {
+			return 0
+		}
+		n = n*10 + int(ch-'0')
+	}
+	return n
+}
+
 func itoa(i int) string {
 	if i == 0 {
 		return "0"
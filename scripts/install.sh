@@ -243,7 +243,7 @@ check_dependencies() {
     if command -v caddy &> /dev/null; then
         log_success "Caddy instalado: $(caddy version 2>/dev/null | head -1 || echo 'versão desconhecida')"
     else
-        log_info "Caddy não instalado (opcional, use --no-caddy para deploy local)"
+        log_info "Caddy não instalado (opcional, use --no-proxy para deploy local)"
     fi
     
     if [[ ${#missing[@]} -gt 0 ]]; then
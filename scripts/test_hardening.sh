@@ -102,9 +102,9 @@ cat > oi.json << 'EOF'
 EOF
 
 echo "📄 oi.json atualizado com domínio .localhost"
-echo "🚀 Executando: oi up --no-caddy"
+echo "🚀 Executando: oi up --no-proxy"
 
-OUTPUT=$($OI_BIN up --no-caddy 2>&1 || true)
+OUTPUT=$($OI_BIN up --no-proxy 2>&1 || true)
 echo "$OUTPUT" | head -10
 
 if echo "$OUTPUT" | grep -qi "não aponta para este servidor"; then
@@ -136,7 +136,7 @@ else
     CADDY_RUNNING=false
 fi
 
-# Testar com Caddy (sem --no-caddy)
+# Testar com Caddy (sem --no-proxy)
 cat > oi.json << 'EOF'
 {
   "nome": "test-hardening",
@@ -273,7 +273,7 @@ EOF
     local up_start up_duration
     up_start=$(date +%s)
     
-    if $OI_BIN up --no-caddy 2>&1 | grep -q "Deploy completo"; then
+    if $OI_BIN up --no-proxy 2>&1 | grep -q "Deploy completo"; then
         up_duration=$(elapsed_time $up_start)
         log_success "Deploy realizado em ${up_duration}s"
     else
@@ -305,7 +305,7 @@ EOF
     
     # 7. Down
     log_section "1.6" "Testando 'oi down'..."
-    if $OI_BIN down --no-caddy 2>&1 | grep -q "removido com sucesso"; then
+    if $OI_BIN down --no-proxy 2>&1 | grep -q "removido com sucesso"; then
         log_success "Projeto removido"
     else
         log_fail "Falha ao remover projeto"
@@ -345,7 +345,7 @@ test_labels_metadata() {
 }
 EOF
     
-    $OI_BIN up --no-caddy > /dev/null 2>&1
+    $OI_BIN up --no-proxy > /dev/null 2>&1
     sleep 2
     
     local container_id
@@ -408,7 +408,7 @@ EOF
     fi
     
     # Cleanup
-    $OI_BIN down --no-caddy > /dev/null 2>&1 || true
+    $OI_BIN down --no-proxy > /dev/null 2>&1 || true
 }
 
 # =============================================================================
@@ -433,7 +433,7 @@ test_network_isolation() {
 }
 EOF
     
-    $OI_BIN up --no-caddy > /dev/null 2>&1
+    $OI_BIN up --no-proxy > /dev/null 2>&1
     sleep 2
     
     # 1. Verificar network criada
@@ -472,7 +472,7 @@ EOF
     
     # 4. Cleanup e verificar remoção da network
     log_section "3.4" "Verificando remoção da network após down..."
-    $OI_BIN down --no-caddy > /dev/null 2>&1 || true
+    $OI_BIN down --no-proxy > /dev/null 2>&1 || true
     sleep 1
     
     if docker network ls --format '{{.Name}}' | grep -q "^${network_name}$"; then
@@ -506,7 +506,7 @@ EOF
     
     # 1. Primeiro deploy
     log_section "4.1" "Primeiro deploy..."
-    $OI_BIN up --no-caddy > /dev/null 2>&1
+    $OI_BIN up --no-proxy > /dev/null 2>&1
     sleep 2
     
     local first_container
@@ -524,7 +524,7 @@ EOF
     # 2. Segundo deploy (Blue-Green)
     log_section "4.2" "Segundo deploy (Blue-Green)..."
     sleep 1
-    $OI_BIN up --no-caddy > /dev/null 2>&1
+    $OI_BIN up --no-proxy > /dev/null 2>&1
     sleep 2
     
     local second_container
@@ -558,7 +558,7 @@ EOF
     fi
     
     # Cleanup
-    $OI_BIN down --no-caddy > /dev/null 2>&1 || true
+    $OI_BIN down --no-proxy > /dev/null 2>&1 || true
 }
 
 # =============================================================================
@@ -584,7 +584,7 @@ test_multiple_projects() {
   "recursos": {"cpu": "0.1", "memoria": "64mb"}
 }
 EOF
-    $OI_BIN up --no-caddy > /dev/null 2>&1
+    $OI_BIN up --no-proxy > /dev/null 2>&1
     
     if docker ps --filter "label=io.oi.project=$PROJECT_BASIC" --format '{{.ID}}' | grep -q .; then
         log_success "Projeto A deployado"
@@ -605,7 +605,7 @@ EOF
   "recursos": {"cpu": "0.1", "memoria": "64mb"}
 }
 EOF
-    $OI_BIN up --no-caddy > /dev/null 2>&1
+    $OI_BIN up --no-proxy > /dev/null 2>&1
     
     if docker ps --filter "label=io.oi.project=$PROJECT_UPDATE" --format '{{.ID}}' | grep -q .; then
         log_success "Projeto B deployado"
@@ -626,7 +626,7 @@ EOF
     
     # 4. Down de apenas um projeto
     log_section "5.4" "Down seletivo de um projeto..."
-    $OI_BIN down --no-caddy -p "$PROJECT_BASIC" > /dev/null 2>&1
+    $OI_BIN down --no-proxy -p "$PROJECT_BASIC" > /dev/null 2>&1
     
     local remaining
     remaining=$(docker ps --filter "label=io.oi.managed=true" --format '{{.ID}}' | wc -l)
@@ -638,7 +638,7 @@ EOF
     fi
     
     # Cleanup
-    $OI_BIN down --no-caddy -p "$PROJECT_UPDATE" > /dev/null 2>&1 || true
+    $OI_BIN down --no-proxy -p "$PROJECT_UPDATE" > /dev/null 2>&1 || true
 }
 
 # =============================================================================
@@ -654,7 +654,7 @@ test_edge_cases() {
     
     # 1. Down de projeto inexistente
     log_section "6.1" "Down de projeto inexistente..."
-    if $OI_BIN down --no-caddy -p "projeto-que-nao-existe" 2>&1 | grep -q "Nenhum container"; then
+    if $OI_BIN down --no-proxy -p "projeto-que-nao-existe" 2>&1 | grep -q "Nenhum container"; then
         log_success "Trata graciosamente projeto inexistente"
     else
         log_fail "Erro ao tratar projeto inexistente"
@@ -692,7 +692,7 @@ test_edge_cases() {
     log_section "6.4" "Validação de oi.json inválido..."
     echo '{"nome": ""}' > oi.json
     local output
-    output=$($OI_BIN up --no-caddy 2>&1) || true
+    output=$($OI_BIN up --no-proxy 2>&1) || true
     if echo "$output" | grep -qi "ausente\|missing\|erro\|error\|inválid\|invalid"; then
         log_success "Rejeita oi.json inválido"
     else